@@ -0,0 +1,230 @@
+// Package cli implements dfcron, the command-line client of Docker Flow Cron.
+package cli
+
+import (
+	"../client"
+	"errors"
+	"flag"
+	"fmt"
+	"golang.org/x/net/context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultAddress = "http://localhost:8080"
+
+// requestTimeout limits the time of requests other than following logs.
+var requestTimeout = 30 * time.Second
+
+type command struct {
+	usage       string
+	description string
+	run         func(c *CLI, usage string, args []string) error
+}
+
+var commands = map[string]command{
+	"ls":      {"ls", "List jobs", (*CLI).list},
+	"inspect": {"inspect JOB", "Show the definition and the state of a job", (*CLI).inspect},
+	"create":  {"create -f FILE", "Create or update the jobs defined in a YAML or JSON file", (*CLI).create},
+	"rm":      {"rm JOB...", "Remove jobs", (*CLI).remove},
+	"run":     {"run JOB", "Run a job now, outside of its schedule", (*CLI).run},
+	"suspend": {"suspend JOB", "Stop scheduling a job until it is resumed", (*CLI).suspend},
+	"resume":  {"resume JOB", "Schedule a suspended job again", (*CLI).resume},
+	"logs":    {"logs [-f] [--tail N] JOB", "Show the logs of the service of a job", (*CLI).logs},
+	"history": {"history [--limit N] JOB", "List the latest runs of a job", (*CLI).history},
+	"plan":    {"plan [-f FILE]", "Show the changes needed to match the jobs file", (*CLI).plan},
+	"apply":   {"apply [-f FILE]", "Create, update and delete jobs to match the jobs file", (*CLI).apply},
+	"context": {"context ls|use|add|rm", "Manage the instances dfcron connects to", (*CLI).context},
+}
+
+// usageError is returned when a command is called with invalid arguments.
+type usageError struct {
+	message string
+	usage   string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// CLI holds the global options of dfcron.
+type CLI struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	configFile  string
+	contextName string
+	address     string
+	output      string
+}
+
+// Run executes the command in the arguments and returns the exit code:
+// 0 on success, 1 when the command failed and 2 when the arguments are invalid.
+func Run(args []string, stdout, stderr io.Writer) int {
+	c := &CLI{
+		Stdout:      stdout,
+		Stderr:      stderr,
+		configFile:  defaultConfigFile(),
+		contextName: os.Getenv("DFCRON_CONTEXT"),
+		address:     os.Getenv("DFCRON_ADDRESS"),
+		output:      OutputTable,
+	}
+	fs := c.flagSet("dfcron", "COMMAND")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		if fs.NArg() > 1 {
+			if cmd, ok := commands[fs.Arg(1)]; ok {
+				fmt.Fprintf(stdout, "Usage: dfcron %s\n\n%s\n", cmd.usage, cmd.description)
+				return 0
+			}
+		}
+		c.usage(stdout)
+		if fs.NArg() == 0 {
+			return 2
+		}
+		return 0
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unknown command %s\n\n", fs.Arg(0))
+		c.usage(stderr)
+		return 2
+	}
+	err := cmd.run(c, cmd.usage, fs.Args()[1:])
+	if err == flag.ErrHelp {
+		return 0
+	} else if usageErr, ok := err.(*usageError); ok {
+		if len(usageErr.message) > 0 {
+			fmt.Fprintf(stderr, "Error: %s\n", usageErr.message)
+		}
+		fmt.Fprintf(stderr, "Usage: dfcron %s\n", usageErr.usage)
+		return 2
+	} else if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+func (c *CLI) usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: dfcron [OPTIONS] COMMAND")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s%s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Options:")
+	fmt.Fprintln(w, "  --context NAME     Context to use instead of the current one (DFCRON_CONTEXT)")
+	fmt.Fprintln(w, "  --address URL      Address of the instance, overrides the context (DFCRON_ADDRESS)")
+	fmt.Fprintln(w, "  --config FILE      Configuration file (DFCRON_CONFIG, default ~/.dfcron/config.yml)")
+	fmt.Fprintln(w, "  -o, --output FMT   Output format: table, json or yaml")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'dfcron help COMMAND' for more information on a command.")
+}
+
+// flagSet returns the flags of a command. Global options are accepted by every command.
+func (c *CLI) flagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.Stderr, "Usage: dfcron %s\n", usage)
+	}
+	fs.StringVar(&c.configFile, "config", c.configFile, "")
+	fs.StringVar(&c.contextName, "context", c.contextName, "")
+	fs.StringVar(&c.address, "address", c.address, "")
+	fs.StringVar(&c.output, "output", c.output, "")
+	fs.StringVar(&c.output, "o", c.output, "")
+	return fs
+}
+
+// parse parses the flags of a command and returns its positional arguments.
+// Unlike flag.Parse, flags can follow positional arguments.
+func (c *CLI) parse(fs *flag.FlagSet, usage string, args []string, min, max int) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err == flag.ErrHelp {
+			return nil, err
+		} else if err != nil {
+			return nil, &usageError{usage: usage}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if err := validateOutput(c.output); err != nil {
+		return nil, &usageError{message: err.Error(), usage: usage}
+	}
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		return nil, &usageError{message: "wrong number of arguments", usage: usage}
+	}
+	return positional, nil
+}
+
+// connection returns the context selected with --context, DFCRON_CONTEXT or the current context of the configuration.
+// The address can be overridden with --address. Without contexts, dfcron connects to localhost.
+func (c *CLI) connection() (Context, error) {
+	connection := Context{Address: defaultAddress}
+	config, err := LoadConfig(c.configFile)
+	if err != nil {
+		return connection, fmt.Errorf("could not read %s: %s", c.configFile, err.Error())
+	}
+	name := c.contextName
+	if len(name) == 0 {
+		name = config.CurrentContext
+	}
+	if len(name) > 0 {
+		found, ok := config.Contexts[name]
+		if !ok {
+			return connection, fmt.Errorf("context %s does not exist", name)
+		}
+		connection = found
+	}
+	if len(c.address) > 0 {
+		connection.Address = c.address
+	}
+	if token := os.Getenv("DFCRON_TOKEN"); len(token) > 0 {
+		connection.Token = token
+	}
+	if len(connection.Address) == 0 {
+		return connection, errors.New("the address of the instance is not set")
+	}
+	if !strings.Contains(connection.Address, "://") {
+		connection.Address = "http://" + connection.Address
+	}
+	return connection, nil
+}
+
+func (c *CLI) client() (*client.Client, error) {
+	connection, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(connection.Address, client.Options{
+		Token:              connection.Token,
+		CAFile:             connection.CAFile,
+		CertFile:           connection.CertFile,
+		KeyFile:            connection.KeyFile,
+		InsecureSkipVerify: connection.Insecure,
+	})
+}
+
+// requestContext limits the time of a request.
+func requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestTimeout)
+}
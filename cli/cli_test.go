@@ -0,0 +1,336 @@
+package cli
+
+import (
+	"../cron"
+	"../server"
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type CLITestSuite struct {
+	suite.Suite
+	Server     *httptest.Server
+	Responses  map[string]interface{}
+	Requests   []string
+	Bodies     []string
+	Dir        string
+	ConfigFile string
+}
+
+func TestCLIUnitTestSuite(t *testing.T) {
+	s := new(CLITestSuite)
+	suite.Run(t, s)
+}
+
+func (s *CLITestSuite) SetupTest() {
+	s.Responses = map[string]interface{}{}
+	s.Requests = []string{}
+	s.Bodies = []string{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := req.Method + " " + strings.TrimPrefix(req.URL.Path, "/v1/docker-flow-cron")
+		s.Requests = append(s.Requests, key)
+		body, _ := ioutil.ReadAll(req.Body)
+		s.Bodies = append(s.Bodies, string(body))
+		response, ok := s.Responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			js, _ := json.Marshal(server.Response{Status: "NOK", Message: "not found"})
+			w.Write(js)
+			return
+		}
+		if text, ok := response.(string); ok {
+			w.Write([]byte(text))
+			return
+		}
+		js, _ := json.Marshal(response)
+		w.Write(js)
+	}))
+	s.Dir, _ = ioutil.TempDir("", "dfcron")
+	s.ConfigFile = filepath.Join(s.Dir, "config.yml")
+	Config{
+		CurrentContext: "test",
+		Contexts:       map[string]Context{"test": {Address: s.Server.URL}},
+	}.Save(s.ConfigFile)
+}
+
+func (s *CLITestSuite) TearDownTest() {
+	s.Server.Close()
+	os.RemoveAll(s.Dir)
+}
+
+// Run
+
+func (s *CLITestSuite) Test_Run_PrintsUsage_WhenCommandIsMissing() {
+	stdout, _, code := s.run()
+
+	s.Equal(2, code)
+	s.Contains(stdout, "Usage: dfcron [OPTIONS] COMMAND")
+	s.Contains(stdout, "history")
+}
+
+func (s *CLITestSuite) Test_Run_ReturnsUsageError_WhenCommandIsUnknown() {
+	_, stderr, code := s.run("deploy")
+
+	s.Equal(2, code)
+	s.Contains(stderr, "unknown command deploy")
+}
+
+func (s *CLITestSuite) Test_Run_ReturnsUsageError_WhenOutputIsInvalid() {
+	_, stderr, code := s.run("ls", "-o", "xml")
+
+	s.Equal(2, code)
+	s.Contains(stderr, "output must be table, json or yaml")
+}
+
+func (s *CLITestSuite) Test_Run_ReturnsError_WhenRequestFails() {
+	_, stderr, code := s.run("inspect", "my-job")
+
+	s.Equal(1, code)
+	s.Equal("Error: not found (404)\n", stderr)
+}
+
+func (s *CLITestSuite) Test_Run_UsesAddress() {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, _ := json.Marshal(server.Response{Status: "OK", Jobs: map[string]cron.JobData{"other-job": {Name: "other-job"}}})
+		w.Write(js)
+	}))
+	defer other.Close()
+
+	stdout, _, code := s.run("--address", other.URL, "ls")
+
+	s.Equal(0, code)
+	s.Contains(stdout, "other-job")
+	s.Empty(s.Requests)
+}
+
+func (s *CLITestSuite) Test_Run_ReturnsError_WhenContextDoesNotExist() {
+	_, stderr, code := s.run("ls", "--context", "production")
+
+	s.Equal(1, code)
+	s.Contains(stderr, "context production does not exist")
+}
+
+// ls
+
+func (s *CLITestSuite) Test_Ls_PrintsTable() {
+	next := time.Date(2017, 5, 6, 3, 0, 0, 0, time.Local)
+	s.Responses["GET /job"] = server.Response{Status: "OK", Jobs: map[string]cron.JobData{
+		"backup":  {Name: "backup", Image: "alpine", Schedule: "@daily", State: &cron.JobState{Status: cron.JobScheduled, NextRun: &next}},
+		"reindex": {Name: "reindex", Schedule: "@hourly", Type: cron.TypeHTTP, HTTP: &cron.HTTPRequest{Method: "post", URL: "http://search/reindex"}},
+	}}
+
+	stdout, _, code := s.run("ls")
+
+	s.Equal(0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	s.Len(lines, 3)
+	s.Regexp(`^NAME\s+TYPE\s+SCHEDULE\s+TARGET\s+STATUS\s+NEXT RUN\s+LAST RUN$`, lines[0])
+	s.Regexp(`^backup\s+service\s+@daily\s+alpine\s+Scheduled\s+2017-05-06 03:00:00\s+-$`, lines[1])
+	s.Regexp(`^reindex\s+http\s+@hourly\s+POST http://search/reindex\s+-\s+-\s+-$`, lines[2])
+}
+
+func (s *CLITestSuite) Test_Ls_PrintsJSON() {
+	s.Responses["GET /job"] = server.Response{Status: "OK", Jobs: map[string]cron.JobData{
+		"backup": {Name: "backup", Image: "alpine", Schedule: "@daily"},
+	}}
+
+	stdout, _, code := s.run("ls", "-o", "json")
+
+	s.Equal(0, code)
+	actual := []cron.JobData{}
+	s.NoError(json.Unmarshal([]byte(stdout), &actual))
+	s.Equal("backup", actual[0].Name)
+}
+
+func (s *CLITestSuite) Test_Ls_PrintsYAML() {
+	s.Responses["GET /job"] = server.Response{Status: "OK", Jobs: map[string]cron.JobData{
+		"backup": {Name: "backup", Image: "alpine", Schedule: "@daily"},
+	}}
+
+	stdout, _, code := s.run("-o", "yaml", "ls")
+
+	s.Equal(0, code)
+	s.True(strings.HasPrefix(stdout, "- "))
+	s.Contains(stdout, "  image: alpine\n")
+	s.Contains(stdout, "  name: backup\n")
+}
+
+// inspect
+
+func (s *CLITestSuite) Test_Inspect_PrintsJobAsYAML() {
+	s.Responses["GET /job/backup"] = server.ResponseDetails{Status: "OK", Job: cron.JobData{Name: "backup", Image: "alpine", Schedule: "@daily"}}
+
+	stdout, _, code := s.run("inspect", "backup")
+
+	s.Equal(0, code)
+	s.Contains(stdout, "name: backup\n")
+	s.Contains(stdout, "schedule: '@daily'\n")
+}
+
+// create
+
+func (s *CLITestSuite) Test_Create_PutsJobsInFile() {
+	file := filepath.Join(s.Dir, "jobs.yml")
+	ioutil.WriteFile(file, []byte("jobs:\n- name: backup\n  image: alpine\n  schedule: '@daily'\n- name: cleanup\n  image: alpine\n  schedule: '@hourly'\n"), 0600)
+	s.Responses["PUT /job/backup"] = server.ResponseDetails{Status: "OK"}
+	s.Responses["PUT /job/cleanup"] = server.ResponseDetails{Status: "OK"}
+
+	stdout, _, code := s.run("create", "-f", file)
+
+	s.Equal(0, code)
+	s.Equal("Job backup was created\nJob cleanup was created\n", stdout)
+	s.Equal([]string{"PUT /job/backup", "PUT /job/cleanup"}, s.Requests)
+	s.Contains(s.Bodies[0], `"image":"alpine"`)
+}
+
+func (s *CLITestSuite) Test_Create_ReturnsUsageError_WhenFileIsMissing() {
+	_, stderr, code := s.run("create")
+
+	s.Equal(2, code)
+	s.Contains(stderr, "the file is mandatory")
+}
+
+// rm
+
+func (s *CLITestSuite) Test_Rm_RemovesJobs() {
+	s.Responses["DELETE /job/backup"] = server.ResponseDetails{Status: "OK"}
+
+	stdout, stderr, code := s.run("rm", "backup", "cleanup")
+
+	s.Equal(1, code)
+	s.Equal("Job backup was removed\n", stdout)
+	s.Contains(stderr, "Job cleanup could not be removed: not found (404)")
+	s.Contains(stderr, "1 of 2 jobs could not be removed")
+}
+
+// run
+
+func (s *CLITestSuite) Test_Run_TriggersJob() {
+	s.Responses["POST /job/backup/run"] = server.RunResponse{Status: "OK", Run: cron.Run{ID: "123", Job: "backup", Trigger: cron.TriggerManual}}
+
+	stdout, _, code := s.run("run", "backup")
+
+	s.Equal(0, code)
+	s.Equal("Run 123 of job backup was started\n", stdout)
+}
+
+// suspend and resume
+
+func (s *CLITestSuite) Test_Suspend_SuspendsJob() {
+	s.Responses["POST /job/backup/suspend"] = server.Response{Status: "OK"}
+
+	stdout, _, code := s.run("suspend", "backup")
+
+	s.Equal(0, code)
+	s.Equal("Job backup was suspended\n", stdout)
+}
+
+func (s *CLITestSuite) Test_Resume_ResumesJob() {
+	s.Responses["POST /job/backup/resume"] = server.Response{Status: "OK"}
+
+	stdout, _, code := s.run("resume", "backup")
+
+	s.Equal(0, code)
+	s.Equal("Job backup was resumed\n", stdout)
+}
+
+// logs
+
+func (s *CLITestSuite) Test_Logs_PrintsLogs() {
+	s.Responses["GET /job/backup/logs"] = "line 1\nline 2\n"
+
+	stdout, _, code := s.run("logs", "backup", "-f", "--tail", "2")
+
+	s.Equal(0, code)
+	s.Equal("line 1\nline 2\n", stdout)
+}
+
+// history
+
+func (s *CLITestSuite) Test_History_PrintsRuns() {
+	started := time.Date(2017, 5, 6, 3, 0, 0, 0, time.Local)
+	s.Responses["GET /job/backup/executions"] = server.RunsResponse{Status: "OK", Runs: []cron.Run{
+		{ID: "2", Job: "backup", Trigger: cron.TriggerManual, Status: cron.RunFailed, StartedAt: started, Duration: "1s", Message: "exit code 1"},
+	}}
+
+	stdout, _, code := s.run("history", "backup")
+
+	s.Equal(0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	s.Regexp(`^ID\s+TRIGGER\s+STATUS\s+STARTED\s+DURATION\s+MESSAGE$`, lines[0])
+	s.Regexp(`^2\s+manual\s+Failed\s+2017-05-06 03:00:00\s+1s\s+exit code 1$`, lines[1])
+}
+
+// plan and apply
+
+func (s *CLITestSuite) Test_Plan_PrintsChanges() {
+	s.Responses["POST /jobs/plan"] = server.PlanResponse{Status: "OK", Plan: cron.Plan{Changes: []cron.PlanChange{
+		{Name: "backup", Action: "create", Reason: "the job does not exist"},
+		{Name: "cleanup", Action: "update", Reason: "definition changed", Error: "image is mandatory"},
+	}}}
+
+	stdout, _, code := s.run("plan")
+
+	s.Equal(0, code)
+	s.Regexp(`create\s+backup\s+the job does not exist\s+-`, stdout)
+	s.Regexp(`update\s+cleanup\s+definition changed\s+image is mandatory`, stdout)
+}
+
+func (s *CLITestSuite) Test_Apply_SendsJobsInFile() {
+	file := filepath.Join(s.Dir, "jobs.yml")
+	ioutil.WriteFile(file, []byte("jobs:\n- name: backup\n  image: alpine\n  schedule: '@daily'\n"), 0600)
+	s.Responses["POST /jobs/apply"] = server.PlanResponse{Status: "OK", Plan: cron.Plan{Changes: []cron.PlanChange{}}}
+
+	stdout, _, code := s.run("apply", "-f", file)
+
+	s.Equal(0, code)
+	s.Equal("No changes\n", stdout)
+	s.Contains(s.Bodies[0], `"name":"backup"`)
+}
+
+// context
+
+func (s *CLITestSuite) Test_Context_ManagesContexts() {
+	_, _, code := s.run("context", "add", "production", "https://cron.example.com", "--token", "secret")
+	s.Equal(0, code)
+	_, _, code = s.run("context", "use", "production")
+	s.Equal(0, code)
+
+	stdout, _, code := s.run("context", "ls")
+
+	s.Equal(0, code)
+	s.Regexp(`\*\s+production\s+https://cron.example.com`, stdout)
+	config, _ := LoadConfig(s.ConfigFile)
+	s.Equal("secret", config.Contexts["production"].Token)
+
+	_, _, code = s.run("context", "rm", "production")
+
+	s.Equal(0, code)
+	config, _ = LoadConfig(s.ConfigFile)
+	s.NotContains(config.Contexts, "production")
+	s.Empty(config.CurrentContext)
+}
+
+func (s *CLITestSuite) Test_Context_ReturnsError_WhenContextDoesNotExist() {
+	_, stderr, code := s.run("context", "use", "production")
+
+	s.Equal(1, code)
+	s.Contains(stderr, "context production does not exist")
+}
+
+// Util
+
+func (s *CLITestSuite) run(args ...string) (string, string, int) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := Run(append([]string{"--config", s.ConfigFile}, args...), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
@@ -0,0 +1,378 @@
+package cli
+
+import (
+	"../client"
+	"../cron"
+	"fmt"
+	"golang.org/x/net/context"
+	"io"
+	"sort"
+)
+
+func (c *CLI) list(usage string, args []string) error {
+	if _, err := c.parse(c.flagSet("ls", usage), usage, args, 0, 0); err != nil {
+		return err
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	jobs, err := cl.GetJobs(ctx)
+	if err != nil {
+		return err
+	}
+	sorted := sortedJobs(jobs)
+	return write(c.Stdout, c.output, sorted, jobsTable(sorted))
+}
+
+// inspect prints the job as YAML unless JSON is requested.
+func (c *CLI) inspect(usage string, args []string) error {
+	names, err := c.parse(c.flagSet("inspect", usage), usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	details, err := cl.GetJob(ctx, names[0])
+	if err != nil {
+		return err
+	}
+	output := c.output
+	if output == OutputTable {
+		output = OutputYAML
+	}
+	return write(c.Stdout, output, details.Job, nil)
+}
+
+// create schedules each job in the file. It continues with the remaining jobs when a job cannot be created.
+func (c *CLI) create(usage string, args []string) error {
+	fs := c.flagSet("create", usage)
+	file := fs.String("f", "", "")
+	if _, err := c.parse(fs, usage, args, 0, 0); err != nil {
+		return err
+	}
+	if len(*file) == 0 {
+		return &usageError{message: "the file is mandatory", usage: usage}
+	}
+	jobs, err := cron.LoadJobsFile(*file)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %s", *file, err.Error())
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, job := range jobs {
+		ctx, cancel := requestContext()
+		_, err := cl.PutJob(ctx, job)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(c.Stderr, "Job %s could not be created: %s\n", job.Name, err.Error())
+			failed++
+			continue
+		}
+		fmt.Fprintf(c.Stdout, "Job %s was created\n", job.Name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs could not be created", failed, len(jobs))
+	}
+	return nil
+}
+
+func (c *CLI) remove(usage string, args []string) error {
+	names, err := c.parse(c.flagSet("rm", usage), usage, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	return c.eachJob(names, "removed", func(cl *client.Client, ctx context.Context, name string) error {
+		return cl.DeleteJob(ctx, name)
+	})
+}
+
+// run prints the ID of the started run. The run is printed with the JSON and YAML outputs.
+func (c *CLI) run(usage string, args []string) error {
+	names, err := c.parse(c.flagSet("run", usage), usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	run, err := cl.RunJob(ctx, names[0])
+	if err != nil {
+		return err
+	}
+	return write(c.Stdout, c.output, run, func(w io.Writer) {
+		fmt.Fprintf(w, "Run %s of job %s was started\n", run.ID, run.Job)
+	})
+}
+
+func (c *CLI) suspend(usage string, args []string) error {
+	names, err := c.parse(c.flagSet("suspend", usage), usage, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	return c.eachJob(names, "suspended", func(cl *client.Client, ctx context.Context, name string) error {
+		return cl.SuspendJob(ctx, name)
+	})
+}
+
+func (c *CLI) resume(usage string, args []string) error {
+	names, err := c.parse(c.flagSet("resume", usage), usage, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	return c.eachJob(names, "resumed", func(cl *client.Client, ctx context.Context, name string) error {
+		return cl.ResumeJob(ctx, name)
+	})
+}
+
+// eachJob calls the function for each job and reports the outcome. It continues with the remaining jobs on errors.
+func (c *CLI) eachJob(names []string, done string, f func(cl *client.Client, ctx context.Context, name string) error) error {
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, name := range names {
+		ctx, cancel := requestContext()
+		err := f(cl, ctx, name)
+		cancel()
+		if err != nil && len(names) == 1 {
+			return err
+		} else if err != nil {
+			fmt.Fprintf(c.Stderr, "Job %s could not be %s: %s\n", name, done, err.Error())
+			failed++
+			continue
+		}
+		fmt.Fprintf(c.Stdout, "Job %s was %s\n", name, done)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs could not be %s", failed, len(names), done)
+	}
+	return nil
+}
+
+// logs copies the logs to the standard output. When following, it stops when the connection is closed.
+func (c *CLI) logs(usage string, args []string) error {
+	fs := c.flagSet("logs", usage)
+	follow := fs.Bool("f", false, "")
+	fs.BoolVar(follow, "follow", false, "")
+	tail := fs.Int("tail", 0, "")
+	names, err := c.parse(fs, usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if !*follow {
+		ctx, cancel = requestContext()
+	}
+	defer cancel()
+	logs, err := cl.Logs(ctx, names[0], client.LogsOptions{Follow: *follow, Tail: *tail})
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+	_, err = io.Copy(c.Stdout, logs)
+	return err
+}
+
+func (c *CLI) history(usage string, args []string) error {
+	fs := c.flagSet("history", usage)
+	limit := fs.Int("limit", 20, "")
+	names, err := c.parse(fs, usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	runs, err := cl.GetRuns(ctx, names[0], *limit)
+	if err != nil {
+		return err
+	}
+	return write(c.Stdout, c.output, runs, runsTable(runs))
+}
+
+func (c *CLI) plan(usage string, args []string) error {
+	return c.planOrApply("plan", usage, args, (*client.Client).Plan)
+}
+
+func (c *CLI) apply(usage string, args []string) error {
+	return c.planOrApply("apply", usage, args, (*client.Client).Apply)
+}
+
+// planOrApply sends the jobs in the file or, without a file, lets the instance use its own jobs file.
+func (c *CLI) planOrApply(name, usage string, args []string, f func(cl *client.Client, ctx context.Context, jobs []cron.JobData) (cron.Plan, error)) error {
+	fs := c.flagSet(name, usage)
+	file := fs.String("f", "", "")
+	if _, err := c.parse(fs, usage, args, 0, 0); err != nil {
+		return err
+	}
+	var jobs []cron.JobData
+	if len(*file) > 0 {
+		loaded, err := cron.LoadJobsFile(*file)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %s", *file, err.Error())
+		}
+		jobs = loaded
+	}
+	cl, err := c.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	plan, err := f(cl, ctx, jobs)
+	if err != nil {
+		return err
+	}
+	return write(c.Stdout, c.output, plan, planTable(plan))
+}
+
+// context manages the contexts of the configuration file.
+func (c *CLI) context(usage string, args []string) error {
+	if len(args) == 0 {
+		return &usageError{message: "the context command is mandatory", usage: usage}
+	}
+	switch args[0] {
+	case "ls":
+		return c.contextList(args[1:])
+	case "use":
+		return c.contextUse(args[1:])
+	case "add":
+		return c.contextAdd(args[1:])
+	case "rm":
+		return c.contextRemove(args[1:])
+	}
+	return &usageError{message: fmt.Sprintf("unknown context command %s", args[0]), usage: usage}
+}
+
+type contextEntry struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Current bool   `json:"current"`
+}
+
+func (c *CLI) contextList(args []string) error {
+	usage := "context ls"
+	if _, err := c.parse(c.flagSet("context ls", usage), usage, args, 0, 0); err != nil {
+		return err
+	}
+	config, err := LoadConfig(c.configFile)
+	if err != nil {
+		return err
+	}
+	entries := []contextEntry{}
+	for name, context := range config.Contexts {
+		entries = append(entries, contextEntry{Name: name, Address: context.Address, Current: name == config.CurrentContext})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return write(c.Stdout, c.output, entries, func(w io.Writer) {
+		fmt.Fprintln(w, "CURRENT\tNAME\tADDRESS")
+		for _, entry := range entries {
+			current := ""
+			if entry.Current {
+				current = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", current, entry.Name, entry.Address)
+		}
+	})
+}
+
+func (c *CLI) contextUse(args []string) error {
+	usage := "context use NAME"
+	names, err := c.parse(c.flagSet("context use", usage), usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(c.configFile)
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Contexts[names[0]]; !ok {
+		return fmt.Errorf("context %s does not exist", names[0])
+	}
+	config.CurrentContext = names[0]
+	if err := config.Save(c.configFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "Switched to context %s\n", names[0])
+	return nil
+}
+
+// contextAdd creates or replaces a context. The first context becomes the current one.
+func (c *CLI) contextAdd(args []string) error {
+	usage := "context add NAME ADDRESS [--token TOKEN] [--ca FILE] [--cert FILE] [--key FILE] [--insecure]"
+	fs := c.flagSet("context add", usage)
+	context := Context{}
+	fs.StringVar(&context.Token, "token", "", "")
+	fs.StringVar(&context.CAFile, "ca", "", "")
+	fs.StringVar(&context.CertFile, "cert", "", "")
+	fs.StringVar(&context.KeyFile, "key", "", "")
+	fs.BoolVar(&context.Insecure, "insecure", false, "")
+	names, err := c.parse(fs, usage, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	context.Address = names[1]
+	config, err := LoadConfig(c.configFile)
+	if err != nil {
+		return err
+	}
+	if config.Contexts == nil {
+		config.Contexts = map[string]Context{}
+	}
+	config.Contexts[names[0]] = context
+	if len(config.CurrentContext) == 0 {
+		config.CurrentContext = names[0]
+	}
+	if err := config.Save(c.configFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "Context %s was added\n", names[0])
+	return nil
+}
+
+func (c *CLI) contextRemove(args []string) error {
+	usage := "context rm NAME"
+	names, err := c.parse(c.flagSet("context rm", usage), usage, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	config, err := LoadConfig(c.configFile)
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Contexts[names[0]]; !ok {
+		return fmt.Errorf("context %s does not exist", names[0])
+	}
+	delete(config.Contexts, names[0])
+	if config.CurrentContext == names[0] {
+		config.CurrentContext = ""
+	}
+	if err := config.Save(c.configFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "Context %s was removed\n", names[0])
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Config stores the instances of Docker Flow Cron dfcron can connect to.
+type Config struct {
+	CurrentContext string             `yaml:"current-context,omitempty"`
+	Contexts       map[string]Context `yaml:"contexts,omitempty"`
+}
+
+// Context describes how to connect to a single instance.
+type Context struct {
+	Address  string `yaml:"address"`
+	Token    string `yaml:"token,omitempty"`
+	CAFile   string `yaml:"ca,omitempty"`
+	CertFile string `yaml:"cert,omitempty"`
+	KeyFile  string `yaml:"key,omitempty"`
+	Insecure bool   `yaml:"insecure,omitempty"`
+}
+
+// defaultConfigFile returns the path of the configuration set with DFCRON_CONFIG or, by default, ~/.dfcron/config.yml.
+func defaultConfigFile() string {
+	if path := os.Getenv("DFCRON_CONFIG"); len(path) > 0 {
+		return path
+	}
+	home := os.Getenv("HOME")
+	if len(home) == 0 {
+		home = os.Getenv("USERPROFILE")
+	}
+	return filepath.Join(home, ".dfcron", "config.yml")
+}
+
+// LoadConfig reads the configuration from the file. A file that does not exist is an empty configuration.
+func LoadConfig(path string) (Config, error) {
+	config := Config{}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	} else if err != nil {
+		return config, err
+	}
+	err = yaml.Unmarshal(content, &config)
+	return config, err
+}
+
+// Save writes the configuration to the file. The file is readable only by its owner since it can contain tokens.
+func (config Config) Save(path string) error {
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0600)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type ConfigTestSuite struct {
+	suite.Suite
+	Dir string
+}
+
+func TestConfigUnitTestSuite(t *testing.T) {
+	s := new(ConfigTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *ConfigTestSuite) SetupTest() {
+	s.Dir, _ = ioutil.TempDir("", "dfcron")
+}
+
+func (s *ConfigTestSuite) TearDownTest() {
+	os.RemoveAll(s.Dir)
+}
+
+// LoadConfig
+
+func (s *ConfigTestSuite) Test_LoadConfig_ReturnsEmptyConfig_WhenFileDoesNotExist() {
+	actual, err := LoadConfig(filepath.Join(s.Dir, "config.yml"))
+
+	s.NoError(err)
+	s.Equal(Config{}, actual)
+}
+
+func (s *ConfigTestSuite) Test_LoadConfig_ReadsContexts() {
+	path := filepath.Join(s.Dir, "config.yml")
+	ioutil.WriteFile(path, []byte(`current-context: production
+contexts:
+  production:
+    address: https://cron.example.com
+    token: secret
+    ca: /certs/ca.pem
+    insecure: true
+`), 0600)
+
+	actual, err := LoadConfig(path)
+
+	s.NoError(err)
+	s.Equal("production", actual.CurrentContext)
+	s.Equal(Context{Address: "https://cron.example.com", Token: "secret", CAFile: "/certs/ca.pem", Insecure: true}, actual.Contexts["production"])
+}
+
+func (s *ConfigTestSuite) Test_LoadConfig_ReturnsError_WhenFileIsInvalid() {
+	path := filepath.Join(s.Dir, "config.yml")
+	ioutil.WriteFile(path, []byte("contexts: [production"), 0600)
+
+	_, err := LoadConfig(path)
+
+	s.Error(err)
+}
+
+// Save
+
+func (s *ConfigTestSuite) Test_Save_WritesFileReadableByOwner() {
+	path := filepath.Join(s.Dir, "nested", "config.yml")
+	expected := Config{CurrentContext: "local", Contexts: map[string]Context{"local": {Address: "http://localhost:8080"}}}
+
+	err := expected.Save(path)
+
+	s.NoError(err)
+	info, _ := os.Stat(path)
+	s.Equal(os.FileMode(0600), info.Mode().Perm())
+	actual, _ := LoadConfig(path)
+	s.Equal(expected, actual)
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// timeFormat is the format of times in tables. Times are shown in the local time zone.
+const timeFormat = "2006-01-02 15:04:05"
+
+func validateOutput(output string) error {
+	switch output {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	}
+	return fmt.Errorf("output must be %s, %s or %s", OutputTable, OutputJSON, OutputYAML)
+}
+
+// write prints the value as JSON or YAML or, with the table output, with the table function.
+// YAML uses the same field names as JSON.
+func write(w io.Writer, output string, value interface{}, table func(w io.Writer)) error {
+	switch output {
+	case OutputJSON:
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(content))
+		return err
+	case OutputYAML:
+		content, err := toYAML(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func toYAML(value interface{}) ([]byte, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(content, &generic); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+// sortedJobs returns the jobs sorted by name.
+func sortedJobs(jobs map[string]cron.JobData) []cron.JobData {
+	sorted := []cron.JobData{}
+	for _, job := range jobs {
+		sorted = append(sorted, job)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func jobsTable(jobs []cron.JobData) func(w io.Writer) {
+	return func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tTYPE\tSCHEDULE\tTARGET\tSTATUS\tNEXT RUN\tLAST RUN")
+		for _, job := range jobs {
+			status, nextRun, lastRun := "", "-", "-"
+			if job.State != nil {
+				status = job.State.Status
+				nextRun = formatTime(job.State.NextRun)
+				if job.State.LastRun != nil {
+					lastRun = fmt.Sprintf("%s (%s)", formatTime(&job.State.LastRun.StartedAt), job.State.LastRun.Status)
+				}
+			}
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				job.Name, jobType(job), jobSchedule(job), jobTarget(job), orDash(status), nextRun, lastRun,
+			)
+		}
+	}
+}
+
+func runsTable(runs []cron.Run) func(w io.Writer) {
+	return func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTRIGGER\tSTATUS\tSTARTED\tDURATION\tMESSAGE")
+		for _, run := range runs {
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				run.ID, run.Trigger, run.Status, formatTime(&run.StartedAt), orDash(run.Duration), orDash(run.Message),
+			)
+		}
+	}
+}
+
+func planTable(plan cron.Plan) func(w io.Writer) {
+	return func(w io.Writer) {
+		if len(plan.Changes) == 0 {
+			fmt.Fprintln(w, "No changes")
+			return
+		}
+		fmt.Fprintln(w, "ACTION\tNAME\tREASON\tERROR")
+		for _, change := range plan.Changes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Action, change.Name, orDash(change.Reason), orDash(change.Error))
+		}
+	}
+}
+
+func jobType(job cron.JobData) string {
+	if len(job.Type) == 0 {
+		return cron.TypeService
+	}
+	return job.Type
+}
+
+func jobSchedule(job cron.JobData) string {
+	if len(job.RunAt) > 0 {
+		return "at " + job.RunAt
+	}
+	return job.Schedule
+}
+
+// jobTarget describes what the job runs: the image, the request of http jobs or the command of exec jobs.
+func jobTarget(job cron.JobData) string {
+	switch {
+	case job.HTTP != nil:
+		method := job.HTTP.Method
+		if len(method) == 0 {
+			method = "GET"
+		}
+		return fmt.Sprintf("%s %s", strings.ToUpper(method), job.HTTP.URL)
+	case job.Exec != nil:
+		return fmt.Sprintf("%s: %s", job.Exec.Service, job.Exec.Command)
+	}
+	return job.Image
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(timeFormat)
+}
+
+func orDash(value string) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"../cron"
+	"bytes"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type OutputTestSuite struct {
+	suite.Suite
+}
+
+func TestOutputUnitTestSuite(t *testing.T) {
+	s := new(OutputTestSuite)
+	suite.Run(t, s)
+}
+
+// write
+
+func (s *OutputTestSuite) Test_Write_UsesJSONFieldNamesInYAML() {
+	actual := bytes.Buffer{}
+
+	err := write(&actual, OutputYAML, cron.Run{ID: "1", Job: "backup", TriggeredBy: "ops"}, nil)
+
+	s.NoError(err)
+	s.Contains(actual.String(), "triggeredBy: ops\n")
+	s.NotContains(actual.String(), "cancelledBy")
+}
+
+func (s *OutputTestSuite) Test_Write_AlignsTable() {
+	actual := bytes.Buffer{}
+
+	write(&actual, OutputTable, nil, func(w io.Writer) {
+		w.Write([]byte("NAME\tSTATUS\n"))
+		w.Write([]byte("backup-database\tScheduled\n"))
+	})
+
+	s.Equal("NAME              STATUS\nbackup-database   Scheduled\n", actual.String())
+}
+
+// jobTarget
+
+func (s *OutputTestSuite) Test_JobTarget_DescribesJob() {
+	s.Equal("alpine", jobTarget(cron.JobData{Image: "alpine"}))
+	s.Equal("GET http://search/reindex", jobTarget(cron.JobData{HTTP: &cron.HTTPRequest{URL: "http://search/reindex"}}))
+	s.Equal("app: python manage.py clearsessions", jobTarget(cron.JobData{Exec: &cron.ExecCommand{Service: "app", Command: "python manage.py clearsessions"}}))
+}
@@ -0,0 +1,462 @@
+package client
+
+import (
+	"../cron"
+	"../server"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const basePath = "/v1/docker-flow-cron"
+
+// Client calls the API of a Docker Flow Cron instance.
+type Client struct {
+	// Address is the base address of the instance, e.g. https://cron:8080
+	Address string
+	// Token is sent as a bearer token, e.g. to a proxy that authenticates requests
+	Token      string
+	HTTPClient *http.Client
+}
+
+// Options configure the connection to an instance.
+type Options struct {
+	Token string
+	// CAFile is the PEM encoded certificate of the CA that signed the server certificate
+	CAFile string
+	// CertFile and KeyFile are the PEM encoded client certificate and key used when the instance requires client certificates
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// Error is returned for responses with the NOK status.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.StatusCode)
+}
+
+// IsNotFound returns true if the error was returned because a job, run or calendar does not exist.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if the error was returned because the request conflicts with the current state,
+// e.g. when a run that already finished is cancelled.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsForbidden returns true if the error was returned because the request was denied by a policy or a role.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func hasStatus(err error, status int) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == status
+}
+
+// New returns a client of the instance at the address. TLS options are used only with https addresses.
+func New(address string, options Options) (*Client, error) {
+	if _, err := url.Parse(address); err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify}
+	if len(options.CAFile) > 0 {
+		ca, err := ioutil.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("could not parse CA certificate %s", options.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(options.CertFile) > 0 || len(options.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &Client{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   options.Token,
+		HTTPClient: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// GetJobs returns all jobs.
+func (c *Client) GetJobs(ctx context.Context) (map[string]cron.JobData, error) {
+	response := server.Response{}
+	err := c.do(ctx, "GET", "/job", nil, nil, &response)
+	return response.Jobs, err
+}
+
+// GetJob returns the job together with its executions.
+func (c *Client) GetJob(ctx context.Context, name string) (server.ResponseDetails, error) {
+	response := server.ResponseDetails{}
+	err := c.do(ctx, "GET", "/job/"+url.PathEscape(name), nil, nil, &response)
+	return response, err
+}
+
+// PutJob creates or replaces the job with the name of the data.
+func (c *Client) PutJob(ctx context.Context, data cron.JobData) (server.ResponseDetails, error) {
+	response := server.ResponseDetails{}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return response, err
+	}
+	err = c.do(ctx, "PUT", "/job/"+url.PathEscape(data.Name), nil, body, &response)
+	return response, err
+}
+
+// DeleteJob removes the job and its services.
+func (c *Client) DeleteJob(ctx context.Context, name string) error {
+	return c.do(ctx, "DELETE", "/job/"+url.PathEscape(name), nil, nil, &server.ResponseDetails{})
+}
+
+// CancelRun stops a running execution of the job.
+func (c *Client) CancelRun(ctx context.Context, jobName, runID string) (cron.Run, error) {
+	response := server.RunResponse{}
+	path := fmt.Sprintf("/job/%s/executions/%s/cancel", url.PathEscape(jobName), url.PathEscape(runID))
+	err := c.do(ctx, "POST", path, nil, nil, &response)
+	return response.Run, err
+}
+
+// RunJob starts a run of the job right away, outside of its schedule.
+func (c *Client) RunJob(ctx context.Context, name string) (cron.Run, error) {
+	response := server.RunResponse{}
+	err := c.do(ctx, "POST", "/job/"+url.PathEscape(name)+"/run", nil, nil, &response)
+	return response.Run, err
+}
+
+// SuspendJob stops scheduling the job until it is resumed.
+func (c *Client) SuspendJob(ctx context.Context, name string) error {
+	return c.do(ctx, "POST", "/job/"+url.PathEscape(name)+"/suspend", nil, nil, &server.Response{})
+}
+
+// ResumeJob schedules a suspended job again.
+func (c *Client) ResumeJob(ctx context.Context, name string) error {
+	return c.do(ctx, "POST", "/job/"+url.PathEscape(name)+"/resume", nil, nil, &server.Response{})
+}
+
+// GetRuns returns the latest runs of the job, newest first. All stored runs are returned when the limit is 0.
+func (c *Client) GetRuns(ctx context.Context, name string, limit int) ([]cron.Run, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	response := server.RunsResponse{}
+	err := c.do(ctx, "GET", "/job/"+url.PathEscape(name)+"/executions", query, nil, &response)
+	return response.Runs, err
+}
+
+// LogsOptions configure the logs returned by Logs.
+type LogsOptions struct {
+	// Follow keeps the logs open and streams new lines until the context is cancelled or the reader is closed.
+	Follow bool
+	// Tail is the number of lines returned from the end of the logs. All lines are returned when it is 0.
+	Tail int
+}
+
+// Logs returns the logs of the service of the job. The reader must be closed.
+func (c *Client) Logs(ctx context.Context, name string, options LogsOptions) (io.ReadCloser, error) {
+	query := url.Values{}
+	if options.Follow {
+		query.Set("follow", "true")
+	}
+	if options.Tail > 0 {
+		query.Set("tail", strconv.Itoa(options.Tail))
+	}
+	resp, err := c.send(ctx, "GET", "/job/"+url.PathEscape(name)+"/logs", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		content, _ := ioutil.ReadAll(resp.Body)
+		return nil, responseError(resp.StatusCode, content)
+	}
+	return resp.Body, nil
+}
+
+// GetAudit returns the audit log entries that match the filter.
+func (c *Client) GetAudit(ctx context.Context, filter server.AuditFilter) ([]server.AuditEntry, error) {
+	query := url.Values{}
+	setQuery(query, "job", filter.Job)
+	setQuery(query, "caller", filter.Caller)
+	setQuery(query, "action", filter.Action)
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	response := server.AuditResponse{}
+	err := c.do(ctx, "GET", "/audit", query, nil, &response)
+	return response.Entries, err
+}
+
+// Plan returns the changes required to make the scheduled jobs match the definitions.
+// When jobs is nil, the jobs file of the instance is used.
+func (c *Client) Plan(ctx context.Context, jobs []cron.JobData) (cron.Plan, error) {
+	return c.plan(ctx, "/jobs/plan", jobs)
+}
+
+// Apply creates, updates and deletes jobs so that they match the definitions and returns the applied changes.
+// When jobs is nil, the jobs file of the instance is used.
+func (c *Client) Apply(ctx context.Context, jobs []cron.JobData) (cron.Plan, error) {
+	return c.plan(ctx, "/jobs/apply", jobs)
+}
+
+func (c *Client) plan(ctx context.Context, path string, jobs []cron.JobData) (cron.Plan, error) {
+	var body []byte
+	if jobs != nil {
+		var err error
+		if body, err = json.Marshal(jobs); err != nil {
+			return cron.Plan{}, err
+		}
+	}
+	response := server.PlanResponse{}
+	err := c.do(ctx, "POST", path, nil, body, &response)
+	return response.Plan, err
+}
+
+// ImportOptions configure the conversion of jobs in other formats.
+type ImportOptions struct {
+	// Image runs the commands of crontab entries
+	Image  string
+	Prefix string
+	System bool
+	DryRun bool
+}
+
+// Import converts jobs from the crontab or k8s format and schedules them.
+func (c *Client) Import(ctx context.Context, format string, content []byte, options ImportOptions) (server.ImportResponse, error) {
+	query := url.Values{"format": []string{format}}
+	setQuery(query, "image", options.Image)
+	setQuery(query, "prefix", options.Prefix)
+	if options.System {
+		query.Set("system", "true")
+	}
+	if options.DryRun {
+		query.Set("dryRun", "true")
+	}
+	response := server.ImportResponse{}
+	err := c.do(ctx, "POST", "/jobs/import", query, content, &response)
+	return response, err
+}
+
+// Export converts all jobs into another format. Only the k8s format is supported.
+func (c *Client) Export(ctx context.Context, format string) ([]byte, error) {
+	resp, err := c.send(ctx, "GET", "/jobs/export", url.Values{"format": []string{format}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp.StatusCode, content)
+	}
+	return content, nil
+}
+
+// PreviewOptions describe the schedule to preview.
+type PreviewOptions struct {
+	Spec      string
+	Count     int
+	TimeZone  string
+	Name      string
+	Jitter    string
+	Calendars []string
+}
+
+// PreviewSchedule returns the next times a schedule fires together with its description.
+func (c *Client) PreviewSchedule(ctx context.Context, options PreviewOptions) (server.PreviewResponse, error) {
+	query := url.Values{"spec": []string{options.Spec}}
+	if options.Count > 0 {
+		query.Set("count", strconv.Itoa(options.Count))
+	}
+	setQuery(query, "tz", options.TimeZone)
+	setQuery(query, "name", options.Name)
+	setQuery(query, "jitter", options.Jitter)
+	setQuery(query, "calendars", strings.Join(options.Calendars, ","))
+	response := server.PreviewResponse{}
+	err := c.do(ctx, "GET", "/schedule/preview", query, nil, &response)
+	return response, err
+}
+
+// Pause stops running jobs until Resume is called or, if set, until the time.
+func (c *Client) Pause(ctx context.Context, until *time.Time) (cron.Maintenance, error) {
+	query := url.Values{}
+	if until != nil {
+		query.Set("until", until.Format(time.RFC3339))
+	}
+	return c.maintenance(ctx, "POST", "/admin/pause", query)
+}
+
+// Resume runs jobs again after a pause.
+func (c *Client) Resume(ctx context.Context) (cron.Maintenance, error) {
+	return c.maintenance(ctx, "POST", "/admin/resume", nil)
+}
+
+// GetMaintenance returns whether jobs are paused.
+func (c *Client) GetMaintenance(ctx context.Context) (cron.Maintenance, error) {
+	return c.maintenance(ctx, "GET", "/admin/maintenance", nil)
+}
+
+func (c *Client) maintenance(ctx context.Context, method, path string, query url.Values) (cron.Maintenance, error) {
+	response := server.MaintenanceResponse{}
+	err := c.do(ctx, method, path, query, nil, &response)
+	return response.Maintenance, err
+}
+
+// GetCalendars returns all calendars.
+func (c *Client) GetCalendars(ctx context.Context) ([]cron.Calendar, error) {
+	response := server.CalendarsResponse{}
+	err := c.do(ctx, "GET", "/calendars", nil, nil, &response)
+	return response.Calendars, err
+}
+
+// GetCalendar returns a single calendar.
+func (c *Client) GetCalendar(ctx context.Context, name string) (cron.Calendar, error) {
+	response := server.CalendarsResponse{}
+	if err := c.do(ctx, "GET", "/calendars/"+url.PathEscape(name), nil, nil, &response); err != nil {
+		return cron.Calendar{}, err
+	}
+	return firstCalendar(response), nil
+}
+
+// PutCalendar creates or replaces the calendar with the name of the calendar.
+func (c *Client) PutCalendar(ctx context.Context, calendar cron.Calendar) (cron.Calendar, error) {
+	body, err := json.Marshal(calendar)
+	if err != nil {
+		return cron.Calendar{}, err
+	}
+	response := server.CalendarsResponse{}
+	if err := c.do(ctx, "PUT", "/calendars/"+url.PathEscape(calendar.Name), nil, body, &response); err != nil {
+		return cron.Calendar{}, err
+	}
+	return firstCalendar(response), nil
+}
+
+// PutCalendarICal replaces the ranges of the calendar with the events of an iCalendar file.
+// It returns the calendar and the warnings about events that could not be converted.
+func (c *Client) PutCalendarICal(ctx context.Context, name, timeZone string, content []byte) (cron.Calendar, []string, error) {
+	query := url.Values{"format": []string{"ical"}}
+	setQuery(query, "tz", timeZone)
+	response := server.CalendarsResponse{}
+	if err := c.do(ctx, "PUT", "/calendars/"+url.PathEscape(name), query, content, &response); err != nil {
+		return cron.Calendar{}, response.Warnings, err
+	}
+	return firstCalendar(response), response.Warnings, nil
+}
+
+// DeleteCalendar removes a calendar that is not excluded by any job.
+func (c *Client) DeleteCalendar(ctx context.Context, name string) error {
+	return c.do(ctx, "DELETE", "/calendars/"+url.PathEscape(name), nil, nil, &server.CalendarsResponse{})
+}
+
+func firstCalendar(response server.CalendarsResponse) cron.Calendar {
+	if len(response.Calendars) == 0 {
+		return cron.Calendar{}
+	}
+	return response.Calendars[0]
+}
+
+// do sends the request and decodes the JSON response into the value.
+// Responses with the NOK status are returned as errors.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body []byte, value interface{}) error {
+	resp, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return responseError(resp.StatusCode, content)
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		return fmt.Errorf("could not decode response: %s", err.Error())
+	}
+	status := struct{ Status, Message string }{}
+	json.Unmarshal(content, &status)
+	if status.Status == "NOK" {
+		return &Error{StatusCode: resp.StatusCode, Message: status.Message}
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Response, error) {
+	address := c.Address + basePath + path
+	if len(query) > 0 {
+		address = address + "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, address, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(c.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req.WithContext(ctx))
+}
+
+// responseError returns the message of the response or, if the response is not JSON, its content.
+func responseError(statusCode int, content []byte) error {
+	status := struct{ Status, Message string }{}
+	if err := json.Unmarshal(content, &status); err != nil || len(status.Message) == 0 {
+		status.Message = strings.TrimSpace(string(content))
+	}
+	if len(status.Message) == 0 {
+		status.Message = http.StatusText(statusCode)
+	}
+	return &Error{StatusCode: statusCode, Message: status.Message}
+}
+
+func setQuery(query url.Values, key, value string) {
+	if len(value) > 0 {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,684 @@
+package client
+
+import (
+	"../cron"
+	"../docker"
+	"../server"
+	"encoding/pem"
+	"fmt"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	Cron     CronerMock
+	Serve    *server.Serve
+	Server   *httptest.Server
+	Client   *Client
+	Requests []*http.Request
+}
+
+func TestClientUnitTestSuite(t *testing.T) {
+	s := new(ClientTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *ClientTestSuite) SetupTest() {
+	calendars, _ := cron.NewCalendarStore("")
+	auditor, _ := server.NewAuditor("")
+	s.Cron = CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{}, nil
+		},
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{}
+		},
+	}
+	s.Serve = &server.Serve{
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{}, nil
+			},
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				return []swarm.Task{}, nil
+			},
+		},
+		Auditor:   auditor,
+		Calendars: calendars,
+	}
+	s.Requests = []*http.Request{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.Requests = append(s.Requests, req)
+		s.Serve.Cron = s.Cron
+		s.Serve.Router().ServeHTTP(w, req)
+	}))
+	s.Client, _ = New(s.Server.URL, Options{})
+}
+
+func (s *ClientTestSuite) TearDownTest() {
+	s.Server.Close()
+}
+
+// New
+
+func (s *ClientTestSuite) Test_New_ReturnsError_WhenCertificatesCannotBeRead() {
+	_, err := New("https://cron:8080", Options{CAFile: "/this/file/does/not/exist"})
+	s.Error(err)
+
+	_, err = New("https://cron:8080", Options{CertFile: "/this/file/does/not/exist", KeyFile: "/neither/does/this"})
+	s.Error(err)
+}
+
+func (s *ClientTestSuite) Test_New_TrustsCAFile() {
+	tlsServer := httptest.NewTLSServer(s.Serve.Router())
+	defer tlsServer.Close()
+	s.Serve.Cron = s.Cron
+	caFile, _ := ioutil.TempFile("", "ca")
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+	caFile.Close()
+
+	untrusted, _ := New(tlsServer.URL, Options{})
+	_, untrustedErr := untrusted.GetJobs(context.Background())
+	trusted, _ := New(tlsServer.URL, Options{CAFile: caFile.Name()})
+	_, trustedErr := trusted.GetJobs(context.Background())
+
+	s.Error(untrustedErr)
+	s.NoError(trustedErr)
+}
+
+// Client
+
+func (s *ClientTestSuite) Test_Client_SendsToken() {
+	s.Client.Token = "my-token"
+
+	s.Client.GetJobs(context.Background())
+
+	s.Equal("Bearer my-token", s.Requests[0].Header.Get("Authorization"))
+}
+
+func (s *ClientTestSuite) Test_Client_ReturnsError_WhenContextIsCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Client.GetJobs(ctx)
+
+	s.Error(err)
+}
+
+// GetJobs
+
+func (s *ClientTestSuite) Test_GetJobs_ReturnsJobs() {
+	expected := map[string]cron.JobData{"my-job": {Name: "my-job", Image: "alpine", Schedule: "@daily"}}
+	s.Cron.GetJobsMock = func() (map[string]cron.JobData, error) {
+		return expected, nil
+	}
+
+	actual, err := s.Client.GetJobs(context.Background())
+
+	s.NoError(err)
+	s.Equal(expected, actual)
+}
+
+func (s *ClientTestSuite) Test_GetJobs_ReturnsError_WhenResponseIsNOK() {
+	s.Cron.GetJobsMock = func() (map[string]cron.JobData, error) {
+		return nil, fmt.Errorf("This is an error")
+	}
+
+	_, err := s.Client.GetJobs(context.Background())
+
+	s.Equal(&Error{StatusCode: 500, Message: "This is an error"}, err)
+}
+
+// GetJob
+
+func (s *ClientTestSuite) Test_GetJob_ReturnsJobDetails() {
+	job := cron.JobData{Name: "my-job", Schedule: "@daily", Type: cron.TypeHTTP, HTTP: &cron.HTTPRequest{URL: "http://example.com"}}
+	s.Cron.GetJobsMock = func() (map[string]cron.JobData, error) {
+		return map[string]cron.JobData{"my-job": job}, nil
+	}
+
+	actual, err := s.Client.GetJob(context.Background(), "my-job")
+
+	s.NoError(err)
+	s.Equal(job, actual.Job)
+	s.Equal("OK", actual.Status)
+}
+
+func (s *ClientTestSuite) Test_GetJob_ReturnsNotFoundError() {
+	_, err := s.Client.GetJob(context.Background(), "my-job")
+
+	s.True(IsNotFound(err))
+	s.EqualError(err, "Could not find the job (404)")
+}
+
+// PutJob
+
+func (s *ClientTestSuite) Test_PutJob_SchedulesJob() {
+	actual := cron.JobData{}
+	s.Cron.AddJobMock = func(data cron.JobData) error {
+		actual = data
+		return nil
+	}
+	job := cron.JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", Args: []string{"--network my-network"}}
+
+	response, err := s.Client.PutJob(context.Background(), job)
+
+	s.NoError(err)
+	s.Equal(job, actual)
+	s.Equal("Job my-job has been scheduled", response.Message)
+	s.Equal("PUT", s.Requests[0].Method)
+	s.Equal("/v1/docker-flow-cron/job/my-job", s.Requests[0].URL.Path)
+}
+
+func (s *ClientTestSuite) Test_PutJob_ReturnsForbiddenError_WhenPolicyDeniesJob() {
+	s.Cron.AddJobMock = func(data cron.JobData) error {
+		return &cron.PolicyError{Rule: "deniedImages", Message: "image ubuntu is denied"}
+	}
+
+	_, err := s.Client.PutJob(context.Background(), cron.JobData{Name: "my-job", Image: "ubuntu"})
+
+	s.True(IsForbidden(err))
+}
+
+// DeleteJob
+
+func (s *ClientTestSuite) Test_DeleteJob_RemovesJob() {
+	actual := ""
+	s.Cron.RemoveJobMock = func(jobName string) error {
+		actual = jobName
+		return nil
+	}
+
+	err := s.Client.DeleteJob(context.Background(), "my-job")
+
+	s.NoError(err)
+	s.Equal("my-job", actual)
+}
+
+// CancelRun
+
+func (s *ClientTestSuite) Test_CancelRun_ReturnsRun() {
+	expected := cron.Run{ID: "1", Job: "my-job", Status: cron.RunCancelled, StartedAt: time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)}
+	s.Cron.CancelRunMock = func(jobName, runID, by string) (cron.Run, error) {
+		return expected, nil
+	}
+
+	actual, err := s.Client.CancelRun(context.Background(), "my-job", "1")
+
+	s.NoError(err)
+	s.Equal(expected, actual)
+}
+
+func (s *ClientTestSuite) Test_CancelRun_ReturnsConflictError() {
+	s.Cron.CancelRunMock = func(jobName, runID, by string) (cron.Run, error) {
+		return cron.Run{}, &cron.ConflictError{Message: "run 1 of job my-job is not running"}
+	}
+
+	_, err := s.Client.CancelRun(context.Background(), "my-job", "1")
+
+	s.True(IsConflict(err))
+}
+
+// RunJob
+
+func (s *ClientTestSuite) Test_RunJob_ReturnsRun() {
+	expected := cron.Run{ID: "1", Job: "my-job", Trigger: cron.TriggerManual, Status: cron.RunRunning, StartedAt: time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)}
+	actualJob := ""
+	s.Cron.TriggerJobMock = func(jobName, by string) (cron.Run, error) {
+		actualJob = jobName
+		return expected, nil
+	}
+
+	actual, err := s.Client.RunJob(context.Background(), "my-job")
+
+	s.NoError(err)
+	s.Equal("my-job", actualJob)
+	s.Equal(expected, actual)
+}
+
+// SuspendJob
+
+func (s *ClientTestSuite) Test_SuspendJob_SuspendsJob() {
+	actual := ""
+	s.Cron.SuspendJobMock = func(jobName, by string) error {
+		actual = jobName
+		return nil
+	}
+
+	err := s.Client.SuspendJob(context.Background(), "my-job")
+
+	s.NoError(err)
+	s.Equal("my-job", actual)
+}
+
+func (s *ClientTestSuite) Test_SuspendJob_ReturnsNotFoundError() {
+	s.Cron.SuspendJobMock = func(jobName, by string) error {
+		return &cron.NotFoundError{Message: "job my-job does not exist"}
+	}
+
+	err := s.Client.SuspendJob(context.Background(), "my-job")
+
+	s.True(IsNotFound(err))
+}
+
+// ResumeJob
+
+func (s *ClientTestSuite) Test_ResumeJob_ResumesJob() {
+	actual := ""
+	s.Cron.ResumeJobMock = func(jobName string) error {
+		actual = jobName
+		return nil
+	}
+
+	err := s.Client.ResumeJob(context.Background(), "my-job")
+
+	s.NoError(err)
+	s.Equal("my-job", actual)
+}
+
+// GetRuns
+
+func (s *ClientTestSuite) Test_GetRuns_ReturnsRuns() {
+	actualLimit := 0
+	s.Cron.GetRunsMock = func(jobName string, limit int) ([]cron.Run, error) {
+		actualLimit = limit
+		return []cron.Run{{ID: "2", Job: jobName}, {ID: "1", Job: jobName}}, nil
+	}
+
+	actual, err := s.Client.GetRuns(context.Background(), "my-job", 5)
+
+	s.NoError(err)
+	s.Equal(5, actualLimit)
+	s.Len(actual, 2)
+	s.Equal("2", actual[0].ID)
+}
+
+// Logs
+
+func (s *ClientTestSuite) Test_Logs_ReturnsLogs() {
+	actualFollow, actualTail := false, ""
+	s.Serve.Logs = LoggerMock{
+		GetLogsMock: func(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+			actualFollow, actualTail = follow, tail
+			return ioutil.NopCloser(strings.NewReader("line 1\n")), nil
+		},
+	}
+
+	logs, err := s.Client.Logs(context.Background(), "my-job", LogsOptions{Follow: true, Tail: 10})
+
+	s.NoError(err)
+	defer logs.Close()
+	actual, _ := ioutil.ReadAll(logs)
+	s.Equal("line 1\n", string(actual))
+	s.True(actualFollow)
+	s.Equal("10", actualTail)
+}
+
+func (s *ClientTestSuite) Test_Logs_ReturnsNotFoundError_WhenJobDoesNotHaveService() {
+	s.Serve.Logs = LoggerMock{
+		GetLogsMock: func(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+			return nil, docker.ErrNoService
+		},
+	}
+
+	_, err := s.Client.Logs(context.Background(), "my-job", LogsOptions{})
+
+	s.True(IsNotFound(err))
+}
+
+// GetAudit
+
+func (s *ClientTestSuite) Test_GetAudit_ReturnsEntries() {
+	s.Cron.AddJobMock = func(data cron.JobData) error {
+		return nil
+	}
+	s.Client.PutJob(context.Background(), cron.JobData{Name: "my-job", Image: "alpine", Schedule: "@daily"})
+	s.Client.PutJob(context.Background(), cron.JobData{Name: "other-job", Image: "alpine", Schedule: "@daily"})
+
+	actual, err := s.Client.GetAudit(context.Background(), server.AuditFilter{Job: "my-job", Limit: 10})
+
+	s.NoError(err)
+	s.Len(actual, 1)
+	s.Equal("create", actual[0].Action)
+	s.Equal("my-job", s.Requests[2].URL.Query().Get("job"))
+}
+
+// Plan
+
+func (s *ClientTestSuite) Test_Plan_SendsJobs() {
+	actual := []cron.JobData{}
+	expected := cron.Plan{Changes: []cron.PlanChange{{Name: "my-job", Action: "create", Reason: "job does not exist"}}}
+	s.Cron.PlanMock = func(jobs []cron.JobData) (cron.Plan, error) {
+		actual = jobs
+		return expected, nil
+	}
+
+	plan, err := s.Client.Plan(context.Background(), []cron.JobData{{Name: "my-job", Image: "alpine", Schedule: "@daily"}})
+
+	s.NoError(err)
+	s.Equal(expected, plan)
+	s.Len(actual, 1)
+	s.Equal("my-job", actual[0].Name)
+}
+
+func (s *ClientTestSuite) Test_Plan_ReturnsError_WhenJobsFileIsNotConfigured() {
+	_, err := s.Client.Plan(context.Background(), nil)
+
+	s.Equal(&Error{StatusCode: 400, Message: "Request body is mandatory when the jobs file is not configured"}, err)
+}
+
+// Apply
+
+func (s *ClientTestSuite) Test_Apply_AppliesPlan() {
+	plan := cron.Plan{Changes: []cron.PlanChange{{Name: "my-job", Action: "delete", Reason: "job is not defined"}}}
+	actual := cron.Plan{}
+	s.Cron.PlanMock = func(jobs []cron.JobData) (cron.Plan, error) {
+		return plan, nil
+	}
+	s.Cron.ApplyMock = func(p cron.Plan) error {
+		actual = p
+		return nil
+	}
+
+	applied, err := s.Client.Apply(context.Background(), []cron.JobData{})
+
+	s.NoError(err)
+	s.Equal(plan, applied)
+	s.Equal(plan, actual)
+}
+
+// Import
+
+func (s *ClientTestSuite) Test_Import_ReturnsJobs_WhenDryRun() {
+	actual, err := s.Client.Import(context.Background(), "crontab", []byte("0 3 * * * /usr/bin/backup.sh\n"), ImportOptions{Image: "alpine", DryRun: true})
+
+	s.NoError(err)
+	s.Len(actual.Jobs, 1)
+	s.Equal("alpine", actual.Jobs[0].Image)
+}
+
+func (s *ClientTestSuite) Test_Import_ReturnsError_WhenFormatIsNotSupported() {
+	_, err := s.Client.Import(context.Background(), "systemd", []byte{}, ImportOptions{})
+
+	s.Equal(&Error{StatusCode: 400, Message: "format systemd is not supported"}, err)
+}
+
+// Export
+
+func (s *ClientTestSuite) Test_Export_ReturnsManifests() {
+	s.Cron.GetJobsMock = func() (map[string]cron.JobData, error) {
+		return map[string]cron.JobData{"my-job": {Name: "my-job", Image: "alpine", Schedule: "0 0 3 * * *"}}, nil
+	}
+
+	actual, err := s.Client.Export(context.Background(), "k8s")
+
+	s.NoError(err)
+	s.Contains(string(actual), "kind: CronJob")
+}
+
+func (s *ClientTestSuite) Test_Export_ReturnsError_WhenFormatIsNotSupported() {
+	_, err := s.Client.Export(context.Background(), "crontab")
+
+	s.Equal(&Error{StatusCode: 400, Message: "format crontab is not supported"}, err)
+}
+
+// PreviewSchedule
+
+func (s *ClientTestSuite) Test_PreviewSchedule_ReturnsTimes() {
+	actual, err := s.Client.PreviewSchedule(context.Background(), PreviewOptions{Spec: "@daily", Count: 3, TimeZone: "UTC"})
+
+	s.NoError(err)
+	s.Len(actual.Times, 3)
+	s.NotEmpty(actual.Description)
+}
+
+func (s *ClientTestSuite) Test_PreviewSchedule_ReturnsError_WhenSpecIsInvalid() {
+	_, err := s.Client.PreviewSchedule(context.Background(), PreviewOptions{Spec: "every day"})
+
+	s.Error(err)
+	s.Equal(400, err.(*Error).StatusCode)
+}
+
+// Pause
+
+func (s *ClientTestSuite) Test_Pause_SendsUntil() {
+	until := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	actual := time.Time{}
+	s.Cron.PauseMock = func(u *time.Time, by string) error {
+		actual = *u
+		return nil
+	}
+	s.Cron.GetMaintenanceMock = func() cron.Maintenance {
+		return cron.Maintenance{Paused: true, Until: &until}
+	}
+
+	maintenance, err := s.Client.Pause(context.Background(), &until)
+
+	s.NoError(err)
+	s.True(until.Equal(actual))
+	s.True(maintenance.Paused)
+}
+
+// Resume
+
+func (s *ClientTestSuite) Test_Resume_ReturnsConflictError_WhenNotPaused() {
+	s.Cron.ResumeMock = func() error {
+		return fmt.Errorf("Docker Flow Cron is not paused")
+	}
+
+	_, err := s.Client.Resume(context.Background())
+
+	s.True(IsConflict(err))
+}
+
+// GetMaintenance
+
+func (s *ClientTestSuite) Test_GetMaintenance_ReturnsMaintenance() {
+	s.Cron.GetMaintenanceMock = func() cron.Maintenance {
+		return cron.Maintenance{Paused: true, PausedBy: "ops"}
+	}
+
+	actual, err := s.Client.GetMaintenance(context.Background())
+
+	s.NoError(err)
+	s.Equal(cron.Maintenance{Paused: true, PausedBy: "ops"}, actual)
+}
+
+// Calendars
+
+func (s *ClientTestSuite) Test_Calendars_AreManaged() {
+	calendar := cron.Calendar{Name: "holidays", Weekdays: []string{"saturday", "sunday"}}
+
+	put, putErr := s.Client.PutCalendar(context.Background(), calendar)
+	got, getErr := s.Client.GetCalendar(context.Background(), "holidays")
+	list, listErr := s.Client.GetCalendars(context.Background())
+	deleteErr := s.Client.DeleteCalendar(context.Background(), "holidays")
+	_, notFoundErr := s.Client.GetCalendar(context.Background(), "holidays")
+
+	s.NoError(putErr)
+	s.NoError(getErr)
+	s.NoError(listErr)
+	s.NoError(deleteErr)
+	s.Equal(calendar, put)
+	s.Equal(calendar, got)
+	s.Equal([]cron.Calendar{calendar}, list)
+	s.True(IsNotFound(notFoundErr))
+}
+
+func (s *ClientTestSuite) Test_PutCalendarICal_ReturnsRanges() {
+	ical := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20171225",
+		"DTEND;VALUE=DATE:20171226",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	actual, _, err := s.Client.PutCalendarICal(context.Background(), "holidays", "Europe/Berlin", []byte(ical))
+
+	s.NoError(err)
+	s.Equal("holidays", actual.Name)
+	s.Equal("Europe/Berlin", actual.TimeZone)
+	s.Len(actual.Ranges, 1)
+}
+
+// Mocks
+
+type CronerMock struct {
+	AddJobMock         func(data cron.JobData) error
+	StopMock           func()
+	GetJobsMock        func() (map[string]cron.JobData, error)
+	RemoveJobMock      func(jobName string) error
+	RescheduleJobsMock func() error
+	PlanMock           func(jobs []cron.JobData) (cron.Plan, error)
+	ApplyMock          func(plan cron.Plan) error
+	GetStateMock       func(jobName string) cron.JobState
+	PauseMock          func(until *time.Time, by string) error
+	ResumeMock         func() error
+	GetMaintenanceMock func() cron.Maintenance
+	CancelRunMock      func(jobName, runID, by string) (cron.Run, error)
+	TriggerJobMock     func(jobName, by string) (cron.Run, error)
+	SuspendJobMock     func(jobName, by string) error
+	ResumeJobMock      func(jobName string) error
+	GetRunsMock        func(jobName string, limit int) ([]cron.Run, error)
+}
+
+func (m CronerMock) AddJob(data cron.JobData) error {
+	return m.AddJobMock(data)
+}
+
+func (m CronerMock) Stop() {
+	m.StopMock()
+}
+
+func (m CronerMock) GetJobs() (map[string]cron.JobData, error) {
+	return m.GetJobsMock()
+}
+
+func (m CronerMock) RemoveJob(jobName string) error {
+	return m.RemoveJobMock(jobName)
+}
+
+func (m CronerMock) RescheduleJobs() error {
+	return m.RescheduleJobsMock()
+}
+
+func (m CronerMock) Plan(jobs []cron.JobData) (cron.Plan, error) {
+	return m.PlanMock(jobs)
+}
+
+func (m CronerMock) Apply(plan cron.Plan) error {
+	return m.ApplyMock(plan)
+}
+
+func (m CronerMock) GetState(jobName string) cron.JobState {
+	if m.GetStateMock == nil {
+		return cron.JobState{}
+	}
+	return m.GetStateMock(jobName)
+}
+
+func (m CronerMock) Pause(until *time.Time, by string) error {
+	return m.PauseMock(until, by)
+}
+
+func (m CronerMock) Resume() error {
+	return m.ResumeMock()
+}
+
+func (m CronerMock) GetMaintenance() cron.Maintenance {
+	return m.GetMaintenanceMock()
+}
+
+func (m CronerMock) CancelRun(jobName, runID, by string) (cron.Run, error) {
+	return m.CancelRunMock(jobName, runID, by)
+}
+
+func (m CronerMock) TriggerJob(jobName, by string) (cron.Run, error) {
+	return m.TriggerJobMock(jobName, by)
+}
+
+func (m CronerMock) SuspendJob(jobName, by string) error {
+	return m.SuspendJobMock(jobName, by)
+}
+
+func (m CronerMock) ResumeJob(jobName string) error {
+	return m.ResumeJobMock(jobName)
+}
+
+func (m CronerMock) GetRuns(jobName string, limit int) ([]cron.Run, error) {
+	return m.GetRunsMock(jobName, limit)
+}
+
+type ServicerMock struct {
+	GetServicesMock      func(jobName string) ([]swarm.Service, error)
+	GetTasksMock         func(jobName string) ([]swarm.Task, error)
+	RemoveServicesMock   func(jobName string) error
+	ScaleServiceMock     func(serviceName string, replicas uint64) error
+	RunServiceMock       func(serviceName, runName string, labels map[string]string) (string, error)
+	GetRunServicesMock   func(jobName string) ([]swarm.Service, error)
+	RemoveServiceMock    func(serviceName string) error
+	JobModeSupportedMock func() bool
+	RunJobMock           func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error)
+	CreateServiceMock    func(spec swarm.ServiceSpec) error
+}
+
+func (m ServicerMock) GetServices(jobName string) ([]swarm.Service, error) {
+	return m.GetServicesMock(jobName)
+}
+
+func (m ServicerMock) GetTasks(jobName string) ([]swarm.Task, error) {
+	return m.GetTasksMock(jobName)
+}
+
+func (m ServicerMock) RemoveServices(jobName string) error {
+	return m.RemoveServicesMock(jobName)
+}
+
+func (m ServicerMock) ScaleService(serviceName string, replicas uint64) error {
+	return m.ScaleServiceMock(serviceName, replicas)
+}
+
+func (m ServicerMock) RunService(serviceName, runName string, labels map[string]string) (string, error) {
+	return m.RunServiceMock(serviceName, runName, labels)
+}
+
+func (m ServicerMock) GetRunServices(jobName string) ([]swarm.Service, error) {
+	return m.GetRunServicesMock(jobName)
+}
+
+func (m ServicerMock) RemoveService(serviceName string) error {
+	return m.RemoveServiceMock(serviceName)
+}
+
+func (m ServicerMock) JobModeSupported() bool {
+	return m.JobModeSupportedMock()
+}
+
+func (m ServicerMock) RunJob(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+	return m.RunJobMock(serviceName, maxConcurrent, totalCompletions)
+}
+
+func (m ServicerMock) CreateService(spec swarm.ServiceSpec) error {
+	return m.CreateServiceMock(spec)
+}
+
+type LoggerMock struct {
+	GetLogsMock func(jobName string, follow bool, tail string) (io.ReadCloser, error)
+}
+
+func (m LoggerMock) GetLogs(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+	return m.GetLogsMock(jobName, follow, tail)
+}
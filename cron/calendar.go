@@ -0,0 +1,378 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var calendarNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Calendar is a named set of times in which jobs that exclude it do not run.
+type Calendar struct {
+	Name     string      `json:"name"`
+	TimeZone string      `json:"timeZone,omitempty"`
+	Weekdays []string    `json:"weekdays,omitempty"`
+	Ranges   []DateRange `json:"ranges,omitempty"`
+}
+
+// DateRange is a period between two dates or RFC3339 timestamps. Both ends are included.
+// Dates cover whole days in the time zone of the calendar.
+type DateRange struct {
+	Name string `json:"name,omitempty"`
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+}
+
+// SkippedTick is a time a job did not run at.
+type SkippedTick struct {
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+}
+
+// CalendarStore stores calendars.
+type CalendarStore interface {
+	Get(name string) (Calendar, bool)
+	List() []Calendar
+	Put(calendar Calendar) error
+	Remove(name string) error
+}
+
+// NewCalendarStore returns a store that keeps calendars in memory.
+// When the path is not empty, calendars are also written to the file as JSON and loaded from it.
+var NewCalendarStore = func(path string) (CalendarStore, error) {
+	store := &calendarStore{path: path, calendars: map[string]Calendar{}}
+	if len(path) == 0 {
+		return store, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+	calendars := []Calendar{}
+	if err := json.Unmarshal(content, &calendars); err != nil {
+		return nil, fmt.Errorf("could not parse calendars %s: %s", path, err.Error())
+	}
+	for _, calendar := range calendars {
+		store.calendars[calendar.Name] = calendar
+	}
+	return store, nil
+}
+
+type calendarStore struct {
+	mu        sync.RWMutex
+	path      string
+	calendars map[string]Calendar
+}
+
+func (s *calendarStore) Get(name string) (Calendar, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	calendar, ok := s.calendars[name]
+	return calendar, ok
+}
+
+func (s *calendarStore) List() []Calendar {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	calendars := []Calendar{}
+	for _, calendar := range s.calendars {
+		calendars = append(calendars, calendar)
+	}
+	sort.Slice(calendars, func(i, j int) bool { return calendars[i].Name < calendars[j].Name })
+	return calendars
+}
+
+func (s *calendarStore) Put(calendar Calendar) error {
+	if err := calendar.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, existed := s.calendars[calendar.Name]
+	s.calendars[calendar.Name] = calendar
+	if err := s.save(); err != nil {
+		if existed {
+			s.calendars[calendar.Name] = previous
+		} else {
+			delete(s.calendars, calendar.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *calendarStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.calendars[name]; !ok {
+		return fmt.Errorf("calendar %s does not exist", name)
+	}
+	delete(s.calendars, name)
+	return s.save()
+}
+
+func (s *calendarStore) save() error {
+	if len(s.path) == 0 {
+		return nil
+	}
+	calendars := []Calendar{}
+	for _, calendar := range s.calendars {
+		calendars = append(calendars, calendar)
+	}
+	sort.Slice(calendars, func(i, j int) bool { return calendars[i].Name < calendars[j].Name })
+	js, _ := json.MarshalIndent(calendars, "", "  ")
+	return ioutil.WriteFile(s.path, js, 0600)
+}
+
+// Validate returns an error if the calendar cannot be evaluated.
+func (c Calendar) Validate() error {
+	if !calendarNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid calendar name %s", c.Name)
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %s", c.TimeZone)
+	}
+	for _, day := range c.Weekdays {
+		if _, ok := parseWeekday(day); !ok {
+			return fmt.Errorf("invalid weekday %s", day)
+		}
+	}
+	for _, r := range c.Ranges {
+		from, to, err := r.bounds(time.UTC)
+		if err != nil {
+			return err
+		}
+		if !to.After(from) {
+			return fmt.Errorf("range %s ends before it starts", r.From)
+		}
+	}
+	return nil
+}
+
+// Excludes returns whether the time falls within the calendar together with the reason.
+func (c Calendar) Excludes(t time.Time) (string, bool) {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	t = t.In(location)
+	for _, day := range c.Weekdays {
+		if weekday, ok := parseWeekday(day); ok && weekday == t.Weekday() {
+			return weekday.String(), true
+		}
+	}
+	for _, r := range c.Ranges {
+		from, to, err := r.bounds(location)
+		if err != nil || t.Before(from) || !t.Before(to) {
+			continue
+		}
+		if len(r.Name) > 0 {
+			return r.Name, true
+		}
+		if len(r.To) > 0 {
+			return fmt.Sprintf("%s - %s", r.From, r.To), true
+		}
+		return r.From, true
+	}
+	return "", false
+}
+
+// bounds returns the start of the range and the time right after it ends.
+func (r DateRange) bounds(location *time.Location) (time.Time, time.Time, error) {
+	from, fromDate, err := parseRangeTime(r.From, location)
+	if err != nil {
+		return from, from, err
+	}
+	to, toDate := from, fromDate
+	if len(r.To) > 0 {
+		if to, toDate, err = parseRangeTime(r.To, location); err != nil {
+			return from, to, err
+		}
+	}
+	if toDate {
+		return from, to.AddDate(0, 0, 1), nil
+	}
+	// Jobs run at whole seconds, so the second of the timestamp is included.
+	return from, to.Add(time.Second), nil
+}
+
+func parseRangeTime(value string, location *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(dateLayout, value, location); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, false, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC3339 timestamp", value)
+	}
+	return t, false, nil
+}
+
+func parseWeekday(value string) (time.Weekday, bool) {
+	for i, day := range weekdays {
+		if strings.EqualFold(value, day) || strings.EqualFold(value, day[:3]) {
+			return time.Weekday(i), true
+		}
+	}
+	return time.Sunday, false
+}
+
+// excludedBy returns the reason the time is excluded by one of the calendars.
+func excludedBy(store CalendarStore, names []string, t time.Time) (string, bool) {
+	if store == nil {
+		return "", false
+	}
+	for _, name := range names {
+		calendar, ok := store.Get(name)
+		if !ok {
+			continue
+		}
+		if reason, ok := calendar.Excludes(t); ok {
+			return fmt.Sprintf("excluded by calendar %s: %s", name, reason), true
+		}
+	}
+	return "", false
+}
+
+// SkippedTicks returns the times, out of the given ones, that are excluded by the calendars.
+func SkippedTicks(store CalendarStore, names []string, times []time.Time) []SkippedTick {
+	skipped := []SkippedTick{}
+	for _, t := range times {
+		if reason, ok := excludedBy(store, names, t); ok {
+			skipped = append(skipped, SkippedTick{Time: t, Reason: reason})
+		}
+	}
+	return skipped
+}
+
+// validateCalendars checks that the calendars excluded by the job exist.
+func validateCalendars(store CalendarStore, data JobData) error {
+	for _, name := range data.ExcludeCalendars {
+		if store == nil {
+			return fmt.Errorf("calendar %s does not exist", name)
+		}
+		if _, ok := store.Get(name); !ok {
+			return fmt.Errorf("calendar %s does not exist", name)
+		}
+	}
+	return nil
+}
+
+// ParseICal converts the events of an iCalendar file, e.g. a list of public holidays, into date ranges.
+// Recurring events are imported with their first occurrence only and reported as warnings.
+func ParseICal(content []byte) ([]DateRange, []string, error) {
+	ranges := []DateRange{}
+	warnings := []string{}
+	lines := unfoldICal(content)
+	var event map[string]string
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = map[string]string{}
+		case line == "END:VEVENT":
+			if event == nil {
+				continue
+			}
+			r, err := icalRange(event)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, ok := event["RRULE"]; ok {
+				warnings = append(warnings, fmt.Sprintf("event %s recurs and only its first occurrence was imported", r.Name))
+			}
+			ranges = append(ranges, r)
+			event = nil
+		case event != nil:
+			i := strings.Index(line, ":")
+			if i < 0 {
+				continue
+			}
+			key, value := line[:i], line[i+1:]
+			if j := strings.Index(key, ";"); j >= 0 {
+				if strings.Contains(key[j:], "VALUE=DATE") && !strings.Contains(key[j:], "VALUE=DATE-TIME") {
+					value = "DATE:" + value
+				}
+				key = key[:j]
+			}
+			event[key] = value
+		}
+	}
+	if len(ranges) == 0 {
+		return nil, nil, fmt.Errorf("the file does not contain events")
+	}
+	return ranges, warnings, nil
+}
+
+// unfoldICal joins the lines that iCalendar splits with a leading space.
+func unfoldICal(content []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func icalRange(event map[string]string) (DateRange, error) {
+	r := DateRange{Name: strings.Replace(event["SUMMARY"], `\,`, ",", -1)}
+	start, startDate, err := icalTime(event["DTSTART"])
+	if err != nil {
+		return r, fmt.Errorf("event %s: %s", r.Name, err.Error())
+	}
+	if startDate {
+		r.From = start.Format(dateLayout)
+	} else {
+		r.From = start.Format(time.RFC3339)
+	}
+	if len(event["DTEND"]) == 0 {
+		return r, nil
+	}
+	end, endDate, err := icalTime(event["DTEND"])
+	if err != nil {
+		return r, fmt.Errorf("event %s: %s", r.Name, err.Error())
+	}
+	if endDate {
+		// The end date of all-day events is exclusive.
+		if last := end.AddDate(0, 0, -1); last.After(start) {
+			r.To = last.Format(dateLayout)
+		}
+	} else {
+		r.To = end.Add(-time.Second).Format(time.RFC3339)
+	}
+	return r, nil
+}
+
+func icalTime(value string) (time.Time, bool, error) {
+	if strings.HasPrefix(value, "DATE:") {
+		t, err := time.Parse("20060102", strings.TrimPrefix(value, "DATE:"))
+		return t, true, err
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return t, false, fmt.Errorf("invalid time %s", value)
+	}
+	return t, false, nil
+}
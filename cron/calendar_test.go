@@ -0,0 +1,184 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type CalendarTestSuite struct {
+	suite.Suite
+}
+
+func TestCalendarUnitTestSuite(t *testing.T) {
+	s := new(CalendarTestSuite)
+	suite.Run(t, s)
+}
+
+// Excludes
+
+func (s *CalendarTestSuite) Test_Excludes_MatchesWeekdays() {
+	calendar := Calendar{Name: "weekend", Weekdays: []string{"sat", "Sunday"}}
+
+	reason, ok := calendar.Excludes(time.Date(2017, 5, 6, 10, 0, 0, 0, time.UTC))
+
+	s.True(ok)
+	s.Equal("Saturday", reason)
+	_, ok = calendar.Excludes(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	s.False(ok)
+}
+
+func (s *CalendarTestSuite) Test_Excludes_MatchesWholeDaysInTimeZone() {
+	calendar := Calendar{
+		Name:     "freeze",
+		TimeZone: "Europe/Berlin",
+		Ranges:   []DateRange{{Name: "Release freeze", From: "2017-12-20", To: "2017-12-27"}},
+	}
+
+	reason, ok := calendar.Excludes(time.Date(2017, 12, 27, 22, 30, 0, 0, time.UTC))
+
+	s.True(ok)
+	s.Equal("Release freeze", reason)
+	_, ok = calendar.Excludes(time.Date(2017, 12, 27, 23, 30, 0, 0, time.UTC))
+	s.False(ok)
+	_, ok = calendar.Excludes(time.Date(2017, 12, 19, 22, 59, 0, 0, time.UTC))
+	s.False(ok)
+}
+
+func (s *CalendarTestSuite) Test_Excludes_MatchesTimestamps() {
+	calendar := Calendar{Name: "maintenance", Ranges: []DateRange{{From: "2017-05-05T10:00:00Z", To: "2017-05-05T12:00:00Z"}}}
+
+	reason, ok := calendar.Excludes(time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC))
+
+	s.True(ok)
+	s.Equal("2017-05-05T10:00:00Z - 2017-05-05T12:00:00Z", reason)
+	_, ok = calendar.Excludes(time.Date(2017, 5, 5, 12, 0, 1, 0, time.UTC))
+	s.False(ok)
+}
+
+// Validate
+
+func (s *CalendarTestSuite) Test_Validate_ReturnsError_WhenCalendarIsInvalid() {
+	s.NoError(Calendar{Name: "holidays"}.Validate())
+	s.Error(Calendar{Name: "my holidays"}.Validate())
+	s.Error(Calendar{Name: "holidays", TimeZone: "Mars/Olympus"}.Validate())
+	s.Error(Calendar{Name: "holidays", Weekdays: []string{"Caturday"}}.Validate())
+	s.Error(Calendar{Name: "holidays", Ranges: []DateRange{{From: "December"}}}.Validate())
+	s.Error(Calendar{Name: "holidays", Ranges: []DateRange{{From: "2017-05-05", To: "2017-05-01"}}}.Validate())
+}
+
+// NewCalendarStore
+
+func (s *CalendarTestSuite) Test_NewCalendarStore_PersistsCalendars() {
+	dir, _ := ioutil.TempDir("", "calendars")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calendars.json")
+	store, _ := NewCalendarStore(path)
+
+	s.NoError(store.Put(Calendar{Name: "weekend", Weekdays: []string{"Saturday", "Sunday"}}))
+	s.NoError(store.Put(Calendar{Name: "holidays", Ranges: []DateRange{{From: "2017-12-25"}}}))
+	s.NoError(store.Remove("weekend"))
+	loaded, err := NewCalendarStore(path)
+
+	s.NoError(err)
+	s.Equal([]Calendar{{Name: "holidays", Ranges: []DateRange{{From: "2017-12-25"}}}}, loaded.List())
+}
+
+func (s *CalendarTestSuite) Test_Put_ReturnsError_WhenCalendarIsInvalid() {
+	store, _ := NewCalendarStore("")
+
+	s.Error(store.Put(Calendar{Name: "weekend", Weekdays: []string{"Caturday"}}))
+	s.Empty(store.List())
+}
+
+// ParseICal
+
+func (s *CalendarTestSuite) Test_ParseICal_ConvertsEvents() {
+	content := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20171225\r\n" +
+		"DTEND;VALUE=DATE:20171227\r\n" +
+		"SUMMARY:Christmas\r\n" +
+		"  holidays\r\n" +
+		"RRULE:FREQ=YEARLY\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20180101\r\n" +
+		"DTEND:20180102\r\n" +
+		"SUMMARY:New Year\\, day 1\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20180105T220000Z\r\n" +
+		"DTEND:20180106T020000Z\r\n" +
+		"SUMMARY:Maintenance\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	ranges, warnings, err := ParseICal([]byte(content))
+
+	s.NoError(err)
+	s.Equal([]DateRange{
+		{Name: "Christmas holidays", From: "2017-12-25", To: "2017-12-26"},
+		{Name: "New Year, day 1", From: "2018-01-01"},
+		{Name: "Maintenance", From: "2018-01-05T22:00:00Z", To: "2018-01-06T01:59:59Z"},
+	}, ranges)
+	s.Equal([]string{"event Christmas holidays recurs and only its first occurrence was imported"}, warnings)
+}
+
+func (s *CalendarTestSuite) Test_ParseICal_ReturnsError_WhenThereAreNoEvents() {
+	_, _, err := ParseICal([]byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n"))
+
+	s.Error(err)
+}
+
+// AddJob
+
+func (s *CalendarTestSuite) Test_AddJob_ReturnsError_WhenCalendarDoesNotExist() {
+	store, _ := NewCalendarStore("")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, Calendars: store}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", ExcludeCalendars: []string{"holidays"}, Created: true})
+
+	s.EqualError(err, "calendar holidays does not exist")
+}
+
+// tick
+
+func (s *CalendarTestSuite) Test_Tick_RecordsSkippedRun_WhenTimeIsExcluded() {
+	timeNow = func() time.Time {
+		return time.Date(2017, 12, 25, 10, 0, 0, 0, time.UTC)
+	}
+	defer func() { timeNow = time.Now }()
+	store, _ := NewCalendarStore("")
+	store.Put(Calendar{Name: "holidays", Ranges: []DateRange{{Name: "Christmas", From: "2017-12-25"}}})
+	history, _ := NewHistorian("")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{"my-job": 1}, Calendars: store, History: history}
+
+	c.tick(JobData{Name: "my-job", ExcludeCalendars: []string{"holidays"}, MaxRuns: 1}, "my-job")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.Len(runs, 1)
+	s.Equal(RunSkipped, runs[0].Status)
+	s.Equal("excluded by calendar holidays: Christmas", runs[0].Message)
+	s.Equal(0, c.runCount("my-job"))
+}
+
+// SkippedTicks
+
+func (s *CalendarTestSuite) Test_SkippedTicks_ReturnsExcludedTimes() {
+	store, _ := NewCalendarStore("")
+	store.Put(Calendar{Name: "weekend", Weekdays: []string{"Saturday", "Sunday"}})
+	times := []time.Time{
+		time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 6, 10, 0, 0, 0, time.UTC),
+	}
+
+	actual := SkippedTicks(store, []string{"weekend"}, times)
+
+	s.Equal([]SkippedTick{{Time: times[1], Reason: "excluded by calendar weekend: Saturday"}}, actual)
+}
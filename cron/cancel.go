@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"fmt"
+)
+
+// NotFoundError is returned when a job or a run does not exist.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// ConflictError is returned when an operation does not fit the current state of a run.
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// CancelRun stops the task of a running run and records the run as cancelled by the caller.
+// In the per-run execution mode, the service of the run is removed. Containers started by the container executor are removed as well, and requests of http jobs are aborted.
+// Runs of global jobs and exec jobs cannot be cancelled.
+// Catch-up runs that would follow the cancelled run are not started.
+func (c *Cron) CancelRun(jobName, runID, by string) (Run, error) {
+	if c.History == nil {
+		return Run{}, &NotFoundError{Message: "the history of runs is not available"}
+	}
+	runs, err := c.History.Runs(jobName, 0)
+	if err != nil {
+		return Run{}, err
+	}
+	run, found := Run{}, false
+	for _, r := range runs {
+		if r.ID == runID {
+			run, found = r, true
+			break
+		}
+	}
+	if !found {
+		return Run{}, &NotFoundError{Message: fmt.Sprintf("run %s of job %s does not exist", runID, jobName)}
+	}
+	if run.Status != RunRunning {
+		return run, &ConflictError{Message: fmt.Sprintf("run %s of job %s is not running: %s", runID, jobName, run.Status)}
+	}
+	if job, ok := c.getLocalJobs()[jobName]; ok && job.Type == TypeExec {
+		return run, &ConflictError{Message: fmt.Sprintf("runs of jobs of the %s type cannot be cancelled", TypeExec)}
+	}
+	fmt.Println("Cancelling run", runID, "of", jobName, "by", by)
+	cancelRequest, isRequest := c.takeRequest(run.ID)
+	if isRequest {
+		// The request is cancelled once the run is marked as cancelled
+	} else if len(run.Container) > 0 {
+		if err := c.Containers.RemoveContainer(run.Container); err != nil {
+			return run, fmt.Errorf("could not remove container %s: %s", run.Container, err.Error())
+		}
+	} else if len(run.Service) > 0 {
+		if err := c.Service.RemoveService(run.Service); err != nil {
+			return run, fmt.Errorf("could not remove service %s: %s", run.Service, err.Error())
+		}
+	} else {
+		services, err := c.Service.GetServices(jobName)
+		if err != nil {
+			return run, err
+		}
+		if len(services) == 0 {
+			return run, &NotFoundError{Message: fmt.Sprintf("job %s does not exist", jobName)}
+		}
+		if services[0].Spec.Mode.GlobalJob != nil {
+			return run, &ConflictError{Message: fmt.Sprintf("runs of jobs in the %s mode cannot be cancelled", ModeGlobal)}
+		}
+		serviceName := services[0].Spec.Name
+		if err := c.Service.ScaleService(serviceName, 0); err != nil {
+			return run, fmt.Errorf("could not stop service %s: %s", serviceName, err.Error())
+		}
+	}
+	run.CancelledBy = by
+	run.finish(RunCancelled, fmt.Sprintf("cancelled by %s", by), timeNow().UTC())
+	c.setCancelled(run)
+	c.recordRun(run)
+	if isRequest {
+		cancelRequest()
+	}
+	return run, nil
+}
+
+// setCancelled tells the watcher of the run that it was cancelled.
+func (c *Cron) setCancelled(run Run) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancelled == nil {
+		c.cancelled = map[string]Run{}
+	}
+	c.cancelled[run.ID] = run
+}
+
+// takeCancelled returns the run if it was cancelled and forgets it.
+func (c *Cron) takeCancelled(runID string) (Run, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	run, ok := c.cancelled[runID]
+	if ok {
+		delete(c.cancelled, runID)
+	}
+	return run, ok
+}
@@ -0,0 +1,179 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+)
+
+type CancelTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestCancelUnitTestSuite(t *testing.T) {
+	s := new(CancelTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *CancelTestSuite) SetupTest() {
+	s.now = time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *CancelTestSuite) TearDownTest() {
+	timeNow = time.Now
+}
+
+// CancelRun
+
+func (s *CancelTestSuite) Test_CancelRun_ScalesServiceToZeroAndRecordsRun() {
+	actualService := ""
+	actualReplicas := uint64(1)
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		actualService = serviceName
+		actualReplicas = replicas
+		return nil
+	})
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: s.now.Add(-time.Minute)})
+
+	run, err := c.CancelRun("my-job", "1", "ops")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.NoError(err)
+	s.Equal("my-service", actualService)
+	s.Equal(uint64(0), actualReplicas)
+	s.Equal(RunCancelled, run.Status)
+	s.Equal("ops", run.CancelledBy)
+	s.Equal("cancelled by ops", run.Message)
+	s.Equal("1m0s", run.Duration)
+	s.Equal(run, runs[0])
+}
+
+func (s *CancelTestSuite) Test_CancelRun_RemovesServiceOfRun() {
+	scaled := false
+	actualRemoved := ""
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		scaled = true
+		return nil
+	})
+	service := c.Service.(ServicerMock)
+	service.RemoveServiceMock = func(serviceName string) error {
+		actualRemoved = serviceName
+		return nil
+	}
+	c.Service = service
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: s.now, Service: "my-service-1"})
+
+	run, err := c.CancelRun("my-job", "1", "ops")
+
+	s.NoError(err)
+	s.False(scaled)
+	s.Equal("my-service-1", actualRemoved)
+	s.Equal(RunCancelled, run.Status)
+}
+
+func (s *CancelTestSuite) Test_CancelRun_StopsWatcher() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		return nil
+	})
+	run := Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: s.now}
+	history.Record(run)
+	c.CancelRun("my-job", "1", "ops")
+	finished := make(chan Run)
+
+	go c.watchRun(run, func(run Run) {
+		finished <- run
+	})
+
+	select {
+	case actual := <-finished:
+		s.Equal(RunCancelled, actual.Status)
+	case <-time.After(time.Second):
+		s.Fail("the watcher did not stop")
+	}
+}
+
+func (s *CancelTestSuite) Test_CancelRun_ReturnsNotFoundError_WhenRunDoesNotExist() {
+	c, _ := s.newCron(nil)
+
+	_, err := c.CancelRun("my-job", "1", "ops")
+
+	_, ok := err.(*NotFoundError)
+	s.True(ok)
+}
+
+func (s *CancelTestSuite) Test_CancelRun_ReturnsConflictError_WhenRunIsNotRunning() {
+	scaled := false
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		scaled = true
+		return nil
+	})
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunSucceeded, StartedAt: s.now})
+
+	_, err := c.CancelRun("my-job", "1", "ops")
+
+	_, ok := err.(*ConflictError)
+	s.True(ok)
+	s.False(scaled)
+}
+
+func (s *CancelTestSuite) Test_CancelRun_ReturnsConflictError_WhenJobIsGlobal() {
+	scaled := false
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		scaled = true
+		return nil
+	})
+	c.Service = ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			service := swarm.Service{}
+			service.Spec.Name = "my-service"
+			service.Spec.Mode.GlobalJob = &swarm.GlobalJob{}
+			return []swarm.Service{service}, nil
+		},
+	}
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: s.now})
+
+	_, err := c.CancelRun("my-job", "1", "ops")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.IsType(&ConflictError{}, err)
+	s.False(scaled)
+	s.Equal(RunRunning, runs[0].Status)
+}
+
+func (s *CancelTestSuite) Test_CancelRun_ReturnsError_WhenScaleFails() {
+	c, history := s.newCron(func(serviceName string, replicas uint64) error {
+		return fmt.Errorf("This is an error")
+	})
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: s.now})
+
+	_, err := c.CancelRun("my-job", "1", "ops")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.Error(err)
+	s.Equal(RunRunning, runs[0].Status)
+}
+
+// Util
+
+func (s *CancelTestSuite) newCron(scale func(serviceName string, replicas uint64) error) (*Cron, Historian) {
+	history, _ := NewHistorian("")
+	service := ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			service := swarm.Service{}
+			service.Spec.Name = "my-service"
+			return []swarm.Service{service}, nil
+		},
+		ScaleServiceMock: scale,
+	}
+	return &Cron{Service: service, History: history}, history
+}
@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+const (
+	CatchUpNone = "none"
+	CatchUpLast = "last"
+	CatchUpAll  = "all"
+)
+
+// catchUpDefaultLimit is the number of missed runs fired by the `all` policy when no limit is set.
+const catchUpDefaultLimit = 10
+
+// catchUpMaxTicks limits the number of ticks inspected when looking for missed runs.
+const catchUpMaxTicks = 100000
+
+// validateCatchUp checks the starting deadline and the catch-up policy of a job.
+func validateCatchUp(data JobData) error {
+	if len(data.StartingDeadline) > 0 {
+		deadline, err := time.ParseDuration(data.StartingDeadline)
+		if err != nil {
+			return fmt.Errorf("invalid startingDeadline %s: %s", data.StartingDeadline, err.Error())
+		}
+		if deadline <= 0 {
+			return fmt.Errorf("startingDeadline must be positive")
+		}
+	}
+	switch data.CatchUp {
+	case "", CatchUpNone, CatchUpLast, CatchUpAll:
+	default:
+		return fmt.Errorf("catchUp must be one of %s, %s or %s", CatchUpNone, CatchUpLast, CatchUpAll)
+	}
+	if data.CatchUpLimit < 0 {
+		return fmt.Errorf("catchUpLimit cannot be negative")
+	}
+	if data.CatchUpLimit > 0 && data.CatchUp != CatchUpAll {
+		return fmt.Errorf("catchUpLimit can only be used together with catchUp %s", CatchUpAll)
+	}
+	return nil
+}
+
+// missedRuns returns the times the job should have run after the given time and before now,
+// filtered by the starting deadline and the catch-up policy of the job.
+// Times excluded by the calendars of the job are skipped.
+func missedRuns(data JobData, calendars CalendarStore, since, now time.Time) ([]time.Time, error) {
+	if len(data.CatchUp) == 0 || data.CatchUp == CatchUpNone {
+		return []time.Time{}, nil
+	}
+	schedule, err := jobSchedule(data)
+	if err != nil {
+		return nil, err
+	}
+	earliest := since
+	if len(data.StartingDeadline) > 0 {
+		deadline, _ := time.ParseDuration(data.StartingDeadline)
+		if cutoff := now.Add(-deadline); cutoff.After(earliest) {
+			earliest = cutoff
+		}
+	}
+	limit := 1
+	if data.CatchUp == CatchUpAll {
+		limit = catchUpDefaultLimit
+		if data.CatchUpLimit > 0 {
+			limit = data.CatchUpLimit
+		}
+	}
+	// Long downtimes of frequent schedules have more ticks than can be inspected, so the ticks are walked from
+	// a time close to now, which moves back until enough missed runs are found or it reaches the earliest time
+	for window := time.Minute; ; window *= 2 {
+		from := now.Add(-window)
+		if !from.After(earliest) {
+			from = earliest
+		}
+		// The job already ran at the time it was last run at, but not at the other times the walk starts from
+		start := from.Add(-time.Nanosecond)
+		if !start.After(since) {
+			start = since
+		}
+		times := missedRunsAfter(data, calendars, schedule, start, now, limit)
+		if len(times) >= limit || from.Equal(earliest) {
+			return times, nil
+		}
+	}
+}
+
+// missedRunsAfter returns up to limit latest times the schedule fires at after start and before now.
+func missedRunsAfter(data JobData, calendars CalendarStore, schedule rcron.Schedule, start, now time.Time, limit int) []time.Time {
+	times := []time.Time{}
+	for t, i := schedule.Next(start), 0; !t.IsZero() && t.Before(now) && i < catchUpMaxTicks; t, i = schedule.Next(t), i+1 {
+		if !inWindow(data, t) {
+			continue
+		}
+		if _, ok := excludedBy(calendars, data.ExcludeCalendars, t); ok {
+			continue
+		}
+		if notAfter, err := time.Parse(time.RFC3339, data.NotAfter); err == nil && t.After(notAfter) {
+			break
+		}
+		times = append(times, t)
+		if len(times) > limit {
+			times = times[1:]
+		}
+	}
+	return times
+}
+
+// catchUp fires the runs of the job that were missed since its last recorded run.
+// Jobs without recorded runs are not caught up since there is nothing to tell when the scheduler stopped.
+func (c *Cron) catchUp(data JobData, serviceName string) {
+	if _, ok := c.entry(data.Name); !ok || len(data.RunAt) > 0 || c.History == nil || c.GetMaintenance().Paused {
+		return
+	}
+	runs, err := c.History.Runs(data.Name, 1)
+	if err != nil || len(runs) == 0 {
+		return
+	}
+	since := runs[0].StartedAt
+	if runs[0].ScheduledAt != nil {
+		since = *runs[0].ScheduledAt
+	}
+	times, err := missedRuns(data, c.Calendars, since, timeNow())
+	if err != nil || len(times) == 0 {
+		return
+	}
+	fmt.Println("Catching up", len(times), "missed runs of", data.Name)
+	go c.runCatchUp(data, serviceName, times)
+}
+
+// runCatchUp fires the missed runs one after another.
+// It stops early and expires the job when it reaches the maximum number of runs.
+func (c *Cron) runCatchUp(data JobData, serviceName string, times []time.Time) {
+	if reason := c.expiryReason(data, timeNow()); len(reason) > 0 {
+		c.expire(data, reason)
+		return
+	}
+	if len(times) == 0 {
+		return
+	}
+	run := newRun(data.Name, TriggerCatchUp)
+	scheduledAt := times[0].UTC()
+	run.ScheduledAt = &scheduledAt
+	c.startRun(run, data, serviceName, func(run Run) {
+		if run.Status == RunCancelled {
+			fmt.Println("Stopping the catch-up of", data.Name, "since run", run.ID, "was cancelled")
+			return
+		}
+		c.runCatchUp(data, serviceName, times[1:])
+	})
+}
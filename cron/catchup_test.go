@@ -0,0 +1,162 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type CatchUpTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestCatchUpUnitTestSuite(t *testing.T) {
+	s := new(CatchUpTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *CatchUpTestSuite) SetupTest() {
+	s.now = time.Date(2017, 5, 5, 10, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *CatchUpTestSuite) TearDownTest() {
+	timeNow = time.Now
+}
+
+// validateCatchUp
+
+func (s *CatchUpTestSuite) Test_ValidateCatchUp_AcceptsPolicies() {
+	s.NoError(validateCatchUp(JobData{}))
+	s.NoError(validateCatchUp(JobData{CatchUp: CatchUpNone}))
+	s.NoError(validateCatchUp(JobData{CatchUp: CatchUpLast, StartingDeadline: "10m"}))
+	s.NoError(validateCatchUp(JobData{CatchUp: CatchUpAll, CatchUpLimit: 3}))
+}
+
+func (s *CatchUpTestSuite) Test_ValidateCatchUp_ReturnsError_WhenFieldsAreInvalid() {
+	s.Error(validateCatchUp(JobData{CatchUp: "some"}))
+	s.Error(validateCatchUp(JobData{StartingDeadline: "soon"}))
+	s.Error(validateCatchUp(JobData{StartingDeadline: "-1m"}))
+	s.Error(validateCatchUp(JobData{CatchUp: CatchUpAll, CatchUpLimit: -1}))
+	s.Error(validateCatchUp(JobData{CatchUp: CatchUpLast, CatchUpLimit: 3}))
+}
+
+// missedRuns
+
+func (s *CatchUpTestSuite) Test_MissedRuns_ReturnsNothing_WhenPolicyIsNone() {
+	since := s.now.Add(-5 * time.Hour)
+
+	actual, err := missedRuns(JobData{Schedule: "@hourly"}, nil, since, s.now)
+
+	s.NoError(err)
+	s.Empty(actual)
+}
+
+func (s *CatchUpTestSuite) Test_MissedRuns_ReturnsLastRun_WhenPolicyIsLast() {
+	since := time.Date(2017, 5, 5, 6, 0, 0, 0, time.UTC)
+
+	actual, err := missedRuns(JobData{Schedule: "@hourly", CatchUp: CatchUpLast}, nil, since, s.now)
+
+	s.NoError(err)
+	s.Equal([]time.Time{time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)}, actual)
+}
+
+func (s *CatchUpTestSuite) Test_MissedRuns_ReturnsLatestRuns_WhenPolicyIsAll() {
+	since := time.Date(2017, 5, 5, 6, 0, 0, 0, time.UTC)
+
+	actual, err := missedRuns(JobData{Schedule: "@hourly", CatchUp: CatchUpAll, CatchUpLimit: 3}, nil, since, s.now)
+
+	s.NoError(err)
+	s.Equal([]time.Time{
+		time.Date(2017, 5, 5, 8, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+	}, actual)
+}
+
+func (s *CatchUpTestSuite) Test_MissedRuns_SkipsRuns_WhenStartingDeadlinePassed() {
+	since := time.Date(2017, 5, 5, 6, 0, 0, 0, time.UTC)
+	job := JobData{Schedule: "@hourly", CatchUp: CatchUpAll, StartingDeadline: "2h"}
+
+	actual, err := missedRuns(job, nil, since, s.now)
+
+	s.NoError(err)
+	s.Equal([]time.Time{
+		time.Date(2017, 5, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+	}, actual)
+
+	job.StartingDeadline = "10m"
+	actual, _ = missedRuns(job, nil, since, s.now)
+
+	s.Empty(actual)
+}
+
+func (s *CatchUpTestSuite) Test_MissedRuns_ReturnsLatestRuns_WhenDowntimeIsLong() {
+	since := s.now.Add(-30 * 24 * time.Hour)
+	job := JobData{Schedule: "@every 1s", CatchUp: CatchUpAll, CatchUpLimit: 2}
+
+	actual, err := missedRuns(job, nil, since, s.now)
+
+	s.NoError(err)
+	s.Equal([]time.Time{s.now.Add(-2 * time.Second), s.now.Add(-time.Second)}, actual)
+
+	job.StartingDeadline = "1h"
+	actual, _ = missedRuns(job, nil, since, s.now)
+
+	s.Equal([]time.Time{s.now.Add(-2 * time.Second), s.now.Add(-time.Second)}, actual)
+}
+
+func (s *CatchUpTestSuite) Test_MissedRuns_IncludesRunAtStartingDeadline() {
+	since := time.Date(2017, 5, 5, 6, 0, 0, 0, time.UTC)
+	job := JobData{Schedule: "0 0 * * * *", CatchUp: CatchUpAll, StartingDeadline: "90m"}
+
+	actual, err := missedRuns(job, nil, since, s.now)
+
+	s.NoError(err)
+	s.Equal([]time.Time{
+		time.Date(2017, 5, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+	}, actual)
+}
+
+// catchUp
+
+func (s *CatchUpTestSuite) Test_CatchUp_DoesNothing_WhenThereIsNoHistory() {
+	history, _ := NewHistorian("")
+	c := Cron{History: history, Jobs: map[string]rcron.EntryID{"my-job": 1}}
+
+	c.catchUp(JobData{Name: "my-job", Schedule: "@hourly", CatchUp: CatchUpAll}, "my-job")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.Empty(runs)
+}
+
+// runCatchUp
+
+func (s *CatchUpTestSuite) Test_RunCatchUp_RecordsRunsAsCatchUp() {
+	timeNow = func() time.Time {
+		s.now = s.now.Add(time.Second)
+		return s.now
+	}
+	history, _ := NewHistorian("")
+	c := Cron{History: history}
+	times := []time.Time{
+		time.Date(2017, 5, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+	}
+
+	c.runCatchUp(JobData{Name: "my-job"}, "my-job-service-that-does-not-exist", times)
+	runs, _ := history.Runs("my-job", 0)
+
+	s.Len(runs, 2)
+	for i, run := range runs {
+		s.Equal(TriggerCatchUp, run.Trigger)
+		s.Equal(times[len(times)-1-i], *run.ScheduledAt)
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"../docker"
 	"fmt"
 	"github.com/docker/docker/api/types/swarm"
+	"golang.org/x/net/context"
 	rcron "gopkg.in/robfig/cron.v2"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 const dockerApiVersion = "v1.24"
@@ -17,26 +22,100 @@ type Croner interface {
 	GetJobs() (map[string]JobData, error)
 	RemoveJob(jobName string) error
 	RescheduleJobs() error
+	Plan(jobs []JobData) (Plan, error)
+	Apply(plan Plan) error
+	GetState(jobName string) JobState
+	Pause(until *time.Time, by string) error
+	Resume() error
+	GetMaintenance() Maintenance
+	CancelRun(jobName, runID, by string) (Run, error)
+	TriggerJob(jobName, by string) (Run, error)
+	SuspendJob(jobName, by string) error
+	ResumeJob(jobName string) error
+	GetRuns(jobName string, limit int) ([]Run, error)
 }
 
 type Cron struct {
-	Cron    *rcron.Cron
-	Service docker.Servicer
-	Jobs    map[string]rcron.EntryID
+	Cron      *rcron.Cron
+	Service   docker.Servicer
+	Jobs      map[string]rcron.EntryID
+	Policy    *Policy
+	History   Historian
+	Calendars CalendarStore
+	// Executor is the executor of jobs that do not set one. Defaults to swarm.
+	Executor   string
+	Containers docker.Containerer
+	Execer     docker.Execer
+
+	// mu guards Jobs and the fields below
+	mu        sync.Mutex
+	completed map[string]time.Time
+	suspended map[string]string
+	runs      map[string]int
+	cancelled map[string]Run
+	running   bool
+	jobMode   bool
+
+	localJobs     map[string]JobData
+	localJobsFile string
+	requests      map[string]context.CancelFunc
+
+	maintenance      Maintenance
+	maintenanceFile  string
+	maintenanceTimer *time.Timer
 }
 
 var rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
 	return c.AddFunc(spec, cmd)
 }
 
+var createServiceFunc = func(cmd string) ([]byte, error) {
+	return exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
+}
+
+var updateServiceFunc = func(cmd string) ([]byte, error) {
+	return exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
+}
+
+// runWatchInterval is the interval at which the tasks of a running job are checked.
+var runWatchInterval = 5 * time.Second
+
+// runWatchTimeout is the maximum time a run is watched before it is marked as failed.
+var runWatchTimeout = 24 * time.Hour
+
 type JobData struct {
-	Name           string   `json:"name"`
-	ServiceName    string   `json:"servicename"`
-	Image          string   `json:"image"`
-	Command        string   `json:"command"`
-	Schedule       string   `json:"schedule"`
-	Args           []string `json:"args"`
-	Created        bool     `json:"created`
+	Name             string       `json:"name"`
+	ServiceName      string       `json:"servicename"`
+	Image            string       `json:"image"`
+	Command          string       `json:"command"`
+	Schedule         string       `json:"schedule"`
+	Args             []string     `json:"args"`
+	Created          bool         `json:"created`
+	ManagedBy        string       `json:"managedBy,omitempty"`
+	RunAt            string       `json:"runAt,omitempty"`
+	TTL              string       `json:"ttl,omitempty"`
+	StartingDeadline string       `json:"startingDeadline,omitempty"`
+	CatchUp          string       `json:"catchUp,omitempty"`
+	CatchUpLimit     int          `json:"catchUpLimit,omitempty"`
+	Jitter           string       `json:"jitter,omitempty"`
+	NotBefore        string       `json:"notBefore,omitempty"`
+	NotAfter         string       `json:"notAfter,omitempty"`
+	MaxRuns          int          `json:"maxRuns,omitempty"`
+	OnExpiry         string       `json:"onExpiry,omitempty"`
+	ExcludeCalendars []string     `json:"excludeCalendars,omitempty"`
+	ExecutionMode    string       `json:"executionMode,omitempty"`
+	Retention        int          `json:"retention,omitempty"`
+	MaxConcurrent    int          `json:"maxConcurrent,omitempty"`
+	TotalCompletions int          `json:"totalCompletions,omitempty"`
+	Mode             string       `json:"mode,omitempty"`
+	Parallelism      int          `json:"parallelism,omitempty"`
+	Completions      int          `json:"completions,omitempty"`
+	Executor         string       `json:"executor,omitempty"`
+	Type             string       `json:"type,omitempty"`
+	HTTP             *HTTPRequest `json:"http,omitempty"`
+	Exec             *ExecCommand `json:"exec,omitempty"`
+	NodeLabels       []string     `json:"nodeLabels,omitempty"`
+	State            *JobState    `json:"state,omitempty"`
 }
 
 var New = func(dockerHost string) (Croner, error) {
@@ -46,7 +125,9 @@ var New = func(dockerHost string) (Croner, error) {
 	}
 	c := rcron.New()
 	c.Start()
-	return &Cron{Cron: c, Service: service, Jobs: map[string]rcron.EntryID{}}, nil
+	history, _ := NewHistorian("")
+	calendars, _ := NewCalendarStore("")
+	return &Cron{Cron: c, Service: service, Jobs: map[string]rcron.EntryID{}, History: history, Calendars: calendars, running: true, jobMode: service.JobModeSupported(), Containers: service, Execer: service}, nil
 }
 
 func (c *Cron) AddJob(data JobData) error {
@@ -54,80 +135,245 @@ func (c *Cron) AddJob(data JobData) error {
 	if data.Args == nil {
 		data.Args = []string{}
 	}
-	if len(data.Name) == 0 {
-		return fmt.Errorf("name is mandatory")
-	}
-	if len(data.Image) == 0 {
-		return fmt.Errorf("image is mandatory")
+	data.Schedule = NormalizeSchedule(data.Schedule)
+	if err := c.validateJob(data); err != nil {
+		return err
 	}
-	/*if len(data.Schedule) == 0 {
-		return fmt.Errorf("schedule is mandatory")
-	}*/
 
 	serviceName := data.Name
 	if data.ServiceName != "" {
 		serviceName = data.ServiceName
 	}
 
-	if !data.Created{
+	if c.isLocalJob(data) {
+		if err := c.setLocalJob(data); err != nil {
+			return err
+		}
+	} else if !data.Created {
 		cmdPrefix := "docker service create"
 		hasRestartCondition := false
-		cmdSuffix := ""
-		for _, v := range data.Args {
+		// Arguments and the command are split and quoted again so that the shell runs what the policy checked
+		args, err := splitShellWords(strings.Join(data.Args, " "))
+		if err != nil {
+			return fmt.Errorf("args are invalid: %s", err.Error())
+		}
+		command, err := splitShellWords(data.Command)
+		if err != nil {
+			return fmt.Errorf("command is invalid: %s", err.Error())
+		}
+		for i, v := range args {
 			if strings.HasPrefix(v, "--restart-condition") {
-				if strings.Contains(v, "any") {
+				condition := strings.TrimPrefix(strings.TrimPrefix(v, "--restart-condition"), "=")
+				if len(condition) == 0 && i+1 < len(args) {
+					condition = args[i+1]
+				}
+				if condition == "any" {
 					return fmt.Errorf("--restart-condition cannot be set to any")
 				}
 				hasRestartCondition = true
 			} else if strings.HasPrefix(v, "--name") {
 				return fmt.Errorf("--name argument is not allowed")
 			}
-			cmdSuffix = fmt.Sprintf("%s %s", cmdSuffix, v)
 		}
 		if !hasRestartCondition {
-			cmdSuffix = fmt.Sprintf("%s --restart-condition none", cmdSuffix)
+			args = append(args, "--restart-condition", "none")
 		}
-		cmdSuffix = fmt.Sprintf("%s %s %s", cmdSuffix, data.Image, data.Command)
+		cmdSuffix := " " + shellJoin(append(append(args, data.Image), command...))
 		cmdLabel := fmt.Sprintf(
-			`-l 'com.df.cron.command=%s%s'`,
-			cmdPrefix,
-			cmdSuffix,
+			`-l %s`,
+			shellQuote(fmt.Sprintf("com.df.cron.command=%s%s", cmdPrefix, cmdSuffix)),
 		)
+		if len(data.RunAt) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.run-at=%s"`, cmdLabel, data.RunAt)
+		}
+		if len(data.TTL) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.ttl=%s"`, cmdLabel, data.TTL)
+		}
+		if len(data.StartingDeadline) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.starting-deadline=%s"`, cmdLabel, data.StartingDeadline)
+		}
+		if len(data.CatchUp) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.catch-up=%s"`, cmdLabel, data.CatchUp)
+		}
+		if data.CatchUpLimit > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.catch-up-limit=%d"`, cmdLabel, data.CatchUpLimit)
+		}
+		if len(data.Jitter) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.jitter=%s"`, cmdLabel, data.Jitter)
+		}
+		if len(data.NotBefore) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.not-before=%s"`, cmdLabel, data.NotBefore)
+		}
+		if len(data.NotAfter) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.not-after=%s"`, cmdLabel, data.NotAfter)
+		}
+		if data.MaxRuns > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.max-runs=%d"`, cmdLabel, data.MaxRuns)
+		}
+		if len(data.OnExpiry) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.on-expiry=%s"`, cmdLabel, data.OnExpiry)
+		}
+		if len(data.ExcludeCalendars) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.exclude-calendars=%s"`, cmdLabel, strings.Join(data.ExcludeCalendars, ","))
+		}
+		if len(data.ExecutionMode) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.execution-mode=%s"`, cmdLabel, data.ExecutionMode)
+		}
+		if data.Retention > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.retention=%d"`, cmdLabel, data.Retention)
+		}
+		if data.MaxConcurrent > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.max-concurrent=%d"`, cmdLabel, data.MaxConcurrent)
+		}
+		if data.TotalCompletions > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.total-completions=%d"`, cmdLabel, data.TotalCompletions)
+		}
+		if len(data.Mode) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.mode=%s"`, cmdLabel, data.Mode)
+		}
+		if data.Parallelism > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.parallelism=%d"`, cmdLabel, data.Parallelism)
+		}
+		if data.Completions > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.completions=%d"`, cmdLabel, data.Completions)
+		}
+		if len(data.Executor) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.executor=%s"`, cmdLabel, data.Executor)
+		}
+		for _, env := range parallelEnv(data) {
+			cmdLabel = fmt.Sprintf(`%s --env %s`, cmdLabel, shellQuote(env))
+		}
+		if len(data.NodeLabels) > 0 {
+			cmdLabel = fmt.Sprintf(`%s -l "com.df.cron.node-labels=%s"`, cmdLabel, strings.Join(data.NodeLabels, ","))
+			for _, constraint := range nodeConstraints(data.NodeLabels) {
+				cmdLabel = fmt.Sprintf(`%s --constraint %s`, cmdLabel, shellQuote(constraint))
+			}
+		}
+		if len(data.ManagedBy) > 0 {
+			cmdLabel = fmt.Sprintf(
+				`%s -l %s -l %s`,
+				cmdLabel,
+				shellQuote("com.df.cron.managed-by="+data.ManagedBy),
+				shellQuote("com.df.cron.hash="+jobHash(data)),
+			)
+		}
+		// Job mode services with no completions are created without running
+		mode := "--replicas 0"
+		if data.Mode == ModeGlobal {
+			mode = "--mode global-job"
+		} else if c.jobMode {
+			mode = "--mode replicated-job --replicas 0"
+		}
 		cmd := fmt.Sprintf(
-			`%s -l "com.df.cron=true" -l "com.df.cron.name=%s" -l "com.df.cron.schedule=%s" --name %s --replicas 0 %s %s`,
+			`%s -l "com.df.cron=true" -l "com.df.cron.name=%s" -l "com.df.cron.schedule=%s" --name %s %s %s %s`,
 			cmdPrefix,
 			data.Name,
 			data.Schedule,
 			serviceName,
+			mode,
 			cmdLabel,
 			strings.Trim(cmdSuffix, " "),
 		)
 
 		fmt.Println("Executing command:", cmd)
 
-		_, err := exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
-		if err != nil { // TODO: Test
-		       fmt.Println("Could not execute command: ", cmd, err.Error())
+		out, err := createServiceFunc(cmd)
+		if err != nil {
+			fmt.Println("Could not execute command: ", cmd, err.Error())
+			return fmt.Errorf("could not create the service %s: %s %s", serviceName, err.Error(), strings.TrimSpace(string(out)))
 		}
 	}
 
+	if len(data.RunAt) > 0 {
+		return c.scheduleOnce(data, serviceName)
+	}
+	if _, ok := c.suspendedReason(data.Name); ok {
+		return nil
+	}
+	if reason := c.expiryReason(data, timeNow()); len(reason) > 0 {
+		c.expire(data, reason)
+		return nil
+	}
 	cronCmd := func() {
-		scale := fmt.Sprintf(`docker service scale %s=1`, serviceName)
-		fmt.Println(scale)
-		_, err := exec.Command("/bin/sh", "-c", scale).CombinedOutput()
-		if err != nil { // TODO: Test
-			fmt.Println("Could not execute command: ", scale)
-		}
+		c.tick(data, serviceName)
+	}
+	if len(data.Jitter) > 0 {
+		schedule, _ := jobSchedule(data)
+		c.setEntry(data.Name, rCronScheduleFunc(c.Cron, schedule, cronCmd))
+		c.scheduleExpiry(data)
+		return nil
 	}
-	entryId, err := rCronAddFunc(c.Cron, data.Schedule, cronCmd)
-	c.Jobs[data.Name] = entryId
+	resolved, _ := ResolveSchedule(data.Schedule, data.Name)
+	entryId, err := rCronAddFunc(c.Cron, resolved, cronCmd)
+	c.setEntry(data.Name, entryId)
+	c.scheduleExpiry(data)
 	return err
 }
 
+// validateJob returns an error when the job cannot be scheduled.
+func (c *Cron) validateJob(data JobData) error {
+	if len(data.Name) == 0 {
+		return fmt.Errorf("name is mandatory")
+	}
+	if len(data.Image) == 0 && runsImage(data) {
+		return fmt.Errorf("image is mandatory")
+	}
+	if err := validateImage(data); err != nil {
+		return err
+	}
+	if len(data.ManagedBy) > 0 && data.ManagedBy != ManagedByFile {
+		return fmt.Errorf("managedBy must be empty or %s", ManagedByFile)
+	}
+	if len(data.RunAt) > 0 || len(data.TTL) > 0 {
+		if err := validateRunAt(data); err != nil {
+			return err
+		}
+	} else if _, err := jobSchedule(data); err != nil {
+		return err
+	}
+	if err := validateJitter(data); err != nil {
+		return err
+	}
+	if err := validateWindow(data); err != nil {
+		return err
+	}
+	if err := validateCalendars(c.Calendars, data); err != nil {
+		return err
+	}
+	if err := validateCatchUp(data); err != nil {
+		return err
+	}
+	if err := validateExecutionMode(data); err != nil {
+		return err
+	}
+	if err := validateCompletions(data); err != nil {
+		return err
+	}
+	if err := c.validateMode(data); err != nil {
+		return err
+	}
+	if err := c.validateParallelism(data); err != nil {
+		return err
+	}
+	if err := c.validateExecutor(data); err != nil {
+		return err
+	}
+	if err := validateHTTP(data); err != nil {
+		return err
+	}
+	if err := validateExec(data); err != nil {
+		return err
+	}
+	if err := c.Policy.Evaluate(data); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Cron) GetJobs() (map[string]JobData, error) {
 	jobs := map[string]JobData{}
 	services, err := c.Service.GetServices("")
-	if err != nil {
+	if err != nil && c.Executor != ExecutorContainer {
 		return jobs, err
 	}
 	for _, service := range services {
@@ -140,35 +386,314 @@ func (c *Cron) GetJobs() (map[string]JobData, error) {
 			}
 		}
 		name := service.Spec.Annotations.Labels["com.df.cron.name"]
-		jobs[name] = c.getJob(service)
+		job := c.getJob(service)
+		state := c.GetState(name)
+		job.State = &state
+		jobs[name] = job
+	}
+	for name, job := range c.getLocalJobs() {
+		state := c.GetState(name)
+		job.State = &state
+		jobs[name] = job
 	}
 	return jobs, nil
 }
 
+// GetState returns the next and the previous run of the job and the last recorded run.
+func (c *Cron) GetState(jobName string) JobState {
+	state := JobState{}
+	id, scheduled := c.entry(jobName)
+	if scheduled {
+		state.Status = JobScheduled
+	}
+	if completedAt, ok := c.completedAt(jobName); ok {
+		state.Status = JobCompleted
+		state.CompletedAt = &completedAt
+	}
+	if reason, ok := c.suspendedReason(jobName); ok {
+		state.Status = JobSuspended
+		state.Reason = reason
+	}
+	if scheduled && c.Cron != nil {
+		entry := c.Cron.Entry(id)
+		if !entry.Next.IsZero() {
+			state.NextRun = &entry.Next
+		}
+		if !entry.Prev.IsZero() {
+			state.PrevRun = &entry.Prev
+		}
+	}
+	if c.History != nil {
+		if runs, err := c.History.Runs(jobName, 1); err == nil && len(runs) > 0 {
+			state.LastRun = &runs[0]
+			if state.PrevRun == nil {
+				state.PrevRun = &runs[0].StartedAt
+			}
+		}
+	}
+	return state
+}
+
 func (c *Cron) RemoveJob(jobName string) error {
 	fmt.Println("Removing job", jobName)
-	c.Cron.Remove(c.Jobs[jobName])
+	c.removeEntry(jobName)
+	c.mu.Lock()
+	delete(c.completed, jobName)
+	delete(c.suspended, jobName)
+	delete(c.runs, jobName)
+	_, isLocalJob := c.localJobs[jobName]
+	delete(c.localJobs, jobName)
+	c.mu.Unlock()
+	if isLocalJob {
+		return c.saveLocalJobs()
+	}
 	if err := c.Service.RemoveServices(jobName); err != nil {
 		return err
 	}
+	if c.jobMode {
+		return c.saveLocalJobs()
+	}
 	return nil
 }
 
 func (c *Cron) RescheduleJobs() error {
 	fmt.Println("Rescheduling jobs")
-	jobs, err := c.GetJobs()
-	if err != nil {
+	services, err := c.Service.GetServices("")
+	if err != nil && c.Executor != ExecutorContainer {
 		return err
 	}
-	for _, job := range jobs {
-		c.AddJob(job)
+	for _, service := range services {
+		job := c.getJob(service)
+		if completedAt, err := time.Parse(time.RFC3339, service.Spec.Annotations.Labels["com.df.cron.completed"]); err == nil {
+			c.setCompleted(job.Name, completedAt)
+		}
+		if reason, ok := service.Spec.Annotations.Labels["com.df.cron.suspended"]; ok {
+			c.setSuspended(job.Name, reason)
+		}
+		if err := c.AddJob(job); err == nil {
+			c.catchUp(job, service.Spec.Name)
+		}
+	}
+	for _, job := range c.getLocalJobs() {
+		if err := c.AddJob(job); err != nil {
+			fmt.Println("Could not schedule job", job.Name, err.Error())
+			continue
+		}
+		serviceName := job.Name
+		if len(job.ServiceName) > 0 {
+			serviceName = job.ServiceName
+		}
+		c.catchUp(job, serviceName)
+	}
+	if !c.GetMaintenance().Paused {
+		c.startDispatcher()
 	}
-	c.Cron.Start()
 	return nil
 }
 
+// setEntry records the entry that schedules the job.
+func (c *Cron) setEntry(jobName string, id rcron.EntryID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Jobs[jobName] = id
+}
+
+// entry returns the entry that schedules the job and whether the job is scheduled.
+func (c *Cron) entry(jobName string) (rcron.EntryID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.Jobs[jobName]
+	return id, ok
+}
+
+// entries returns a copy of the entries of all scheduled jobs.
+func (c *Cron) entries() map[string]rcron.EntryID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := map[string]rcron.EntryID{}
+	for name, id := range c.Jobs {
+		entries[name] = id
+	}
+	return entries
+}
+
+// removeEntry stops scheduling the job.
+func (c *Cron) removeEntry(jobName string) {
+	c.mu.Lock()
+	id, ok := c.Jobs[jobName]
+	delete(c.Jobs, jobName)
+	c.mu.Unlock()
+	if ok {
+		c.Cron.Remove(id)
+	}
+}
+
+// runJob starts the service of the job and watches it until its task finishes.
+// The done function, if set, is invoked with the finished run.
+func (c *Cron) runJob(data JobData, serviceName, trigger string, done func(Run)) {
+	c.startRun(newRun(data.Name, trigger), data, serviceName, done)
+}
+
+// startRun starts the run with the executor of the job.
+func (c *Cron) startRun(run Run, data JobData, serviceName string, done func(Run)) {
+	c.executor(data).Execute(run, data, serviceName, done)
+}
+
+// startSwarmRun scales the service of the job and watches the run until its task finishes.
+// Jobs in the per-run execution mode get a new service instead.
+func (c *Cron) startSwarmRun(run Run, data JobData, serviceName string, done func(Run)) {
+	if data.ExecutionMode == ExecutionPerRun {
+		c.startRunService(run, data, serviceName, done)
+		return
+	}
+	c.countRun(run.Job)
+	if c.jobMode {
+		maxConcurrent, completions := jobConcurrency(data)
+		iteration, err := c.Service.RunJob(serviceName, uint64(maxConcurrent), uint64(completions))
+		if err == nil {
+			run.iteration = iteration
+			run.completions = completions
+			run.global = data.Mode == ModeGlobal
+			c.recordRun(run)
+			go c.watchRun(run, done)
+			return
+		} else if err != docker.ErrNotJob {
+			fmt.Println("Could not run job", serviceName, err.Error())
+			run.finish(RunFailed, fmt.Sprintf("could not run service %s: %s", serviceName, err.Error()), timeNow().UTC())
+			c.recordRun(run)
+			if done != nil {
+				done(run)
+			}
+			return
+		}
+		// Services created before the daemon supported job mode are scaled
+	}
+	replicas, completions := scaledConcurrency(data)
+	run.completions = completions
+	scale := fmt.Sprintf(`docker service scale %s=%d`, serviceName, replicas)
+	fmt.Println(scale)
+	_, err := exec.Command("/bin/sh", "-c", scale).CombinedOutput()
+	if err != nil { // TODO: Test
+		fmt.Println("Could not execute command: ", scale)
+		run.finish(RunFailed, fmt.Sprintf("could not scale service %s: %s", serviceName, err.Error()), timeNow().UTC())
+		c.recordRun(run)
+		if done != nil {
+			done(run)
+		}
+		return
+	}
+	c.recordRun(run)
+	go c.watchRun(run, done)
+}
+
+// watchRun waits for the task started by the run to finish and records the outcome.
+func (c *Cron) watchRun(run Run, done func(Run)) {
+	defer func() {
+		if done != nil {
+			done(run)
+		}
+	}()
+	deadline := run.StartedAt.Add(runWatchTimeout)
+	for timeNow().Before(deadline) {
+		time.Sleep(runWatchInterval)
+		if cancelled, ok := c.takeCancelled(run.ID); ok {
+			run = cancelled
+			return
+		}
+		tasks, err := c.Service.GetTasks(run.Job)
+		if err != nil {
+			continue
+		}
+		if len(run.serviceID) > 0 {
+			tasks = serviceTasks(tasks, run.serviceID)
+		}
+		if run.global {
+			if status, message, finishedAt, nodes, ok := globalOutcome(tasks, run.iteration); ok {
+				run.Nodes = nodes
+				run.finish(status, message, finishedAt)
+				c.recordRun(run)
+				return
+			}
+			continue
+		}
+		if run.iteration > 0 {
+			if status, message, finishedAt, ok := iterationOutcome(tasks, run.iteration, run.completions); ok {
+				run.finish(status, message, finishedAt)
+				c.recordRun(run)
+				return
+			}
+			continue
+		}
+		if run.completions > 1 {
+			if status, message, finishedAt, ok := tasksOutcome(tasksCreatedAfter(tasks, run.StartedAt), run.completions); ok {
+				run.finish(status, message, finishedAt)
+				c.recordRun(run)
+				return
+			}
+			continue
+		}
+		if task, ok := latestTask(tasks, run.StartedAt); ok {
+			switch task.Status.State {
+			case swarm.TaskStateComplete:
+				run.finish(RunSucceeded, "", task.Status.Timestamp.UTC())
+			case swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateShutdown, swarm.TaskStateOrphaned:
+				run.finish(RunFailed, task.Status.Err, task.Status.Timestamp.UTC())
+			default:
+				continue
+			}
+			c.recordRun(run)
+			return
+		}
+	}
+	run.finish(RunFailed, fmt.Sprintf("the task did not finish within %s", runWatchTimeout), timeNow().UTC())
+	c.recordRun(run)
+}
+
+func (c *Cron) recordRun(run Run) {
+	if c.History == nil {
+		return
+	}
+	if err := c.History.Record(run); err != nil {
+		fmt.Println("Could not record run of", run.Job, err.Error())
+	}
+}
+
+// skipRun records that the job did not run at the time set by its schedule.
+func (c *Cron) skipRun(jobName, reason string, scheduledAt time.Time) {
+	fmt.Println("Skipping", jobName, "at", scheduledAt, "because of", reason)
+	scheduledAt = scheduledAt.UTC()
+	run := Run{
+		ID:          strconv.FormatInt(scheduledAt.UnixNano(), 10),
+		Job:         jobName,
+		Trigger:     TriggerSchedule,
+		ScheduledAt: &scheduledAt,
+		StartedAt:   scheduledAt,
+	}
+	run.finish(RunSkipped, reason, scheduledAt)
+	c.recordRun(run)
+}
+
+// latestTask returns the newest task created after the given time.
+func latestTask(tasks []swarm.Task, after time.Time) (swarm.Task, bool) {
+	latest := swarm.Task{}
+	found := false
+	for _, task := range tasks {
+		if task.CreatedAt.Before(after.Add(-time.Second)) {
+			continue
+		}
+		if !found || task.CreatedAt.After(latest.CreatedAt) {
+			latest = task
+			found = true
+		}
+	}
+	return latest, found
+}
+
 func (c *Cron) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.Cron.Stop()
+	c.running = false
 }
 
 func (c *Cron) getJob(service swarm.Service) JobData {
@@ -181,11 +706,55 @@ func (c *Cron) getJob(service swarm.Service) JobData {
 		}
 	}
 	name := service.Spec.Annotations.Labels["com.df.cron.name"]
+	catchUpLimit, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.catch-up-limit"])
+	maxRuns, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.max-runs"])
+	retention, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.retention"])
+	maxConcurrent, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.max-concurrent"])
+	totalCompletions, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.total-completions"])
+	parallelism, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.parallelism"])
+	completions, _ := strconv.Atoi(service.Spec.Annotations.Labels["com.df.cron.completions"])
+	var nodeLabels []string
+	if value := service.Spec.Annotations.Labels["com.df.cron.node-labels"]; len(value) > 0 {
+		nodeLabels = strings.Split(value, ",")
+	}
+	var excludeCalendars []string
+	if value := service.Spec.Annotations.Labels["com.df.cron.exclude-calendars"]; len(value) > 0 {
+		excludeCalendars = strings.Split(value, ",")
+	}
 	return JobData{
-		Name:     name,
-		ServiceName: service.Spec.Name,
-		Image:    service.Spec.TaskTemplate.ContainerSpec.Image,
-		Command:  service.Spec.Annotations.Labels["com.df.cron.command"],
-		Schedule: service.Spec.Annotations.Labels["com.df.cron.schedule"],
+		Name:             name,
+		ServiceName:      service.Spec.Name,
+		Image:            service.Spec.TaskTemplate.ContainerSpec.Image,
+		Command:          service.Spec.Annotations.Labels["com.df.cron.command"],
+		Schedule:         service.Spec.Annotations.Labels["com.df.cron.schedule"],
+		ManagedBy:        service.Spec.Annotations.Labels["com.df.cron.managed-by"],
+		RunAt:            service.Spec.Annotations.Labels["com.df.cron.run-at"],
+		TTL:              service.Spec.Annotations.Labels["com.df.cron.ttl"],
+		StartingDeadline: service.Spec.Annotations.Labels["com.df.cron.starting-deadline"],
+		CatchUp:          service.Spec.Annotations.Labels["com.df.cron.catch-up"],
+		CatchUpLimit:     catchUpLimit,
+		Jitter:           service.Spec.Annotations.Labels["com.df.cron.jitter"],
+		NotBefore:        service.Spec.Annotations.Labels["com.df.cron.not-before"],
+		NotAfter:         service.Spec.Annotations.Labels["com.df.cron.not-after"],
+		MaxRuns:          maxRuns,
+		OnExpiry:         service.Spec.Annotations.Labels["com.df.cron.on-expiry"],
+		ExcludeCalendars: excludeCalendars,
+		ExecutionMode:    service.Spec.Annotations.Labels["com.df.cron.execution-mode"],
+		Retention:        retention,
+		MaxConcurrent:    maxConcurrent,
+		TotalCompletions: totalCompletions,
+		Mode:             service.Spec.Annotations.Labels["com.df.cron.mode"],
+		NodeLabels:       nodeLabels,
+		Parallelism:      parallelism,
+		Completions:      completions,
+		Executor:         service.Spec.Annotations.Labels["com.df.cron.executor"],
 	}
 }
+
+// validateImage rejects images that the shell would split into several words or that docker would read as a flag.
+func validateImage(data JobData) error {
+	if strings.HasPrefix(data.Image, "-") || strings.IndexFunc(data.Image, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("image %s is invalid", data.Image)
+	}
+	return nil
+}
@@ -205,6 +205,27 @@ func (s CronTestSuite) Test_AddJob_ThrowsAnError_WhenNameIsEmpty() {
 	s.Error(err)
 }
 
+func (s CronTestSuite) Test_AddJob_ReturnsError_WhenServiceCannotBeCreated() {
+	createServiceFuncOrig := createServiceFunc
+	defer func() { createServiceFunc = createServiceFuncOrig }()
+	createServiceFunc = func(cmd string) ([]byte, error) {
+		return []byte("unknown flag: --mode"), fmt.Errorf("exit status 125")
+	}
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	scheduled := false
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		scheduled = true
+		return 1, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@yearly"})
+
+	s.EqualError(err, "could not create the service my-job: exit status 125 unknown flag: --mode")
+	s.False(scheduled)
+}
+
 // GetJobs
 
 func (s CronTestSuite) Test_GetJobs_ReturnsListOfJobs() {
@@ -448,9 +469,16 @@ func (s CronTestSuite) removeAllServices() {
 }
 
 type ServicerMock struct {
-	GetServicesMock    func(jobName string) ([]swarm.Service, error)
-	GetTasksMock       func(jobName string) ([]swarm.Task, error)
-	RemoveServicesMock func(jobName string) error
+	GetServicesMock      func(jobName string) ([]swarm.Service, error)
+	GetTasksMock         func(jobName string) ([]swarm.Task, error)
+	RemoveServicesMock   func(jobName string) error
+	ScaleServiceMock     func(serviceName string, replicas uint64) error
+	RunServiceMock       func(serviceName, runName string, labels map[string]string) (string, error)
+	GetRunServicesMock   func(jobName string) ([]swarm.Service, error)
+	RemoveServiceMock    func(serviceName string) error
+	JobModeSupportedMock func() bool
+	RunJobMock           func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error)
+	CreateServiceMock    func(spec swarm.ServiceSpec) error
 }
 
 func (m ServicerMock) GetServices(jobName string) ([]swarm.Service, error) {
@@ -464,3 +492,31 @@ func (m ServicerMock) GetTasks(jobName string) ([]swarm.Task, error) {
 func (m ServicerMock) RemoveServices(jobName string) error {
 	return m.RemoveServicesMock(jobName)
 }
+
+func (m ServicerMock) ScaleService(serviceName string, replicas uint64) error {
+	return m.ScaleServiceMock(serviceName, replicas)
+}
+
+func (m ServicerMock) RunService(serviceName, runName string, labels map[string]string) (string, error) {
+	return m.RunServiceMock(serviceName, runName, labels)
+}
+
+func (m ServicerMock) GetRunServices(jobName string) ([]swarm.Service, error) {
+	return m.GetRunServicesMock(jobName)
+}
+
+func (m ServicerMock) RemoveService(serviceName string) error {
+	return m.RemoveServiceMock(serviceName)
+}
+
+func (m ServicerMock) JobModeSupported() bool {
+	return m.JobModeSupportedMock()
+}
+
+func (m ServicerMock) RunJob(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+	return m.RunJobMock(serviceName, maxConcurrent, totalCompletions)
+}
+
+func (m ServicerMock) CreateService(spec swarm.ServiceSpec) error {
+	return m.CreateServiceMock(spec)
+}
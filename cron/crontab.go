@@ -0,0 +1,178 @@
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const crontabDirective = "dfcron:"
+
+type CrontabOptions struct {
+	// Image is used by jobs that do not override it.
+	Image string
+	// Prefix is prepended to generated job names.
+	Prefix string
+	// System should be set for files in the /etc/crontab format which contain the user field.
+	System bool
+}
+
+// Import contains jobs converted from other formats and the problems found during the conversion.
+type Import struct {
+	Jobs     []JobData `json:"jobs"`
+	Warnings []string  `json:"warnings"`
+}
+
+var crontabEnvRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+var jobNameRegexp = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ParseCrontab converts the lines of a crontab file into jobs.
+//
+// Environment lines are added as `--env` arguments to all the jobs that follow them.
+// The `%` characters in commands are converted the same way cron does it;
+// the text after the first unescaped `%` is sent to the command as its standard input.
+// A comment in the format `# dfcron: key=value ...` overrides the fields of the job defined on the next line.
+// Supported keys are `name`, `image`, `servicename` and `arg`, which can be repeated.
+func ParseCrontab(content string, options CrontabOptions) (Import, error) {
+	result := Import{Jobs: []JobData{}, Warnings: []string{}}
+	env := []string{}
+	overrides := map[string][]string{}
+	names := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			comment := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			if strings.HasPrefix(comment, crontabDirective) {
+				overrides = parseDirective(strings.TrimPrefix(comment, crontabDirective))
+			}
+			continue
+		}
+		if matches := crontabEnvRegexp.FindStringSubmatch(line); matches != nil {
+			env = append(env, fmt.Sprintf("%s=%s", matches[1], strings.Trim(matches[2], `"'`)))
+			continue
+		}
+		schedule, command, err := splitCrontabLine(line, options.System)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %s", lineNumber, err.Error())
+		}
+		if schedule == "@reboot" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("line %d: @reboot is not supported and was skipped", lineNumber))
+			overrides = map[string][]string{}
+			continue
+		}
+		job := JobData{
+			Name:     crontabJobName(options.Prefix, command, lineNumber),
+			Image:    options.Image,
+			Command:  crontabCommand(command),
+			Schedule: schedule,
+			Args:     []string{},
+		}
+		for _, e := range env {
+			job.Args = append(job.Args, fmt.Sprintf("--env %s", shellQuote(e)))
+		}
+		if values := overrides["name"]; len(values) > 0 {
+			job.Name = values[0]
+		}
+		if values := overrides["image"]; len(values) > 0 {
+			job.Image = values[0]
+		}
+		if values := overrides["servicename"]; len(values) > 0 {
+			job.ServiceName = values[0]
+		}
+		job.Args = append(job.Args, overrides["arg"]...)
+		overrides = map[string][]string{}
+		if len(job.Image) == 0 {
+			return result, fmt.Errorf("line %d: image is mandatory", lineNumber)
+		}
+		if names[job.Name] {
+			return result, fmt.Errorf("line %d: job %s is defined more than once", lineNumber, job.Name)
+		}
+		names[job.Name] = true
+		result.Jobs = append(result.Jobs, job)
+	}
+	return result, scanner.Err()
+}
+
+// splitCrontabLine returns the schedule in the six-field format, with seconds, and the command.
+func splitCrontabLine(line string, system bool) (string, string, error) {
+	fields := strings.Fields(line)
+	scheduleFields := 5
+	if strings.HasPrefix(fields[0], "@") {
+		scheduleFields = 1
+	}
+	if system {
+		scheduleFields++
+	}
+	if len(fields) <= scheduleFields {
+		return "", "", fmt.Errorf("expected a schedule followed by a command")
+	}
+	command := line
+	for i := 0; i < scheduleFields; i++ {
+		command = strings.TrimSpace(command)
+		command = command[strings.IndexAny(command, " \t"):]
+	}
+	command = strings.TrimSpace(command)
+	if scheduleFields == 1 || (system && scheduleFields == 2) {
+		return fields[0], command, nil
+	}
+	return "0 " + strings.Join(fields[:5], " "), command, nil
+}
+
+// crontabCommand wraps the command into a shell so that pipes and redirects keep working inside the container.
+func crontabCommand(command string) string {
+	parts := []string{}
+	current := ""
+	for i := 0; i < len(command); i++ {
+		switch {
+		case command[i] == '\\' && i+1 < len(command) && command[i+1] == '%':
+			current += "%"
+			i++
+		case command[i] == '%':
+			parts = append(parts, current)
+			current = ""
+		default:
+			current += string(command[i])
+		}
+	}
+	parts = append(parts, current)
+	script := parts[0]
+	if len(parts) > 1 {
+		lines := []string{}
+		for _, line := range parts[1:] {
+			lines = append(lines, shellQuote(line))
+		}
+		script = fmt.Sprintf(`printf '%%s\n' %s | %s`, strings.Join(lines, " "), script)
+	}
+	return fmt.Sprintf("sh -c %s", shellQuote(script))
+}
+
+func crontabJobName(prefix, command string, lineNumber int) string {
+	if len(prefix) == 0 {
+		prefix = "crontab"
+	}
+	base := path.Base(strings.Fields(command)[0])
+	name := fmt.Sprintf("%s-%s-%d", prefix, base, lineNumber)
+	return strings.Trim(jobNameRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// parseDirective parses space separated key=value pairs. Values can be quoted.
+func parseDirective(value string) map[string][]string {
+	directive := map[string][]string{}
+	re := regexp.MustCompile(`([a-z]+)=("[^"]*"|'[^']*'|\S+)`)
+	for _, match := range re.FindAllStringSubmatch(value, -1) {
+		directive[match[1]] = append(directive[match[1]], strings.Trim(match[2], `"'`))
+	}
+	return directive
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,117 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CrontabTestSuite struct {
+	suite.Suite
+}
+
+func TestCrontabUnitTestSuite(t *testing.T) {
+	s := new(CrontabTestSuite)
+	suite.Run(t, s)
+}
+
+// ParseCrontab
+
+func (s *CrontabTestSuite) Test_ParseCrontab_ConvertsLinesIntoJobs() {
+	content := `
+# m h dom mon dow command
+*/5 * * * * /usr/local/bin/cleanup.sh > /dev/null 2>&1
+@daily backup --all
+`
+
+	actual, err := ParseCrontab(content, CrontabOptions{Image: "acme/tools"})
+
+	s.NoError(err)
+	s.Equal([]JobData{
+		{
+			Name:     "crontab-cleanup-sh-3",
+			Image:    "acme/tools",
+			Command:  `sh -c '/usr/local/bin/cleanup.sh > /dev/null 2>&1'`,
+			Schedule: "0 */5 * * * *",
+			Args:     []string{},
+		},
+		{
+			Name:     "crontab-backup-4",
+			Image:    "acme/tools",
+			Command:  `sh -c 'backup --all'`,
+			Schedule: "@daily",
+			Args:     []string{},
+		},
+	}, actual.Jobs)
+	s.Empty(actual.Warnings)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_AddsEnvironmentToFollowingJobs() {
+	content := `0 1 * * * first
+MAILTO=""
+DB_HOST = "db.acme.com"
+0 2 * * * second`
+
+	actual, _ := ParseCrontab(content, CrontabOptions{Image: "alpine", Prefix: "ops"})
+
+	s.Equal([]string{}, actual.Jobs[0].Args)
+	s.Equal([]string{`--env 'MAILTO='`, `--env 'DB_HOST=db.acme.com'`}, actual.Jobs[1].Args)
+	s.Equal("ops-second-4", actual.Jobs[1].Name)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_ConvertsPercentSigns() {
+	content := `0 1 * * * date +\%Y-\%m-\%d%first line%it's second`
+
+	actual, _ := ParseCrontab(content, CrontabOptions{Image: "alpine"})
+
+	s.Equal(`sh -c 'printf '\''%s\n'\'' '\''first line'\'' '\''it'\''\'\'''\''s second'\'' | date +%Y-%m-%d'`, actual.Jobs[0].Command)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_SkipsReboot() {
+	content := `@reboot start-agent
+0 1 * * * other`
+
+	actual, _ := ParseCrontab(content, CrontabOptions{Image: "alpine"})
+
+	s.Len(actual.Jobs, 1)
+	s.Equal([]string{"line 1: @reboot is not supported and was skipped"}, actual.Warnings)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_AppliesDirectivesToNextJob() {
+	content := `# dfcron: name=nightly-backup image="acme/backup:1.0" arg="--network backups" arg=--limit-memory=1G
+0 2 * * * backup
+0 3 * * * report`
+
+	actual, _ := ParseCrontab(content, CrontabOptions{Image: "alpine"})
+
+	s.Equal("nightly-backup", actual.Jobs[0].Name)
+	s.Equal("acme/backup:1.0", actual.Jobs[0].Image)
+	s.Equal([]string{"--network backups", "--limit-memory=1G"}, actual.Jobs[0].Args)
+	s.Equal("alpine", actual.Jobs[1].Image)
+	s.Equal([]string{}, actual.Jobs[1].Args)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_SkipsUserField_WhenSystemIsSet() {
+	content := `17 * * * * root cd / && run-parts --report /etc/cron.hourly
+@weekly root run-parts /etc/cron.weekly`
+
+	actual, err := ParseCrontab(content, CrontabOptions{Image: "alpine", System: true})
+
+	s.NoError(err)
+	s.Equal("0 17 * * * *", actual.Jobs[0].Schedule)
+	s.Equal(`sh -c 'cd / && run-parts --report /etc/cron.hourly'`, actual.Jobs[0].Command)
+	s.Equal("@weekly", actual.Jobs[1].Schedule)
+	s.Equal(`sh -c 'run-parts /etc/cron.weekly'`, actual.Jobs[1].Command)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_ReturnsError_WhenImageIsNotSet() {
+	_, err := ParseCrontab("0 1 * * * backup", CrontabOptions{})
+
+	s.Error(err)
+}
+
+func (s *CrontabTestSuite) Test_ParseCrontab_ReturnsError_WhenCommandIsMissing() {
+	_, err := ParseCrontab("0 1 * * *", CrontabOptions{Image: "alpine"})
+
+	s.Error(err)
+}
@@ -0,0 +1,145 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// ReplicasOne runs the command in a single container of the service
+	ReplicasOne = "one"
+	// ReplicasAll runs the command in every container of the service
+	ReplicasAll = "all"
+)
+
+// execOutputLimit is the number of bytes of the output of a command stored in the history
+const execOutputLimit = 1024
+
+// ExecCommand describes the command run by a job of the exec type.
+type ExecCommand struct {
+	Service  string `json:"service"`
+	Command  string `json:"command"`
+	Replicas string `json:"replicas,omitempty"`
+}
+
+// ExecResult describes how the command of an exec job ended in a single container.
+type ExecResult struct {
+	Container string `json:"container"`
+	ExitCode  int64  `json:"exitCode"`
+	Output    string `json:"output,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func validateExec(data JobData) error {
+	if data.Type != TypeExec {
+		if data.Exec != nil {
+			return fmt.Errorf("exec can be used only with the %s type", TypeExec)
+		}
+		return nil
+	}
+	if data.Exec == nil {
+		return fmt.Errorf("exec is mandatory for jobs of the %s type", TypeExec)
+	}
+	if len(data.Image) > 0 || len(data.Command) > 0 || len(data.Args) > 0 || len(data.Executor) > 0 {
+		return fmt.Errorf("image, command, args and executor cannot be used with the %s type", TypeExec)
+	}
+	if len(data.ExecutionMode) > 0 || len(data.Mode) > 0 || len(data.NodeLabels) > 0 ||
+		data.Parallelism > 0 || data.Completions > 0 || data.MaxConcurrent > 0 || data.TotalCompletions > 0 {
+		return fmt.Errorf("jobs of the %s type cannot be used with modes, parallelism or completions", TypeExec)
+	}
+	if len(data.Exec.Service) == 0 {
+		return fmt.Errorf("exec service is mandatory")
+	}
+	if cmd, err := splitShellWords(data.Exec.Command); err != nil {
+		return err
+	} else if len(cmd) == 0 {
+		return fmt.Errorf("exec command is mandatory")
+	}
+	switch data.Exec.Replicas {
+	case "", ReplicasOne, ReplicasAll:
+		return nil
+	}
+	return fmt.Errorf("exec replicas must be %s or %s", ReplicasOne, ReplicasAll)
+}
+
+type execExecutor struct {
+	c *Cron
+}
+
+// Execute runs the command of the job in one or all running containers of the target service.
+func (e execExecutor) Execute(run Run, data JobData, serviceName string, done func(Run)) {
+	c := e.c
+	c.countRun(run.Job)
+	c.recordRun(run)
+	go func() {
+		results, err := c.execCommand(*data.Exec)
+		if err != nil {
+			run.finish(RunFailed, err.Error(), timeNow().UTC())
+		} else {
+			run.Execs = results
+			status, message := execOutcome(results)
+			run.finish(status, message, timeNow().UTC())
+		}
+		c.recordRun(run)
+		if done != nil {
+			done(run)
+		}
+	}()
+}
+
+// execCommand runs the command in the containers of the service at the same time and waits until all of them exit.
+func (c *Cron) execCommand(command ExecCommand) ([]ExecResult, error) {
+	cmd, err := splitShellWords(command.Command)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := c.Execer.GetServiceContainers(command.Service)
+	if err != nil {
+		return nil, fmt.Errorf("could not get containers of service %s: %s", command.Service, err.Error())
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("service %s does not have running containers on this node", command.Service)
+	}
+	if command.Replicas != ReplicasAll {
+		containers = containers[:1]
+	} else if remote, err := c.Execer.CountRemoteTasks(command.Service); err != nil {
+		return nil, fmt.Errorf("could not get tasks of service %s: %s", command.Service, err.Error())
+	} else if remote > 0 {
+		return nil, fmt.Errorf("service %s has %d running task(s) on other nodes", command.Service, remote)
+	}
+	fmt.Println("Executing", command.Command, "in", len(containers), "container(s) of", command.Service)
+	results := make([]ExecResult, len(containers))
+	wg := sync.WaitGroup{}
+	for i, container := range containers {
+		wg.Add(1)
+		go func(i int, containerID, containerName string) {
+			defer wg.Done()
+			exitCode, output, err := c.Execer.ExecContainer(containerID, cmd)
+			result := ExecResult{Container: containerName, ExitCode: exitCode, Output: output}
+			if len(result.Output) > execOutputLimit {
+				result.Output = result.Output[:execOutputLimit]
+				result.Truncated = true
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, container.ID, container.Name)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// execOutcome returns the status of a run and its message. The run fails if the command failed in any container.
+func execOutcome(results []ExecResult) (string, string) {
+	for _, result := range results {
+		if len(result.Error) > 0 {
+			return RunFailed, fmt.Sprintf("could not execute the command in container %s: %s", result.Container, result.Error)
+		}
+		if result.ExitCode != 0 {
+			return RunFailed, fmt.Sprintf("exit code %d in container %s", result.ExitCode, result.Container)
+		}
+	}
+	return RunSucceeded, ""
+}
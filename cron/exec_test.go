@@ -0,0 +1,267 @@
+package cron
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"../docker"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type ExecTestSuite struct {
+	suite.Suite
+}
+
+func TestExecUnitTestSuite(t *testing.T) {
+	s := new(ExecTestSuite)
+	suite.Run(t, s)
+}
+
+// validateExec
+
+func (s *ExecTestSuite) Test_ValidateExec_AcceptsValidCommands() {
+	s.NoError(validateExec(JobData{Image: "alpine"}))
+	s.NoError(validateExec(s.job(ExecCommand{Service: "app", Command: "python manage.py clearsessions"})))
+	s.NoError(validateExec(s.job(ExecCommand{Service: "app", Command: "redis-cli flushall", Replicas: ReplicasAll})))
+}
+
+func (s *ExecTestSuite) Test_ValidateExec_ReturnsError_WhenCommandIsInvalid() {
+	s.Error(validateExec(JobData{Exec: &ExecCommand{Service: "app", Command: "ls"}}))
+	s.Error(validateExec(JobData{Type: TypeExec}))
+	s.Error(validateExec(JobData{Type: TypeExec, Image: "alpine", Exec: &ExecCommand{Service: "app", Command: "ls"}}))
+	s.Error(validateExec(JobData{Type: TypeExec, Mode: ModeGlobal, Exec: &ExecCommand{Service: "app", Command: "ls"}}))
+	s.Error(validateExec(s.job(ExecCommand{Command: "ls"})))
+	s.Error(validateExec(s.job(ExecCommand{Service: "app"})))
+	s.Error(validateExec(s.job(ExecCommand{Service: "app", Command: "echo 'unterminated"})))
+	s.Error(validateExec(s.job(ExecCommand{Service: "app", Command: "ls", Replicas: "some"})))
+}
+
+// Execute
+
+func (s *ExecTestSuite) Test_Execute_RunsCommandInFirstContainer() {
+	history, _ := NewHistorian("")
+	actualService := ""
+	actualContainers := []string{}
+	actualCmd := []string{}
+	c := Cron{
+		History: history,
+		Execer: ExecerMock{
+			GetServiceContainersMock: func(serviceName string) ([]docker.ServiceContainer, error) {
+				actualService = serviceName
+				return s.containers(), nil
+			},
+			ExecContainerMock: func(containerID string, cmd []string) (int64, string, error) {
+				actualContainers = append(actualContainers, containerID)
+				actualCmd = cmd
+				return 0, "flushed", nil
+			},
+		},
+	}
+
+	actual := s.execute(&c, ExecCommand{Service: "app", Command: "sh -c 'redis-cli flushall'"})
+
+	s.Equal("app", actualService)
+	s.Equal([]string{"id-1"}, actualContainers)
+	s.Equal([]string{"sh", "-c", "redis-cli flushall"}, actualCmd)
+	s.Equal(RunSucceeded, actual.Status)
+	s.Equal([]ExecResult{{Container: "app.1.abc", Output: "flushed"}}, actual.Execs)
+	runs, _ := history.Runs("my-job", 0)
+	s.Equal(actual, runs[0])
+}
+
+func (s *ExecTestSuite) Test_Execute_RunsCommandInAllContainers() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Execer: ExecerMock{
+			GetServiceContainersMock: func(serviceName string) ([]docker.ServiceContainer, error) {
+				return s.containers(), nil
+			},
+			CountRemoteTasksMock: func(serviceName string) (int, error) {
+				return 0, nil
+			},
+			ExecContainerMock: func(containerID string, cmd []string) (int64, string, error) {
+				if containerID == "id-2" {
+					return 2, strings.Repeat("a", execOutputLimit+1), nil
+				}
+				return 0, "", nil
+			},
+		},
+	}
+
+	actual := s.execute(&c, ExecCommand{Service: "app", Command: "ls", Replicas: ReplicasAll})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Equal("exit code 2 in container app.2.def", actual.Message)
+	s.Len(actual.Execs, 2)
+	s.Equal(int64(2), actual.Execs[1].ExitCode)
+	s.Len(actual.Execs[1].Output, execOutputLimit)
+	s.True(actual.Execs[1].Truncated)
+}
+
+func (s *ExecTestSuite) Test_Execute_FailsRun_WhenServiceHasNoContainers() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Execer: ExecerMock{
+			GetServiceContainersMock: func(serviceName string) ([]docker.ServiceContainer, error) {
+				return []docker.ServiceContainer{}, nil
+			},
+		},
+	}
+
+	actual := s.execute(&c, ExecCommand{Service: "app", Command: "ls"})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Equal("service app does not have running containers on this node", actual.Message)
+}
+
+func (s *ExecTestSuite) Test_Execute_FailsRun_WhenServiceHasTasksOnOtherNodes() {
+	history, _ := NewHistorian("")
+	executed := false
+	c := Cron{
+		History: history,
+		Execer: ExecerMock{
+			GetServiceContainersMock: func(serviceName string) ([]docker.ServiceContainer, error) {
+				return s.containers(), nil
+			},
+			CountRemoteTasksMock: func(serviceName string) (int, error) {
+				return 3, nil
+			},
+			ExecContainerMock: func(containerID string, cmd []string) (int64, string, error) {
+				executed = true
+				return 0, "", nil
+			},
+		},
+	}
+
+	actual := s.execute(&c, ExecCommand{Service: "app", Command: "ls", Replicas: ReplicasAll})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Equal("service app has 3 running task(s) on other nodes", actual.Message)
+	s.False(executed)
+}
+
+func (s *ExecTestSuite) Test_Execute_FailsRun_WhenExecFails() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Execer: ExecerMock{
+			GetServiceContainersMock: func(serviceName string) ([]docker.ServiceContainer, error) {
+				return s.containers(), nil
+			},
+			ExecContainerMock: func(containerID string, cmd []string) (int64, string, error) {
+				return 0, "", fmt.Errorf("This is an error")
+			},
+		},
+	}
+
+	actual := s.execute(&c, ExecCommand{Service: "app", Command: "ls"})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Contains(actual.Message, "This is an error")
+}
+
+// AddJob
+
+func (s *ExecTestSuite) Test_RescheduleJobs_RestoresExecJobsFromLocalJobsFile() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	job := s.job(ExecCommand{Service: "app", Command: "python manage.py clearsessions", Replicas: ReplicasAll})
+	job.Name = "my-job"
+	job.Schedule = "@daily"
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.LoadLocalJobs(path)
+	c.AddJob(job)
+	restored := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{}, nil
+		},
+	}}
+	restored.LoadLocalJobs(path)
+
+	err := restored.RescheduleJobs()
+	defer restored.Stop()
+
+	s.NoError(err)
+	s.Contains(restored.Jobs, job.Name)
+	s.Equal(job.Exec, restored.getLocalJobs()[job.Name].Exec)
+}
+
+// CancelRun
+
+func (s *ExecTestSuite) Test_CancelRun_ReturnsConflictError_WhenJobIsExec() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	history, _ := NewHistorian("")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, History: history}
+	job := s.job(ExecCommand{Service: "app", Command: "ls"})
+	job.Name = "my-job"
+	job.Schedule = "@daily"
+	s.NoError(c.AddJob(job))
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: time.Now()})
+
+	_, err := c.CancelRun("my-job", "1", "ops")
+
+	s.IsType(&ConflictError{}, err)
+}
+
+// Util
+
+func (s *ExecTestSuite) job(command ExecCommand) JobData {
+	return JobData{Type: TypeExec, Exec: &command}
+}
+
+func (s *ExecTestSuite) containers() []docker.ServiceContainer {
+	return []docker.ServiceContainer{{ID: "id-1", Name: "app.1.abc"}, {ID: "id-2", Name: "app.2.def"}}
+}
+
+func (s *ExecTestSuite) execute(c *Cron, command ExecCommand) Run {
+	finished := make(chan Run)
+	data := s.job(command)
+	data.Name = "my-job"
+	execExecutor{c: c}.Execute(newRun("my-job", TriggerSchedule), data, "my-job", func(run Run) {
+		finished <- run
+	})
+	select {
+	case actual := <-finished:
+		return actual
+	case <-time.After(time.Second):
+		s.Fail("the run did not finish")
+	}
+	return Run{}
+}
+
+type ExecerMock struct {
+	GetServiceContainersMock func(serviceName string) ([]docker.ServiceContainer, error)
+	CountRemoteTasksMock     func(serviceName string) (int, error)
+	ExecContainerMock        func(containerID string, cmd []string) (int64, string, error)
+}
+
+func (m ExecerMock) GetServiceContainers(serviceName string) ([]docker.ServiceContainer, error) {
+	return m.GetServiceContainersMock(serviceName)
+}
+
+func (m ExecerMock) CountRemoteTasks(serviceName string) (int, error) {
+	return m.CountRemoteTasksMock(serviceName)
+}
+
+func (m ExecerMock) ExecContainer(containerID string, cmd []string) (int64, string, error) {
+	return m.ExecContainerMock(containerID, cmd)
+}
@@ -0,0 +1,107 @@
+package cron
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	// ExecutionShared runs the job by scaling its service, so that all runs share the same service.
+	ExecutionShared = "shared"
+	// ExecutionPerRun creates a new service for each run of the job.
+	ExecutionPerRun = "per-run"
+)
+
+// runRetentionDefault is the number of finished per-run services kept when the job does not set the retention.
+const runRetentionDefault = 5
+
+// runServiceLabel holds the ID of the run a per-run service was created for.
+const runServiceLabel = "com.df.cron.run"
+
+func validateExecutionMode(data JobData) error {
+	switch data.ExecutionMode {
+	case "", ExecutionShared, ExecutionPerRun:
+	default:
+		return fmt.Errorf("executionMode must be %s or %s", ExecutionShared, ExecutionPerRun)
+	}
+	if data.Retention < 0 {
+		return fmt.Errorf("retention must not be negative")
+	}
+	if data.Retention > 0 && data.ExecutionMode != ExecutionPerRun {
+		return fmt.Errorf("retention requires the %s execution mode", ExecutionPerRun)
+	}
+	return nil
+}
+
+// startRunService creates a service named after the job service and the run, and watches it until its task finishes.
+// Once the run finished, services of older runs beyond the retention of the job are removed.
+func (c *Cron) startRunService(run Run, data JobData, serviceName string, done func(Run)) {
+	c.countRun(run.Job)
+	runName := fmt.Sprintf("%s-%s", serviceName, run.ID)
+	fmt.Println("Creating service", runName)
+	id, err := c.Service.RunService(serviceName, runName, map[string]string{runServiceLabel: run.ID})
+	if err != nil {
+		run.finish(RunFailed, fmt.Sprintf("could not create service %s: %s", runName, err.Error()), timeNow().UTC())
+		c.recordRun(run)
+		if done != nil {
+			done(run)
+		}
+		return
+	}
+	run.Service = runName
+	run.serviceID = id
+	c.recordRun(run)
+	go c.watchRun(run, func(run Run) {
+		c.pruneRunServices(data.Name, data.Retention)
+		if done != nil {
+			done(run)
+		}
+	})
+}
+
+// pruneRunServices removes the services of finished runs of the job, except for the newest ones.
+func (c *Cron) pruneRunServices(jobName string, retention int) {
+	if retention <= 0 {
+		retention = runRetentionDefault
+	}
+	services, err := c.Service.GetRunServices(jobName)
+	if err != nil {
+		fmt.Println("Could not get services of", jobName, err.Error())
+		return
+	}
+	running := map[string]bool{}
+	if c.History != nil {
+		runs, _ := c.History.Runs(jobName, 0)
+		for _, run := range runs {
+			if run.Status == RunRunning {
+				running[run.ID] = true
+			}
+		}
+	}
+	kept := 0
+	for i := len(services) - 1; i >= 0; i-- {
+		if running[services[i].Spec.Labels[runServiceLabel]] {
+			continue
+		}
+		if kept < retention {
+			kept++
+			continue
+		}
+		fmt.Println("Removing service", services[i].Spec.Name)
+		if err := c.Service.RemoveService(services[i].Spec.Name); err != nil {
+			fmt.Println("Could not remove service", services[i].Spec.Name, err.Error())
+		}
+	}
+}
+
+// serviceTasks returns the tasks of a single service.
+func serviceTasks(tasks []swarm.Task, serviceID string) []swarm.Task {
+	filtered := []swarm.Task{}
+	for _, task := range tasks {
+		if task.ServiceID == serviceID {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
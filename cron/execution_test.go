@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecutionTestSuite struct {
+	suite.Suite
+}
+
+func TestExecutionUnitTestSuite(t *testing.T) {
+	s := new(ExecutionTestSuite)
+	suite.Run(t, s)
+}
+
+// validateExecutionMode
+
+func (s *ExecutionTestSuite) Test_ValidateExecutionMode_AcceptsValidFields() {
+	s.NoError(validateExecutionMode(JobData{}))
+	s.NoError(validateExecutionMode(JobData{ExecutionMode: ExecutionShared}))
+	s.NoError(validateExecutionMode(JobData{ExecutionMode: ExecutionPerRun, Retention: 3}))
+}
+
+func (s *ExecutionTestSuite) Test_ValidateExecutionMode_ReturnsError_WhenFieldsAreInvalid() {
+	s.Error(validateExecutionMode(JobData{ExecutionMode: "parallel"}))
+	s.Error(validateExecutionMode(JobData{ExecutionMode: ExecutionPerRun, Retention: -1}))
+	s.Error(validateExecutionMode(JobData{Retention: 3}))
+}
+
+// startRunService
+
+func (s *ExecutionTestSuite) Test_StartRunService_CreatesServiceAndWatchesItsTask() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	history, _ := NewHistorian("")
+	actualService, actualName := "", ""
+	actualLabels := map[string]string{}
+	removed := []string{}
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			RunServiceMock: func(serviceName, runName string, labels map[string]string) (string, error) {
+				actualService, actualName, actualLabels = serviceName, runName, labels
+				return "run-service-id", nil
+			},
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				other := swarm.Task{ServiceID: "other-service-id", Status: swarm.TaskStatus{State: swarm.TaskStateFailed}}
+				other.CreatedAt = time.Now()
+				task := swarm.Task{ServiceID: "run-service-id", Status: swarm.TaskStatus{State: swarm.TaskStateComplete, Timestamp: time.Now()}}
+				task.CreatedAt = time.Now()
+				return []swarm.Task{task, other}, nil
+			},
+			GetRunServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{}, nil
+			},
+			RemoveServiceMock: func(serviceName string) error {
+				removed = append(removed, serviceName)
+				return nil
+			},
+		},
+	}
+	run := Run{ID: "123", Job: "my-job", Status: RunRunning, StartedAt: time.Now().UTC()}
+	finished := make(chan Run)
+
+	c.startRun(run, JobData{Name: "my-job", ExecutionMode: ExecutionPerRun}, "my-service", func(run Run) {
+		finished <- run
+	})
+
+	select {
+	case actual := <-finished:
+		s.Equal(RunSucceeded, actual.Status)
+		s.Equal("my-service-123", actual.Service)
+	case <-time.After(time.Second):
+		s.Fail("the run did not finish")
+	}
+	s.Equal("my-service", actualService)
+	s.Equal("my-service-123", actualName)
+	s.Equal(map[string]string{runServiceLabel: "123"}, actualLabels)
+	s.Empty(removed)
+}
+
+func (s *ExecutionTestSuite) Test_StartRunService_FailsRun_WhenServiceCannotBeCreated() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			RunServiceMock: func(serviceName, runName string, labels map[string]string) (string, error) {
+				return "", fmt.Errorf("This is an error")
+			},
+		},
+	}
+	actual := Run{}
+
+	c.startRun(newRun("my-job", TriggerSchedule), JobData{Name: "my-job", ExecutionMode: ExecutionPerRun}, "my-service", func(run Run) {
+		actual = run
+	})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Contains(actual.Message, "This is an error")
+}
+
+// pruneRunServices
+
+func (s *ExecutionTestSuite) Test_PruneRunServices_RemovesOldestFinishedServices() {
+	history, _ := NewHistorian("")
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning})
+	removed := []string{}
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			GetRunServicesMock: func(jobName string) ([]swarm.Service, error) {
+				services := []swarm.Service{}
+				for i := 1; i <= 5; i++ {
+					service := swarm.Service{}
+					service.Spec.Name = fmt.Sprintf("my-job-%d", i)
+					service.Spec.Labels = map[string]string{runServiceLabel: fmt.Sprintf("%d", i)}
+					services = append(services, service)
+				}
+				return services, nil
+			},
+			RemoveServiceMock: func(serviceName string) error {
+				removed = append(removed, serviceName)
+				return nil
+			},
+		},
+	}
+
+	c.pruneRunServices("my-job", 2)
+
+	s.Equal([]string{"my-job-3", "my-job-2"}, removed)
+}
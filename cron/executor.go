@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"../docker"
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	// ExecutorSwarm runs jobs as Swarm services
+	ExecutorSwarm = "swarm"
+	// ExecutorContainer runs jobs as plain containers without Swarm
+	ExecutorContainer = "container"
+)
+
+// Executor starts runs of jobs. The done function, if set, is invoked with the finished run.
+type Executor interface {
+	Execute(run Run, data JobData, serviceName string, done func(Run))
+}
+
+type swarmExecutor struct {
+	c *Cron
+}
+
+// Execute scales the service of the job or, in the per-run execution mode, creates a new one.
+func (e swarmExecutor) Execute(run Run, data JobData, serviceName string, done func(Run)) {
+	e.c.startSwarmRun(run, data, serviceName, done)
+}
+
+type containerExecutor struct {
+	c *Cron
+}
+
+// Execute creates a container named after the job service and the run, and waits until it exits.
+func (e containerExecutor) Execute(run Run, data JobData, serviceName string, done func(Run)) {
+	c := e.c
+	c.countRun(run.Job)
+	finish := func(run Run) {
+		c.recordRun(run)
+		if done != nil {
+			done(run)
+		}
+	}
+	spec, err := containerSpec(data)
+	if err != nil {
+		run.finish(RunFailed, err.Error(), timeNow().UTC())
+		finish(run)
+		return
+	}
+	run.Container = fmt.Sprintf("%s-%s", serviceName, run.ID)
+	fmt.Println("Creating container", run.Container)
+	c.recordRun(run)
+	go func() {
+		exitCode, err := c.Containers.RunContainer(run.Container, spec)
+		if cancelled, ok := c.takeCancelled(run.ID); ok {
+			if done != nil {
+				done(cancelled)
+			}
+			return
+		}
+		switch {
+		case err != nil:
+			run.finish(RunFailed, fmt.Sprintf("could not run container %s: %s", run.Container, err.Error()), timeNow().UTC())
+		case exitCode != 0:
+			run.finish(RunFailed, fmt.Sprintf("exit code %d", exitCode), timeNow().UTC())
+		default:
+			run.finish(RunSucceeded, "", timeNow().UTC())
+		}
+		finish(run)
+	}()
+}
+
+// SetExecutor sets the executor of jobs that do not specify one.
+func (c *Cron) SetExecutor(name string) error {
+	if err := validateExecutorName(name); err != nil {
+		return err
+	}
+	c.Executor = name
+	return nil
+}
+
+func (c *Cron) executorName(data JobData) string {
+	switch {
+	case len(data.Executor) > 0:
+		return data.Executor
+	case len(c.Executor) > 0:
+		return c.Executor
+	}
+	return ExecutorSwarm
+}
+
+func (c *Cron) executor(data JobData) Executor {
+	switch data.Type {
+	case TypeHTTP:
+		return httpExecutor{c: c}
+	case TypeExec:
+		return execExecutor{c: c}
+	}
+	if c.executorName(data) == ExecutorContainer {
+		return containerExecutor{c: c}
+	}
+	return swarmExecutor{c: c}
+}
+
+func validateExecutorName(name string) error {
+	switch name {
+	case "", ExecutorSwarm, ExecutorContainer:
+		return nil
+	}
+	return fmt.Errorf("executor must be %s or %s", ExecutorSwarm, ExecutorContainer)
+}
+
+func (c *Cron) validateExecutor(data JobData) error {
+	if err := validateExecutorName(data.Executor); err != nil {
+		return err
+	}
+	if !runsImage(data) || c.executorName(data) != ExecutorContainer {
+		return nil
+	}
+	switch {
+	case data.ExecutionMode == ExecutionPerRun:
+		return fmt.Errorf("the %s executor cannot be used with the %s execution mode", ExecutorContainer, ExecutionPerRun)
+	case data.Mode == ModeGlobal:
+		return fmt.Errorf("the %s executor cannot be used with mode %s", ExecutorContainer, ModeGlobal)
+	case data.Parallelism > 0 || data.Completions > 0 || data.MaxConcurrent > 0 || data.TotalCompletions > 0:
+		return fmt.Errorf("the %s executor runs a single container and cannot be used with parallelism or completions", ExecutorContainer)
+	}
+	_, err := containerSpec(data)
+	return err
+}
+
+// containerSpec converts the arguments of a job into the specification of a container.
+// Only the `docker service create` flags that have an equivalent for containers are supported.
+func containerSpec(data JobData) (docker.ContainerSpec, error) {
+	flags, image, command, err := jobArguments(data)
+	if err != nil {
+		return docker.ContainerSpec{}, err
+	}
+	spec := docker.ContainerSpec{
+		Image:  image,
+		Cmd:    command,
+		Env:    []string{},
+		Labels: map[string]string{"com.df.cron": "true", "com.df.cron.name": data.Name},
+		Mounts: []mount.Mount{},
+	}
+	for _, flag := range flags {
+		switch flag.Name {
+		case "-e", "--env":
+			spec.Env = append(spec.Env, flag.Value)
+		case "--mount":
+			m, err := parseMount(flag.Value)
+			if err != nil {
+				return docker.ContainerSpec{}, err
+			}
+			spec.Mounts = append(spec.Mounts, m)
+		case "--network":
+			spec.Network = flag.Value
+		case "-u", "--user":
+			spec.User = flag.Value
+		case "-w", "--workdir":
+			spec.WorkingDir = flag.Value
+		case "--hostname":
+			spec.Hostname = flag.Value
+		case "--container-label":
+			values := strings.SplitN(flag.Value, "=", 2)
+			if len(values) < 2 {
+				values = append(values, "")
+			}
+			spec.Labels[values[0]] = values[1]
+		case "--entrypoint":
+			if spec.Entrypoint, err = splitShellWords(flag.Value); err != nil {
+				return docker.ContainerSpec{}, err
+			}
+		case "--name", "--restart-condition", "--restart-delay", "--restart-max-attempts", "-d", "--detach":
+			// Containers are named after runs and are never restarted
+		default:
+			return docker.ContainerSpec{}, fmt.Errorf("the %s flag is not supported by the %s executor", flag.Name, ExecutorContainer)
+		}
+	}
+	if len(spec.Image) == 0 {
+		return docker.ContainerSpec{}, fmt.Errorf("image is mandatory")
+	}
+	return spec, nil
+}
+
+// parseMount converts the value of the `--mount` flag, e.g. `type=bind,source=/tmp,target=/tmp,readonly`.
+func parseMount(value string) (mount.Mount, error) {
+	m := mount.Mount{Type: mount.TypeVolume}
+	for _, field := range strings.Split(value, ",") {
+		values := strings.SplitN(field, "=", 2)
+		key := values[0]
+		val := ""
+		if len(values) > 1 {
+			val = values[1]
+		}
+		switch key {
+		case "type":
+			m.Type = mount.Type(val)
+		case "source", "src":
+			m.Source = val
+		case "target", "destination", "dst":
+			m.Target = val
+		case "readonly", "ro":
+			readOnly := true
+			if len(val) > 0 {
+				var err error
+				if readOnly, err = strconv.ParseBool(val); err != nil {
+					return m, fmt.Errorf("invalid mount %s", value)
+				}
+			}
+			m.ReadOnly = readOnly
+		default:
+			return m, fmt.Errorf("the mount option %s is not supported by the %s executor", key, ExecutorContainer)
+		}
+	}
+	if len(m.Target) == 0 {
+		return m, fmt.Errorf("mount %s does not have a target", value)
+	}
+	return m, nil
+}
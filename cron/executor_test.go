@@ -0,0 +1,333 @@
+package cron
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"../docker"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type ExecutorTestSuite struct {
+	suite.Suite
+}
+
+func TestExecutorUnitTestSuite(t *testing.T) {
+	s := new(ExecutorTestSuite)
+	suite.Run(t, s)
+}
+
+// SetExecutor
+
+func (s *ExecutorTestSuite) Test_SetExecutor_ReturnsError_WhenExecutorIsUnknown() {
+	c := Cron{}
+
+	s.NoError(c.SetExecutor(""))
+	s.NoError(c.SetExecutor(ExecutorContainer))
+	s.Equal(ExecutorContainer, c.Executor)
+	s.Error(c.SetExecutor("kubernetes"))
+}
+
+// executor
+
+func (s *ExecutorTestSuite) Test_Executor_PrefersExecutorOfJob() {
+	c := Cron{}
+	s.IsType(swarmExecutor{}, c.executor(JobData{}))
+	s.IsType(containerExecutor{}, c.executor(JobData{Executor: ExecutorContainer}))
+
+	c.Executor = ExecutorContainer
+	s.IsType(containerExecutor{}, c.executor(JobData{}))
+	s.IsType(swarmExecutor{}, c.executor(JobData{Executor: ExecutorSwarm}))
+}
+
+// validateExecutor
+
+func (s *ExecutorTestSuite) Test_ValidateExecutor_ReturnsError_WhenFieldsCannotBeUsedWithContainers() {
+	c := Cron{Executor: ExecutorContainer}
+
+	s.NoError(c.validateExecutor(JobData{Image: "alpine"}))
+	s.NoError(c.validateExecutor(JobData{Image: "alpine", Executor: ExecutorSwarm, Parallelism: 2}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", Executor: "kubernetes"}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", ExecutionMode: ExecutionPerRun}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", Mode: ModeGlobal}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", Parallelism: 2}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", MaxConcurrent: 2}))
+	s.Error(c.validateExecutor(JobData{Image: "alpine", Args: []string{"--replicas 2"}}))
+}
+
+// containerSpec
+
+func (s *ExecutorTestSuite) Test_ContainerSpec_MapsArgs() {
+	data := JobData{
+		Name:    "my-job",
+		Image:   "alpine",
+		Command: "echo 'Hello World'",
+		Args: []string{
+			"-e A=1",
+			"--env=B=2",
+			"--mount type=bind,source=/tmp,target=/data,readonly",
+			"--network my-network",
+			"--user nobody",
+			"--workdir /data",
+			"--hostname my-host",
+			"--container-label team=ops",
+			"--entrypoint /bin/sh",
+			"--restart-condition none",
+		},
+	}
+
+	actual, err := containerSpec(data)
+
+	s.NoError(err)
+	s.Equal(docker.ContainerSpec{
+		Image:      "alpine",
+		Cmd:        []string{"echo", "Hello World"},
+		Entrypoint: []string{"/bin/sh"},
+		Env:        []string{"A=1", "B=2"},
+		Labels:     map[string]string{"com.df.cron": "true", "com.df.cron.name": "my-job", "team": "ops"},
+		Mounts:     []mount.Mount{{Type: mount.TypeBind, Source: "/tmp", Target: "/data", ReadOnly: true}},
+		Network:    "my-network",
+		User:       "nobody",
+		WorkingDir: "/data",
+		Hostname:   "my-host",
+	}, actual)
+}
+
+func (s *ExecutorTestSuite) Test_ContainerSpec_ReturnsError_WhenMountIsInvalid() {
+	_, err := containerSpec(JobData{Image: "alpine", Args: []string{"--mount type=bind,source=/tmp"}})
+	s.Error(err)
+
+	_, err = containerSpec(JobData{Image: "alpine", Args: []string{"--mount target=/data,volume-driver=local"}})
+	s.Error(err)
+}
+
+// AddJob
+
+func (s *ExecutorTestSuite) Test_AddJob_KeepsContainerJobsInMemory() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	removed := false
+	c := Cron{
+		Cron:     rcron.New(),
+		Jobs:     map[string]rcron.EntryID{},
+		Executor: ExecutorContainer,
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return nil, fmt.Errorf("This node is not a swarm manager")
+			},
+			RemoveServicesMock: func(jobName string) error {
+				removed = true
+				return nil
+			},
+		},
+	}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily"})
+	jobs, jobsErr := c.GetJobs()
+
+	s.NoError(err)
+	s.NoError(jobsErr)
+	s.Contains(jobs, "my-job")
+	s.Equal("alpine", jobs["my-job"].Image)
+
+	s.NoError(c.RemoveJob("my-job"))
+	jobs, _ = c.GetJobs()
+	s.False(removed)
+	s.NotContains(jobs, "my-job")
+}
+
+func (s *ExecutorTestSuite) Test_LoadLocalJobs_RestoresContainerJobsAfterRestart() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	newCron := func() *Cron {
+		c := &Cron{
+			Cron:     rcron.New(),
+			Jobs:     map[string]rcron.EntryID{},
+			Executor: ExecutorContainer,
+			Service: ServicerMock{
+				GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+					return nil, fmt.Errorf("This node is not a swarm manager")
+				},
+			},
+		}
+		s.NoError(c.LoadLocalJobs(path))
+		return c
+	}
+	c := newCron()
+	s.NoError(c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily"}))
+	s.NoError(c.AddJob(JobData{Name: "suspended-job", Image: "alpine", Schedule: "@daily"}))
+	s.NoError(c.AddJob(JobData{Name: "removed-job", Image: "alpine", Schedule: "@daily"}))
+	s.NoError(c.SuspendJob("suspended-job", "ops"))
+	s.NoError(c.RemoveJob("removed-job"))
+
+	restored := newCron()
+	err := restored.RescheduleJobs()
+	defer restored.Stop()
+	jobs, _ := restored.GetJobs()
+
+	s.NoError(err)
+	s.Len(jobs, 2)
+	s.Equal("alpine", jobs["my-job"].Image)
+	s.Equal(JobScheduled, jobs["my-job"].State.Status)
+	s.Equal(JobSuspended, jobs["suspended-job"].State.Status)
+	s.Equal("suspended by ops", jobs["suspended-job"].State.Reason)
+}
+
+func (s *ExecutorTestSuite) Test_LoadLocalJobs_ReturnsError_WhenFileIsInvalid() {
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	ioutil.WriteFile(path, []byte("not json"), 0600)
+	c := Cron{}
+
+	s.Error(c.LoadLocalJobs(path))
+	s.NoError(c.LoadLocalJobs(filepath.Join(dir, "missing.json")))
+}
+
+// Plan
+
+func (s *ExecutorTestSuite) Test_Plan_IncludesContainerJobs() {
+	job := JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", Executor: ExecutorContainer}
+	c := Cron{Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{}, nil
+		},
+	}}
+	c.setLocalJob(managedJob(job))
+	c.setLocalJob(managedJob(JobData{Name: "removed-job", Image: "alpine", Schedule: "@daily", Executor: ExecutorContainer}))
+
+	actual, err := c.Plan([]JobData{job})
+
+	s.NoError(err)
+	s.Equal([]PlanChange{{Name: "removed-job", Action: "delete", Reason: "job is not defined"}}, actual.Changes)
+}
+
+// Execute
+
+func (s *ExecutorTestSuite) Test_Execute_RunsContainerAndRecordsExitCode() {
+	history, _ := NewHistorian("")
+	actualName := ""
+	actualSpec := docker.ContainerSpec{}
+	c := Cron{
+		History: history,
+		Containers: ContainererMock{
+			RunContainerMock: func(name string, spec docker.ContainerSpec) (int64, error) {
+				actualName = name
+				actualSpec = spec
+				return 3, nil
+			},
+		},
+	}
+	run := newRun("my-job", TriggerSchedule)
+
+	actual := s.execute(&c, run, JobData{Name: "my-job", Image: "alpine", Command: "false"})
+
+	s.Equal(fmt.Sprintf("my-service-%s", run.ID), actualName)
+	s.Equal([]string{"false"}, actualSpec.Cmd)
+	s.Equal(actualName, actual.Container)
+	s.Equal(RunFailed, actual.Status)
+	s.Equal("exit code 3", actual.Message)
+	runs, _ := history.Runs("my-job", 0)
+	s.Equal(actual, runs[0])
+}
+
+func (s *ExecutorTestSuite) Test_Execute_Succeeds_WhenContainerExitsWithZero() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Containers: ContainererMock{
+			RunContainerMock: func(name string, spec docker.ContainerSpec) (int64, error) {
+				return 0, nil
+			},
+		},
+	}
+
+	actual := s.execute(&c, newRun("my-job", TriggerSchedule), JobData{Name: "my-job", Image: "alpine"})
+
+	s.Equal(RunSucceeded, actual.Status)
+}
+
+func (s *ExecutorTestSuite) Test_Execute_FailsRun_WhenContainerCannotRun() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Containers: ContainererMock{
+			RunContainerMock: func(name string, spec docker.ContainerSpec) (int64, error) {
+				return 0, fmt.Errorf("This is an error")
+			},
+		},
+	}
+
+	actual := s.execute(&c, newRun("my-job", TriggerSchedule), JobData{Name: "my-job", Image: "alpine"})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Contains(actual.Message, "This is an error")
+}
+
+// CancelRun
+
+func (s *ExecutorTestSuite) Test_CancelRun_RemovesContainerOfRun() {
+	history, _ := NewHistorian("")
+	actualRemoved := ""
+	c := Cron{
+		History: history,
+		Containers: ContainererMock{
+			RemoveContainerMock: func(name string) error {
+				actualRemoved = name
+				return nil
+			},
+		},
+	}
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: time.Now(), Container: "my-job-1"})
+
+	run, err := c.CancelRun("my-job", "1", "ops")
+
+	s.NoError(err)
+	s.Equal("my-job-1", actualRemoved)
+	s.Equal(RunCancelled, run.Status)
+}
+
+// Util
+
+func (s *ExecutorTestSuite) execute(c *Cron, run Run, data JobData) Run {
+	finished := make(chan Run)
+	containerExecutor{c: c}.Execute(run, data, "my-service", func(run Run) {
+		finished <- run
+	})
+	select {
+	case actual := <-finished:
+		return actual
+	case <-time.After(time.Second):
+		s.Fail("the run did not finish")
+	}
+	return Run{}
+}
+
+type ContainererMock struct {
+	RunContainerMock    func(name string, spec docker.ContainerSpec) (int64, error)
+	RemoveContainerMock func(name string) error
+}
+
+func (m ContainererMock) RunContainer(name string, spec docker.ContainerSpec) (int64, error) {
+	return m.RunContainerMock(name, spec)
+}
+
+func (m ContainererMock) RemoveContainer(name string) error {
+	return m.RemoveContainerMock(name)
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	// ModeReplicated runs the tasks of a job on any nodes.
+	ModeReplicated = "replicated"
+	// ModeGlobal runs a task of a job on every node that matches the node labels of the job.
+	ModeGlobal = "global"
+)
+
+func (c *Cron) validateMode(data JobData) error {
+	switch data.Mode {
+	case "", ModeReplicated:
+	case ModeGlobal:
+		if !c.jobMode {
+			return fmt.Errorf("mode %s requires a Docker daemon with job mode services", ModeGlobal)
+		}
+		if data.ExecutionMode == ExecutionPerRun {
+			return fmt.Errorf("mode %s cannot be used with the %s execution mode", ModeGlobal, ExecutionPerRun)
+		}
+		if data.MaxConcurrent > 0 || data.TotalCompletions > 0 {
+			return fmt.Errorf("maxConcurrent and totalCompletions cannot be used with mode %s", ModeGlobal)
+		}
+	default:
+		return fmt.Errorf("mode must be %s or %s", ModeReplicated, ModeGlobal)
+	}
+	for _, label := range data.NodeLabels {
+		if parts := strings.SplitN(label, "=", 2); len(parts) != 2 || len(parts[0]) == 0 {
+			return fmt.Errorf("node label %s must have the format key=value", label)
+		}
+	}
+	return nil
+}
+
+// nodeConstraints returns the placement constraints that limit tasks to the nodes with the labels.
+func nodeConstraints(nodeLabels []string) []string {
+	constraints := []string{}
+	for _, label := range nodeLabels {
+		parts := strings.SplitN(label, "=", 2)
+		constraints = append(constraints, fmt.Sprintf("node.labels.%s==%s", parts[0], parts[1]))
+	}
+	return constraints
+}
+
+// globalOutcome returns how the iteration of a global job ended on each node.
+// The iteration succeeds when the tasks on all nodes completed and fails when any of them failed.
+// The last return value is false while any of the tasks is still running.
+func globalOutcome(tasks []swarm.Task, iteration uint64) (string, string, time.Time, []NodeRun, bool) {
+	nodes := []NodeRun{}
+	failed := 0
+	finishedAt := time.Time{}
+	for _, task := range tasks {
+		if task.JobIteration == nil || task.JobIteration.Index != iteration {
+			continue
+		}
+		node := NodeRun{Node: task.NodeID, Message: task.Status.Err}
+		switch task.Status.State {
+		case swarm.TaskStateComplete:
+			node.Status = RunSucceeded
+		case swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateShutdown, swarm.TaskStateOrphaned:
+			node.Status = RunFailed
+			failed++
+		default:
+			return "", "", time.Time{}, nil, false
+		}
+		timestamp := task.Status.Timestamp.UTC()
+		node.FinishedAt = &timestamp
+		if timestamp.After(finishedAt) {
+			finishedAt = timestamp
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return "", "", time.Time{}, nil, false
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+	if failed > 0 {
+		return RunFailed, fmt.Sprintf("the task failed on %d of %d nodes", failed, len(nodes)), finishedAt, nodes, true
+	}
+	return RunSucceeded, "", finishedAt, nodes, true
+}
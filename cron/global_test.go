@@ -0,0 +1,140 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+)
+
+type GlobalTestSuite struct {
+	suite.Suite
+}
+
+func TestGlobalUnitTestSuite(t *testing.T) {
+	s := new(GlobalTestSuite)
+	suite.Run(t, s)
+}
+
+// validateMode
+
+func (s *GlobalTestSuite) Test_ValidateMode_AcceptsValidFields() {
+	c := Cron{jobMode: true}
+
+	s.NoError(c.validateMode(JobData{}))
+	s.NoError(c.validateMode(JobData{Mode: ModeReplicated, NodeLabels: []string{"disk=ssd"}}))
+	s.NoError(c.validateMode(JobData{Mode: ModeGlobal, NodeLabels: []string{"role=build"}}))
+}
+
+func (s *GlobalTestSuite) Test_ValidateMode_ReturnsError_WhenFieldsAreInvalid() {
+	c := Cron{jobMode: true}
+
+	s.Error(c.validateMode(JobData{Mode: "everywhere"}))
+	s.Error(c.validateMode(JobData{Mode: ModeGlobal, ExecutionMode: ExecutionPerRun}))
+	s.Error(c.validateMode(JobData{Mode: ModeGlobal, TotalCompletions: 2}))
+	s.Error(c.validateMode(JobData{NodeLabels: []string{"ssd"}}))
+}
+
+func (s *GlobalTestSuite) Test_ValidateMode_ReturnsError_WhenJobModeIsNotSupported() {
+	c := Cron{}
+
+	s.Error(c.validateMode(JobData{Mode: ModeGlobal}))
+}
+
+// nodeConstraints
+
+func (s *GlobalTestSuite) Test_NodeConstraints_ReturnsNodeLabelConstraints() {
+	actual := nodeConstraints([]string{"role=build", "disk=ssd"})
+
+	s.Equal([]string{"node.labels.role==build", "node.labels.disk==ssd"}, actual)
+}
+
+// globalOutcome
+
+func (s *GlobalTestSuite) Test_GlobalOutcome_Succeeds_WhenTasksOnAllNodesComplete() {
+	finished := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	tasks := []swarm.Task{
+		s.task("node-2", 3, swarm.TaskStateComplete, finished),
+		s.task("node-1", 3, swarm.TaskStateComplete, finished.Add(-time.Minute)),
+		s.task("node-1", 2, swarm.TaskStateFailed, finished),
+	}
+
+	status, _, finishedAt, nodes, ok := globalOutcome(tasks, 3)
+
+	s.True(ok)
+	s.Equal(RunSucceeded, status)
+	s.Equal(finished, finishedAt)
+	s.Len(nodes, 2)
+	s.Equal("node-1", nodes[0].Node)
+	s.Equal(RunSucceeded, nodes[0].Status)
+	s.Equal("node-2", nodes[1].Node)
+}
+
+func (s *GlobalTestSuite) Test_GlobalOutcome_Fails_WhenTaskFailsOnAnyNode() {
+	failed := s.task("node-2", 3, swarm.TaskStateFailed, time.Now())
+	failed.Status.Err = "exit code 1"
+	tasks := []swarm.Task{s.task("node-1", 3, swarm.TaskStateComplete, time.Now()), failed}
+
+	status, message, _, nodes, ok := globalOutcome(tasks, 3)
+
+	s.True(ok)
+	s.Equal(RunFailed, status)
+	s.Equal("the task failed on 1 of 2 nodes", message)
+	s.Equal(RunFailed, nodes[1].Status)
+	s.Equal("exit code 1", nodes[1].Message)
+}
+
+func (s *GlobalTestSuite) Test_GlobalOutcome_IsRunning_WhileAnyTaskRuns() {
+	tasks := []swarm.Task{
+		s.task("node-1", 3, swarm.TaskStateComplete, time.Now()),
+		s.task("node-2", 3, swarm.TaskStateRunning, time.Now()),
+	}
+
+	_, _, _, _, ok := globalOutcome(tasks, 3)
+
+	s.False(ok)
+}
+
+func (s *GlobalTestSuite) Test_GlobalOutcome_IsRunning_WhenIterationHasNoTasks() {
+	_, _, _, _, ok := globalOutcome([]swarm.Task{s.task("node-1", 2, swarm.TaskStateComplete, time.Now())}, 3)
+
+	s.False(ok)
+}
+
+// watchRun
+
+func (s *GlobalTestSuite) Test_WatchRun_RecordsNodesOfGlobalRun() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				return []swarm.Task{
+					s.task("node-1", 3, swarm.TaskStateComplete, time.Now()),
+					s.task("node-2", 3, swarm.TaskStateComplete, time.Now()),
+				}, nil
+			},
+		},
+	}
+	run := Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: time.Now(), iteration: 3, global: true}
+
+	c.watchRun(run, nil)
+
+	actual, _ := history.Runs("my-job", 0)
+	s.Equal(RunSucceeded, actual[0].Status)
+	s.Len(actual[0].Nodes, 2)
+}
+
+// Util
+
+func (s *GlobalTestSuite) task(node string, iteration uint64, state swarm.TaskState, timestamp time.Time) swarm.Task {
+	return swarm.Task{
+		NodeID:       node,
+		JobIteration: &swarm.Version{Index: iteration},
+		Status:       swarm.TaskStatus{State: state, Timestamp: timestamp},
+	}
+}
@@ -0,0 +1,176 @@
+package cron
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+// hashTokenPattern matches the `H`, `H/step`, `H(low-high)` and `H(low-high)/step` tokens of a field.
+var hashTokenPattern = regexp.MustCompile(`^H(\((\d+)-(\d+)\))?(/(\d+))?$`)
+
+// hashBounds are the values the fields of a six-field schedule can take.
+// The day of month is limited to 28 so that hashed days exist in every month.
+var hashBounds = [][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 28}, {1, 12}, {0, 6}}
+
+// ResolveSchedule replaces the `H` tokens of the schedule with values derived from the hash of the job name.
+// The same job always gets the same values while different jobs get spread across the range of each field.
+// Schedules without `H` tokens are returned normalized.
+func ResolveSchedule(schedule, jobName string) (string, error) {
+	normalized := NormalizeSchedule(schedule)
+	timeZone, spec, err := splitTimeZone(normalized)
+	if err != nil || !hasHashToken(spec) {
+		return normalized, nil
+	}
+	if len(jobName) == 0 {
+		return "", fmt.Errorf("H tokens require a job name")
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != len(hashBounds) {
+		return "", fmt.Errorf("H tokens can be used only in schedules with five or six fields")
+	}
+	for i, field := range fields {
+		parts := strings.Split(field, ",")
+		for j, part := range parts {
+			if !strings.HasPrefix(part, "H") {
+				continue
+			}
+			resolved, err := resolveHashToken(part, hashBounds[i], hashOf(fmt.Sprintf("%s:%d", jobName, i)))
+			if err != nil {
+				return "", err
+			}
+			parts[j] = resolved
+		}
+		fields[i] = strings.Join(parts, ",")
+	}
+	resolved := strings.Join(fields, " ")
+	if len(timeZone) > 0 {
+		resolved = fmt.Sprintf("TZ=%s %s", timeZone, resolved)
+	}
+	return resolved, nil
+}
+
+func hasHashToken(spec string) bool {
+	for _, field := range strings.Fields(spec) {
+		for _, part := range strings.Split(field, ",") {
+			if strings.HasPrefix(part, "H") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func resolveHashToken(token string, bounds [2]int, hash uint32) (string, error) {
+	match := hashTokenPattern.FindStringSubmatch(token)
+	if match == nil {
+		return "", fmt.Errorf("invalid H token %s", token)
+	}
+	low, high := bounds[0], bounds[1]
+	if len(match[1]) > 0 {
+		low, _ = strconv.Atoi(match[2])
+		high, _ = strconv.Atoi(match[3])
+		if low < bounds[0] || high > bounds[1] || low > high {
+			return "", fmt.Errorf("range of %s must be within %d-%d", token, bounds[0], bounds[1])
+		}
+	}
+	size := high - low + 1
+	if len(match[4]) == 0 {
+		return strconv.Itoa(low + int(hash%uint32(size))), nil
+	}
+	step, _ := strconv.Atoi(match[5])
+	if step < 1 {
+		return "", fmt.Errorf("step of %s must be positive", token)
+	}
+	if step < size {
+		size = step
+	}
+	return fmt.Sprintf("%d-%d/%d", low+int(hash%uint32(size)), high, step), nil
+}
+
+func hashOf(value string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(value))
+	return h.Sum32()
+}
+
+// validateJitter checks the jitter of a job.
+func validateJitter(data JobData) error {
+	if len(data.Jitter) == 0 {
+		return nil
+	}
+	if len(data.RunAt) > 0 {
+		return fmt.Errorf("jitter cannot be used together with runAt")
+	}
+	jitter, err := time.ParseDuration(data.Jitter)
+	if err != nil {
+		return fmt.Errorf("invalid jitter %s: %s", data.Jitter, err.Error())
+	}
+	if jitter <= 0 {
+		return fmt.Errorf("jitter must be positive")
+	}
+	return nil
+}
+
+// jitterOffset returns the delay, shorter than the jitter, that is added to each start time of the job.
+func jitterOffset(jobName, jitter string) time.Duration {
+	duration, err := time.ParseDuration(jitter)
+	if err != nil || duration < time.Second {
+		return 0
+	}
+	seconds := int64(duration / time.Second)
+	return time.Duration(int64(hashOf(jobName+":jitter"))%seconds) * time.Second
+}
+
+// jitterSchedule delays each time of the wrapped schedule by the offset.
+type jitterSchedule struct {
+	schedule rcron.Schedule
+	offset   time.Duration
+}
+
+func (s jitterSchedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t.Add(-s.offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.offset)
+}
+
+// jobSchedule returns the schedule the job runs at, with `H` tokens resolved and the jitter applied.
+func jobSchedule(data JobData) (rcron.Schedule, error) {
+	resolved, err := ResolveSchedule(data.Schedule, data.Name)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := parseSchedule(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if offset := jitterOffset(data.Name, data.Jitter); offset > 0 {
+		return jitterSchedule{schedule: schedule, offset: offset}, nil
+	}
+	return schedule, nil
+}
+
+// PreviewJob returns the next count times the job runs after the given time.
+func PreviewJob(data JobData, from time.Time, count int) ([]time.Time, error) {
+	times := []time.Time{}
+	schedule, err := jobSchedule(data)
+	if err != nil {
+		return times, err
+	}
+	next := from
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+	}
+	return times, nil
+}
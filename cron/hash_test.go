@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type HashTestSuite struct {
+	suite.Suite
+}
+
+func TestHashUnitTestSuite(t *testing.T) {
+	s := new(HashTestSuite)
+	suite.Run(t, s)
+}
+
+// ResolveSchedule
+
+func (s *HashTestSuite) Test_ResolveSchedule_ReplacesHashTokens() {
+	actual, err := ResolveSchedule("TZ=Europe/Berlin H H(2-4) * * H(1-5)", "backup")
+
+	s.NoError(err)
+	s.Regexp(`^TZ=Europe/Berlin 0 \d+ [2-4] \* \* [1-5]$`, actual)
+}
+
+func (s *HashTestSuite) Test_ResolveSchedule_IsStable() {
+	first, _ := ResolveSchedule("H H * * *", "backup")
+	second, _ := ResolveSchedule("H H * * *", "backup")
+
+	s.Equal(first, second)
+}
+
+func (s *HashTestSuite) Test_ResolveSchedule_SpreadsJobs() {
+	resolved := map[string]bool{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		actual, _ := ResolveSchedule("0 H * * * *", name)
+		resolved[actual] = true
+	}
+
+	s.True(len(resolved) > 1)
+}
+
+func (s *HashTestSuite) Test_ResolveSchedule_ResolvesSteps() {
+	actual, err := ResolveSchedule("0 H/15 * * * *", "backup")
+
+	s.NoError(err)
+	s.Regexp(`^0 ([0-9]|1[0-4])-59/15 \* \* \* \*$`, actual)
+	s.NoError(ValidateSchedule(actual))
+}
+
+func (s *HashTestSuite) Test_ResolveSchedule_KeepsSchedulesWithoutHashTokens() {
+	actual, err := ResolveSchedule("0 2 * * THU", "")
+
+	s.NoError(err)
+	s.Equal("0 0 2 * * THU", actual)
+}
+
+func (s *HashTestSuite) Test_ResolveSchedule_ReturnsError_WhenTokensAreInvalid() {
+	for _, schedule := range []string{"H(5-1) * * * *", "H(0-70) * * * *", "H/0 * * * *", "Hx * * * *", "@every H"} {
+		_, err := ResolveSchedule(schedule, "backup")
+
+		s.Error(err, schedule)
+	}
+	_, err := ResolveSchedule("H * * * *", "")
+
+	s.Error(err)
+}
+
+// jitter
+
+func (s *HashTestSuite) Test_ValidateJitter_ReturnsError_WhenJitterIsInvalid() {
+	s.NoError(validateJitter(JobData{Jitter: "5m"}))
+	s.Error(validateJitter(JobData{Jitter: "soon"}))
+	s.Error(validateJitter(JobData{Jitter: "-5m"}))
+	s.Error(validateJitter(JobData{Jitter: "5m", RunAt: "2017-05-06T03:00:00Z"}))
+}
+
+func (s *HashTestSuite) Test_JitterOffset_IsStableAndShorterThanJitter() {
+	offset := jitterOffset("backup", "5m")
+
+	s.Equal(offset, jitterOffset("backup", "5m"))
+	s.True(offset >= 0 && offset < 5*time.Minute)
+	s.Equal(time.Duration(0), jitterOffset("backup", ""))
+}
+
+// PreviewJob
+
+func (s *HashTestSuite) Test_PreviewJob_AppliesJitter() {
+	from := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	job := JobData{Name: "backup", Schedule: "0 0 * * * *", Jitter: "30m"}
+	offset := jitterOffset(job.Name, job.Jitter)
+	s.True(offset > 0)
+
+	actual, err := PreviewJob(job, from, 2)
+
+	s.NoError(err)
+	s.Len(actual, 2)
+	s.True(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC).Add(offset).Equal(actual[0]))
+	s.True(time.Date(2017, 5, 5, 11, 0, 0, 0, time.UTC).Add(offset).Equal(actual[1]))
+}
+
+// AddJob
+
+func (s *HashTestSuite) Test_AddJob_SchedulesResolvedSpec() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	actualSpec := ""
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		actualSpec = spec
+		return 1, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "backup", Image: "alpine", Schedule: "H 2 * * *", Created: true})
+	expected, _ := ResolveSchedule("H 2 * * *", "backup")
+
+	s.NoError(err)
+	s.Equal(expected, actualSpec)
+}
+
+func (s *HashTestSuite) Test_AddJob_SchedulesJitteredJob() {
+	rCronScheduleFuncOrig := rCronScheduleFunc
+	defer func() { rCronScheduleFunc = rCronScheduleFuncOrig }()
+	var actualSchedule rcron.Schedule
+	rCronScheduleFunc = func(c *rcron.Cron, schedule rcron.Schedule, cmd func()) rcron.EntryID {
+		actualSchedule = schedule
+		return 3
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "backup", Image: "alpine", Schedule: "@hourly", Jitter: "10m", Created: true})
+
+	s.NoError(err)
+	s.IsType(jitterSchedule{}, actualSchedule)
+	s.Equal(rcron.EntryID(3), c.Jobs["backup"])
+}
@@ -0,0 +1,193 @@
+package cron
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const historyMemorySize = 100
+
+const (
+	RunRunning   = "Running"
+	RunSucceeded = "Succeeded"
+	RunFailed    = "Failed"
+	RunSkipped   = "Skipped"
+	RunCancelled = "Cancelled"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerCatchUp  = "catch-up"
+	TriggerManual   = "manual"
+)
+
+// Run describes a single execution of a job.
+type Run struct {
+	ID          string        `json:"id"`
+	Job         string        `json:"job"`
+	Trigger     string        `json:"trigger"`
+	Status      string        `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	ScheduledAt *time.Time    `json:"scheduledAt,omitempty"`
+	StartedAt   time.Time     `json:"startedAt"`
+	FinishedAt  *time.Time    `json:"finishedAt,omitempty"`
+	Duration    string        `json:"duration,omitempty"`
+	TriggeredBy string        `json:"triggeredBy,omitempty"`
+	CancelledBy string        `json:"cancelledBy,omitempty"`
+	Service     string        `json:"service,omitempty"`
+	Container   string        `json:"container,omitempty"`
+	Response    *HTTPResponse `json:"response,omitempty"`
+	Execs       []ExecResult  `json:"execs,omitempty"`
+	Nodes       []NodeRun     `json:"nodes,omitempty"`
+
+	serviceID   string
+	iteration   uint64
+	completions int
+	global      bool
+}
+
+// NodeRun describes how the task of a global run ended on a single node.
+type NodeRun struct {
+	Node       string     `json:"node"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// JobState describes when a job runs and how its last run ended.
+type JobState struct {
+	Status      string     `json:"status,omitempty"`
+	NextRun     *time.Time `json:"nextRun,omitempty"`
+	PrevRun     *time.Time `json:"prevRun,omitempty"`
+	LastRun     *Run       `json:"lastRun,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+}
+
+// Historian stores job runs.
+type Historian interface {
+	// Record stores the run or replaces the stored run with the same ID.
+	Record(run Run) error
+	// Runs returns the latest runs of the job, newest first. All runs are returned when the limit is 0.
+	Runs(job string, limit int) ([]Run, error)
+}
+
+// NewHistorian returns a historian that keeps the latest runs of each job in memory.
+// When the path is not empty, runs are also appended to the file as JSON lines and loaded from it,
+// so that they survive restarts.
+var NewHistorian = func(path string) (Historian, error) {
+	if len(path) == 0 {
+		return &memoryHistorian{}, nil
+	}
+	h := &fileHistorian{}
+	if err := h.load(path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	h.file = file
+	return h, nil
+}
+
+var timeNow = time.Now
+
+type memoryHistorian struct {
+	mu   sync.RWMutex
+	runs map[string][]Run
+}
+
+func (h *memoryHistorian) Record(run Run) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.runs == nil {
+		h.runs = map[string][]Run{}
+	}
+	runs := h.runs[run.Job]
+	for i := range runs {
+		if runs[i].ID == run.ID {
+			runs[i] = run
+			return nil
+		}
+	}
+	runs = append(runs, run)
+	if len(runs) > historyMemorySize {
+		runs = runs[len(runs)-historyMemorySize:]
+	}
+	h.runs[run.Job] = runs
+	return nil
+}
+
+func (h *memoryHistorian) Runs(job string, limit int) ([]Run, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	runs := []Run{}
+	stored := h.runs[job]
+	for i := len(stored) - 1; i >= 0; i-- {
+		runs = append(runs, stored[i])
+		if limit > 0 && len(runs) >= limit {
+			break
+		}
+	}
+	return runs, nil
+}
+
+type fileHistorian struct {
+	memoryHistorian
+	fileMu sync.Mutex
+	file   *os.File
+}
+
+func (h *fileHistorian) Record(run Run) error {
+	js, _ := json.Marshal(run)
+	h.fileMu.Lock()
+	_, err := h.file.Write(append(js, '\n'))
+	h.fileMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return h.memoryHistorian.Record(run)
+}
+
+func (h *fileHistorian) load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		run := Run{}
+		if err := json.Unmarshal(scanner.Bytes(), &run); err == nil {
+			h.memoryHistorian.Record(run)
+		}
+	}
+	return scanner.Err()
+}
+
+func newRun(job, trigger string) Run {
+	now := timeNow().UTC()
+	return Run{
+		ID:        strconv.FormatInt(now.UnixNano(), 10),
+		Job:       job,
+		Trigger:   trigger,
+		Status:    RunRunning,
+		StartedAt: now,
+	}
+}
+
+// finish sets the status of the run and the time it finished.
+func (r *Run) finish(status, message string, finishedAt time.Time) {
+	r.Status = status
+	r.Message = message
+	r.FinishedAt = &finishedAt
+	r.Duration = finishedAt.Sub(r.StartedAt).String()
+}
@@ -0,0 +1,166 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type HistoryTestSuite struct {
+	suite.Suite
+}
+
+func TestHistoryUnitTestSuite(t *testing.T) {
+	s := new(HistoryTestSuite)
+	suite.Run(t, s)
+}
+
+// NewHistorian
+
+func (s *HistoryTestSuite) Test_NewHistorian_ReturnsRunsNewestFirst() {
+	h, _ := NewHistorian("")
+	h.Record(Run{ID: "1", Job: "my-job", Status: RunSucceeded})
+	h.Record(Run{ID: "2", Job: "my-job", Status: RunRunning})
+	h.Record(Run{ID: "3", Job: "other-job", Status: RunRunning})
+	h.Record(Run{ID: "2", Job: "my-job", Status: RunFailed})
+
+	actual, err := h.Runs("my-job", 0)
+
+	s.NoError(err)
+	s.Equal([]Run{
+		{ID: "2", Job: "my-job", Status: RunFailed},
+		{ID: "1", Job: "my-job", Status: RunSucceeded},
+	}, actual)
+	limited, _ := h.Runs("my-job", 1)
+	s.Len(limited, 1)
+}
+
+func (s *HistoryTestSuite) Test_NewHistorian_KeepsLatestRunsInMemory() {
+	h, _ := NewHistorian("")
+	for i := 0; i < historyMemorySize+10; i++ {
+		h.Record(Run{ID: string(rune('a' + i)), Job: "my-job"})
+	}
+
+	actual, _ := h.Runs("my-job", 0)
+
+	s.Len(actual, historyMemorySize)
+}
+
+func (s *HistoryTestSuite) Test_NewHistorian_LoadsRunsFromFile() {
+	file, _ := ioutil.TempFile("", "history")
+	file.Close()
+	defer os.Remove(file.Name())
+	h, _ := NewHistorian(file.Name())
+	h.Record(Run{ID: "1", Job: "my-job", Status: RunRunning})
+	h.Record(Run{ID: "1", Job: "my-job", Status: RunSucceeded})
+
+	reloaded, err := NewHistorian(file.Name())
+	actual, _ := reloaded.Runs("my-job", 0)
+
+	s.NoError(err)
+	s.Equal([]Run{{ID: "1", Job: "my-job", Status: RunSucceeded}}, actual)
+}
+
+func (s *HistoryTestSuite) Test_NewHistorian_ReturnsError_WhenFileCannotBeOpened() {
+	_, err := NewHistorian("/this/path/does/not/exist/history")
+
+	s.Error(err)
+}
+
+// GetState
+
+func (s *HistoryTestSuite) Test_GetState_ReturnsNextRunAndLastRun() {
+	started := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	history, _ := NewHistorian("")
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunSucceeded, StartedAt: started})
+	rc := rcron.New()
+	id, _ := rc.AddFunc("@hourly", func() {})
+	rc.Start()
+	defer rc.Stop()
+	c := Cron{Cron: rc, Jobs: map[string]rcron.EntryID{"my-job": id}, History: history}
+
+	actual := c.GetState("my-job")
+
+	s.NotNil(actual.NextRun)
+	s.True(actual.NextRun.After(time.Now()))
+	s.Equal(started, *actual.PrevRun)
+	s.Equal(RunSucceeded, actual.LastRun.Status)
+}
+
+func (s *HistoryTestSuite) Test_GetState_ReturnsEmptyState_WhenJobIsNotScheduled() {
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	actual := c.GetState("my-job")
+
+	s.Equal(JobState{}, actual)
+}
+
+// watchRun
+
+func (s *HistoryTestSuite) Test_WatchRun_RecordsOutcomeOfTask() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	started := time.Now().UTC()
+	finished := started.Add(10 * time.Second)
+	calls := 0
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				calls++
+				old := swarm.Task{Status: swarm.TaskStatus{State: swarm.TaskStateComplete}}
+				old.CreatedAt = started.Add(-time.Hour)
+				task := swarm.Task{Status: swarm.TaskStatus{State: swarm.TaskStateRunning}}
+				task.CreatedAt = started
+				if calls > 1 {
+					task.Status = swarm.TaskStatus{State: swarm.TaskStateFailed, Err: "exit code 1", Timestamp: finished}
+				}
+				return []swarm.Task{old, task}, nil
+			},
+		},
+	}
+
+	doneRun := Run{}
+	c.watchRun(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: started}, func(run Run) {
+		doneRun = run
+	})
+
+	actual, _ := history.Runs("my-job", 0)
+	s.Equal(2, calls)
+	s.Equal(actual[0], doneRun)
+	s.Equal(RunFailed, actual[0].Status)
+	s.Equal("exit code 1", actual[0].Message)
+	s.Equal("10s", actual[0].Duration)
+}
+
+func (s *HistoryTestSuite) Test_WatchRun_FailsRun_WhenTaskDoesNotFinishInTime() {
+	runWatchIntervalOrig := runWatchInterval
+	runWatchTimeoutOrig := runWatchTimeout
+	defer func() {
+		runWatchInterval = runWatchIntervalOrig
+		runWatchTimeout = runWatchTimeoutOrig
+	}()
+	runWatchInterval = time.Millisecond
+	runWatchTimeout = 10 * time.Millisecond
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				return []swarm.Task{}, nil
+			},
+		},
+	}
+
+	c.watchRun(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: time.Now()}, nil)
+
+	actual, _ := history.Runs("my-job", 0)
+	s.Equal(RunFailed, actual[0].Status)
+}
@@ -0,0 +1,220 @@
+package cron
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	httpTimeoutDefault    = 30 * time.Second
+	httpRetryDelayDefault = 10 * time.Second
+	// httpResponseBodyLimit is the number of bytes of the response body stored in the history
+	httpResponseBodyLimit = 1024
+)
+
+// HTTPRequest describes the request sent by a job of the http type.
+type HTTPRequest struct {
+	Method         string            `json:"method,omitempty"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	Timeout        string            `json:"timeout,omitempty"`
+	ExpectedStatus []int             `json:"expectedStatus,omitempty"`
+	Retries        int               `json:"retries,omitempty"`
+	RetryDelay     string            `json:"retryDelay,omitempty"`
+}
+
+// HTTPResponse describes the response received by the last attempt of a run of an http job.
+type HTTPResponse struct {
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       string `json:"body,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Attempts   int    `json:"attempts"`
+}
+
+// httpClient sends the requests of http jobs. Timeouts are set per request.
+var httpClient = &http.Client{}
+
+func validateHTTP(data JobData) error {
+	switch data.Type {
+	case TypeHTTP:
+	case "", TypeService, TypeExec:
+		if data.HTTP != nil {
+			return fmt.Errorf("http can be used only with the %s type", TypeHTTP)
+		}
+		return nil
+	default:
+		return fmt.Errorf("type must be %s, %s or %s", TypeService, TypeHTTP, TypeExec)
+	}
+	if data.HTTP == nil {
+		return fmt.Errorf("http is mandatory for jobs of the %s type", TypeHTTP)
+	}
+	if len(data.Image) > 0 || len(data.Command) > 0 || len(data.Args) > 0 || len(data.Executor) > 0 {
+		return fmt.Errorf("image, command, args and executor cannot be used with the %s type", TypeHTTP)
+	}
+	if len(data.ExecutionMode) > 0 || len(data.Mode) > 0 || len(data.NodeLabels) > 0 ||
+		data.Parallelism > 0 || data.Completions > 0 || data.MaxConcurrent > 0 || data.TotalCompletions > 0 {
+		return fmt.Errorf("jobs of the %s type run a single request and cannot be used with modes, parallelism or completions", TypeHTTP)
+	}
+	request := *data.HTTP
+	if _, err := http.NewRequest(httpMethod(request), "http://localhost", nil); err != nil {
+		return fmt.Errorf("invalid method %s", request.Method)
+	}
+	if u, err := url.Parse(request.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if _, err := parseDuration(request.Timeout, httpTimeoutDefault); err != nil {
+		return fmt.Errorf("invalid timeout %s", request.Timeout)
+	}
+	if _, err := parseDuration(request.RetryDelay, httpRetryDelayDefault); err != nil {
+		return fmt.Errorf("invalid retryDelay %s", request.RetryDelay)
+	}
+	if request.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	for _, status := range request.ExpectedStatus {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("invalid expected status %d", status)
+		}
+	}
+	return nil
+}
+
+type httpExecutor struct {
+	c *Cron
+}
+
+// Execute sends the request of the job, retrying it until the response has one of the expected status codes.
+func (e httpExecutor) Execute(run Run, data JobData, serviceName string, done func(Run)) {
+	c := e.c
+	c.countRun(run.Job)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.setRequest(run.ID, cancel)
+	c.recordRun(run)
+	go func() {
+		response, err := sendHTTP(ctx, *data.HTTP)
+		c.takeRequest(run.ID)
+		cancel()
+		if cancelled, ok := c.takeCancelled(run.ID); ok {
+			if done != nil {
+				done(cancelled)
+			}
+			return
+		}
+		run.Response = &response
+		if err != nil {
+			run.finish(RunFailed, err.Error(), timeNow().UTC())
+		} else {
+			run.finish(RunSucceeded, "", timeNow().UTC())
+		}
+		c.recordRun(run)
+		if done != nil {
+			done(run)
+		}
+	}()
+}
+
+// sendHTTP sends the request and retries it on errors and unexpected status codes.
+// It returns the response of the last attempt.
+func sendHTTP(ctx context.Context, request HTTPRequest) (HTTPResponse, error) {
+	timeout, _ := parseDuration(request.Timeout, httpTimeoutDefault)
+	retryDelay, _ := parseDuration(request.RetryDelay, httpRetryDelayDefault)
+	for attempt := 1; ; attempt++ {
+		response, err := sendHTTPOnce(ctx, request, timeout)
+		response.Attempts = attempt
+		if err == nil || attempt > request.Retries {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return response, err
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func sendHTTPOnce(ctx context.Context, request HTTPRequest, timeout time.Duration) (HTTPResponse, error) {
+	response := HTTPResponse{}
+	req, err := http.NewRequest(httpMethod(request), request.URL, strings.NewReader(request.Body))
+	if err != nil {
+		return response, err
+	}
+	for name, value := range request.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+		} else {
+			req.Header.Set(name, value)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return response, fmt.Errorf("could not send request: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, httpResponseBodyLimit+1))
+	if len(body) > httpResponseBodyLimit {
+		body = body[:httpResponseBodyLimit]
+		response.Truncated = true
+	}
+	response.StatusCode = resp.StatusCode
+	response.Body = string(body)
+	if !isExpectedStatus(request.ExpectedStatus, resp.StatusCode) {
+		return response, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return response, nil
+}
+
+// isExpectedStatus returns true if the status is one of the expected ones or, when none are set, a 2xx status.
+func isExpectedStatus(expected []int, status int) bool {
+	if len(expected) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, e := range expected {
+		if e == status {
+			return true
+		}
+	}
+	return false
+}
+
+func httpMethod(request HTTPRequest) string {
+	if len(request.Method) == 0 {
+		return http.MethodGet
+	}
+	return strings.ToUpper(request.Method)
+}
+
+func parseDuration(value string, defaultDuration time.Duration) (time.Duration, error) {
+	if len(value) == 0 {
+		return defaultDuration, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// setRequest stores the function that cancels the request of a running http job.
+func (c *Cron) setRequest(runID string, cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.requests == nil {
+		c.requests = map[string]context.CancelFunc{}
+	}
+	c.requests[runID] = cancel
+}
+
+// takeRequest returns the function that cancels the request of the run and forgets it.
+func (c *Cron) takeRequest(runID string) (context.CancelFunc, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cancel, ok := c.requests[runID]
+	delete(c.requests, runID)
+	return cancel, ok
+}
@@ -0,0 +1,260 @@
+package cron
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type HTTPTestSuite struct {
+	suite.Suite
+}
+
+func TestHTTPUnitTestSuite(t *testing.T) {
+	s := new(HTTPTestSuite)
+	suite.Run(t, s)
+}
+
+// validateHTTP
+
+func (s *HTTPTestSuite) Test_ValidateHTTP_AcceptsValidRequests() {
+	s.NoError(validateHTTP(JobData{Image: "alpine"}))
+	s.NoError(validateHTTP(JobData{Type: TypeHTTP, HTTP: &HTTPRequest{URL: "http://example.com/reindex"}}))
+	s.NoError(validateHTTP(JobData{Type: TypeHTTP, HTTP: &HTTPRequest{
+		Method:         "post",
+		URL:            "https://example.com/reindex",
+		Timeout:        "5s",
+		ExpectedStatus: []int{202},
+		Retries:        3,
+		RetryDelay:     "1m",
+	}}))
+}
+
+func (s *HTTPTestSuite) Test_ValidateHTTP_ReturnsError_WhenRequestIsInvalid() {
+	request := func(r HTTPRequest) JobData {
+		return JobData{Type: TypeHTTP, HTTP: &r}
+	}
+
+	s.Error(validateHTTP(JobData{Type: "lambda"}))
+	s.Error(validateHTTP(JobData{Type: TypeHTTP}))
+	s.Error(validateHTTP(JobData{HTTP: &HTTPRequest{URL: "http://example.com"}}))
+	s.Error(validateHTTP(JobData{Type: TypeHTTP, Image: "alpine", HTTP: &HTTPRequest{URL: "http://example.com"}}))
+	s.Error(validateHTTP(JobData{Type: TypeHTTP, Parallelism: 2, HTTP: &HTTPRequest{URL: "http://example.com"}}))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "example.com"})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "ftp://example.com"})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "http://example.com", Method: "GET IT"})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "http://example.com", Timeout: "5"})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "http://example.com", RetryDelay: "soon"})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "http://example.com", Retries: -1})))
+	s.Error(validateHTTP(request(HTTPRequest{URL: "http://example.com", ExpectedStatus: []int{1000}})))
+}
+
+// sendHTTP
+
+func (s *HTTPTestSuite) Test_SendHTTP_SendsRequest() {
+	actualMethod, actualHeader, actualBody := "", "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		actualMethod, actualHeader, actualBody = req.Method, req.Header.Get("X-Token"), string(body)
+		w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	actual, err := sendHTTP(context.Background(), HTTPRequest{
+		Method:  "post",
+		URL:     server.URL,
+		Headers: map[string]string{"X-Token": "secret"},
+		Body:    `{"full":true}`,
+	})
+
+	s.NoError(err)
+	s.Equal(HTTPResponse{StatusCode: 200, Body: "done", Attempts: 1}, actual)
+	s.Equal("POST", actualMethod)
+	s.Equal("secret", actualHeader)
+	s.Equal(`{"full":true}`, actualBody)
+}
+
+func (s *HTTPTestSuite) Test_SendHTTP_RetriesUnexpectedStatus() {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	actual, err := sendHTTP(context.Background(), HTTPRequest{
+		URL:            server.URL,
+		ExpectedStatus: []int{http.StatusAccepted},
+		Retries:        5,
+		RetryDelay:     "1ms",
+	})
+
+	s.NoError(err)
+	s.Equal(3, calls)
+	s.Equal(http.StatusAccepted, actual.StatusCode)
+	s.Equal(3, actual.Attempts)
+}
+
+func (s *HTTPTestSuite) Test_SendHTTP_ReturnsError_WhenRetriesAreExhausted() {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	actual, err := sendHTTP(context.Background(), HTTPRequest{URL: server.URL, Retries: 1, RetryDelay: "1ms"})
+
+	s.EqualError(err, "unexpected status code 500")
+	s.Equal(2, calls)
+	s.Equal(2, actual.Attempts)
+}
+
+func (s *HTTPTestSuite) Test_SendHTTP_TruncatesBody() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(strings.Repeat("a", httpResponseBodyLimit*2)))
+	}))
+	defer server.Close()
+
+	actual, _ := sendHTTP(context.Background(), HTTPRequest{URL: server.URL})
+
+	s.Len(actual.Body, httpResponseBodyLimit)
+	s.True(actual.Truncated)
+}
+
+func (s *HTTPTestSuite) Test_SendHTTP_ReturnsError_WhenTimeoutIsReached() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	_, err := sendHTTP(context.Background(), HTTPRequest{URL: server.URL, Timeout: "10ms"})
+
+	s.Error(err)
+}
+
+// Execute
+
+func (s *HTTPTestSuite) Test_Execute_RecordsResponse() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+	defer server.Close()
+	history, _ := NewHistorian("")
+	c := Cron{History: history}
+	finished := make(chan Run)
+
+	httpExecutor{c: &c}.Execute(newRun("my-job", TriggerSchedule), JobData{Name: "my-job", Type: TypeHTTP, HTTP: &HTTPRequest{URL: server.URL}}, "my-job", func(run Run) {
+		finished <- run
+	})
+
+	select {
+	case actual := <-finished:
+		s.Equal(RunFailed, actual.Status)
+		s.Equal("unexpected status code 404", actual.Message)
+		s.Equal(&HTTPResponse{StatusCode: 404, Body: "not found", Attempts: 1}, actual.Response)
+		runs, _ := history.Runs("my-job", 0)
+		s.Equal(actual, runs[0])
+	case <-time.After(time.Second):
+		s.Fail("the run did not finish")
+	}
+}
+
+// CancelRun
+
+func (s *HTTPTestSuite) Test_CancelRun_AbortsRequest() {
+	release := make(chan bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	history, _ := NewHistorian("")
+	c := Cron{History: history}
+	finished := make(chan Run)
+	run := newRun("my-job", TriggerSchedule)
+	httpExecutor{c: &c}.Execute(run, JobData{Name: "my-job", Type: TypeHTTP, HTTP: &HTTPRequest{URL: server.URL}}, "my-job", func(run Run) {
+		finished <- run
+	})
+
+	cancelled, err := c.CancelRun("my-job", run.ID, "ops")
+
+	s.NoError(err)
+	s.Equal(RunCancelled, cancelled.Status)
+	select {
+	case actual := <-finished:
+		s.Equal(RunCancelled, actual.Status)
+	case <-time.After(500 * time.Millisecond):
+		s.Fail("the request was not aborted")
+	}
+}
+
+// AddJob
+
+func (s *HTTPTestSuite) Test_AddJob_KeepsHTTPJobsInMemory() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, Policy: &Policy{AllowedImages: []string{"alpine"}}}
+
+	err := c.AddJob(JobData{Name: "my-job", Schedule: "@daily", Type: TypeHTTP, HTTP: &HTTPRequest{URL: "http://example.com"}})
+
+	s.NoError(err)
+	s.Contains(c.getLocalJobs(), "my-job")
+	s.Contains(c.Jobs, "my-job")
+}
+
+func (s *HTTPTestSuite) Test_RescheduleJobs_RestoresHTTPJobsFromLocalJobsFile() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	job := JobData{
+		Name:     "my-job",
+		Schedule: "@daily",
+		Type:     TypeHTTP,
+		HTTP:     &HTTPRequest{Method: "POST", URL: "http://example.com", Headers: map[string]string{"Authorization": "Bearer token"}},
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.LoadLocalJobs(path)
+	c.AddJob(job)
+	restored := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{}, nil
+		},
+	}}
+	restored.LoadLocalJobs(path)
+
+	err := restored.RescheduleJobs()
+	defer restored.Stop()
+	info, _ := os.Stat(path)
+
+	s.NoError(err)
+	s.Contains(restored.Jobs, "my-job")
+	s.Equal(job.HTTP, restored.getLocalJobs()["my-job"].HTTP)
+	s.Equal(os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+func validateCompletions(data JobData) error {
+	if data.MaxConcurrent < 0 {
+		return fmt.Errorf("maxConcurrent must not be negative")
+	}
+	if data.TotalCompletions < 0 {
+		return fmt.Errorf("totalCompletions must not be negative")
+	}
+	return nil
+}
+
+// jobConcurrency returns how many tasks of a run execute at the same time and how many of them have to complete for the run to succeed.
+// Parallelism and completions take precedence over maxConcurrent and totalCompletions.
+func jobConcurrency(data JobData) (int, int) {
+	maxConcurrent, completions := data.MaxConcurrent, data.TotalCompletions
+	if data.Parallelism > 0 || data.Completions > 0 {
+		maxConcurrent, completions = data.Parallelism, data.Completions
+	}
+	if maxConcurrent == 0 {
+		maxConcurrent = 1
+	}
+	if completions == 0 {
+		completions = maxConcurrent
+	}
+	return maxConcurrent, completions
+}
+
+// iterationOutcome returns how the iteration of a job mode service ended.
+// The iteration fails as soon as one of its tasks fails and succeeds once the given number of tasks completed.
+// The last return value is false while the iteration is still running.
+func iterationOutcome(tasks []swarm.Task, iteration uint64, completions int) (string, string, time.Time, bool) {
+	iterationTasks := []swarm.Task{}
+	for _, task := range tasks {
+		if task.JobIteration != nil && task.JobIteration.Index == iteration {
+			iterationTasks = append(iterationTasks, task)
+		}
+	}
+	return tasksOutcome(iterationTasks, completions)
+}
+
+// tasksOutcome returns how the tasks of a run ended.
+// The run fails as soon as one of the tasks fails and succeeds once the given number of tasks completed.
+func tasksOutcome(tasks []swarm.Task, completions int) (string, string, time.Time, bool) {
+	completed := 0
+	finishedAt := time.Time{}
+	for _, task := range tasks {
+		switch task.Status.State {
+		case swarm.TaskStateComplete:
+			completed++
+			if task.Status.Timestamp.After(finishedAt) {
+				finishedAt = task.Status.Timestamp
+			}
+		case swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateShutdown, swarm.TaskStateOrphaned:
+			return RunFailed, task.Status.Err, task.Status.Timestamp.UTC(), true
+		}
+	}
+	if completed > 0 && completed >= completions {
+		return RunSucceeded, "", finishedAt.UTC(), true
+	}
+	return "", "", time.Time{}, false
+}
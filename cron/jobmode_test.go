@@ -0,0 +1,184 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"../docker"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobModeTestSuite struct {
+	suite.Suite
+}
+
+func TestJobModeUnitTestSuite(t *testing.T) {
+	s := new(JobModeTestSuite)
+	suite.Run(t, s)
+}
+
+// validateCompletions
+
+func (s *JobModeTestSuite) Test_ValidateCompletions_ReturnsError_WhenNegative() {
+	s.NoError(validateCompletions(JobData{MaxConcurrent: 2, TotalCompletions: 4}))
+	s.Error(validateCompletions(JobData{MaxConcurrent: -1}))
+	s.Error(validateCompletions(JobData{TotalCompletions: -1}))
+}
+
+// jobConcurrency
+
+func (s *JobModeTestSuite) Test_JobConcurrency_DefaultsToMaxConcurrent() {
+	s.assertConcurrency(1, 1, JobData{})
+	s.assertConcurrency(2, 2, JobData{MaxConcurrent: 2})
+	s.assertConcurrency(2, 4, JobData{MaxConcurrent: 2, TotalCompletions: 4})
+}
+
+func (s *JobModeTestSuite) Test_JobConcurrency_PrefersParallelism() {
+	s.assertConcurrency(3, 3, JobData{MaxConcurrent: 2, Parallelism: 3})
+	s.assertConcurrency(1, 5, JobData{Completions: 5})
+	s.assertConcurrency(2, 6, JobData{Parallelism: 2, Completions: 6})
+}
+
+// iterationOutcome
+
+func (s *JobModeTestSuite) Test_IterationOutcome_Succeeds_WhenAllCompletionsAreDone() {
+	finished := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	tasks := []swarm.Task{
+		s.task(1, swarm.TaskStateFailed, finished),
+		s.task(2, swarm.TaskStateComplete, finished.Add(-time.Minute)),
+		s.task(2, swarm.TaskStateComplete, finished),
+	}
+
+	status, _, finishedAt, ok := iterationOutcome(tasks, 2, 2)
+
+	s.True(ok)
+	s.Equal(RunSucceeded, status)
+	s.Equal(finished, finishedAt)
+}
+
+func (s *JobModeTestSuite) Test_IterationOutcome_IsRunning_WhenCompletionsAreMissing() {
+	tasks := []swarm.Task{
+		s.task(2, swarm.TaskStateComplete, time.Now()),
+		s.task(2, swarm.TaskStateRunning, time.Now()),
+	}
+
+	_, _, _, ok := iterationOutcome(tasks, 2, 2)
+
+	s.False(ok)
+}
+
+func (s *JobModeTestSuite) Test_IterationOutcome_Fails_WhenTaskFails() {
+	task := s.task(2, swarm.TaskStateFailed, time.Now())
+	task.Status.Err = "exit code 1"
+	tasks := []swarm.Task{s.task(2, swarm.TaskStateComplete, time.Now()), task}
+
+	status, message, _, ok := iterationOutcome(tasks, 2, 2)
+
+	s.True(ok)
+	s.Equal(RunFailed, status)
+	s.Equal("exit code 1", message)
+}
+
+// startRun
+
+func (s *JobModeTestSuite) Test_StartRun_RunsJobAndWatchesIteration() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	history, _ := NewHistorian("")
+	actualService := ""
+	actualMaxConcurrent, actualTotalCompletions := uint64(0), uint64(0)
+	c := Cron{
+		History: history,
+		jobMode: true,
+		Service: ServicerMock{
+			RunJobMock: func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+				actualService = serviceName
+				actualMaxConcurrent, actualTotalCompletions = maxConcurrent, totalCompletions
+				return 7, nil
+			},
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				return []swarm.Task{
+					s.task(6, swarm.TaskStateFailed, time.Now()),
+					s.task(7, swarm.TaskStateComplete, time.Now()),
+					s.task(7, swarm.TaskStateComplete, time.Now()),
+				}, nil
+			},
+		},
+	}
+	finished := make(chan Run)
+
+	c.startRun(newRun("my-job", TriggerSchedule), JobData{Name: "my-job", MaxConcurrent: 2}, "my-service", func(run Run) {
+		finished <- run
+	})
+
+	select {
+	case actual := <-finished:
+		s.Equal(RunSucceeded, actual.Status)
+	case <-time.After(time.Second):
+		s.Fail("the run did not finish")
+	}
+	s.Equal("my-service", actualService)
+	s.Equal(uint64(2), actualMaxConcurrent)
+	s.Equal(uint64(2), actualTotalCompletions)
+}
+
+func (s *JobModeTestSuite) Test_StartRun_FailsRun_WhenRunJobFails() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		jobMode: true,
+		Service: ServicerMock{
+			RunJobMock: func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+				return 0, fmt.Errorf("This is an error")
+			},
+		},
+	}
+	actual := Run{}
+
+	c.startRun(newRun("my-job", TriggerSchedule), JobData{Name: "my-job"}, "my-service", func(run Run) {
+		actual = run
+	})
+
+	s.Equal(RunFailed, actual.Status)
+	s.Contains(actual.Message, "This is an error")
+}
+
+func (s *JobModeTestSuite) Test_StartRun_ScalesService_WhenServiceIsNotJob() {
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		jobMode: true,
+		Service: ServicerMock{
+			RunJobMock: func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+				return 0, docker.ErrNotJob
+			},
+		},
+	}
+	actual := Run{}
+
+	c.startRun(newRun("my-job", TriggerSchedule), JobData{Name: "my-job"}, "my-service", func(run Run) {
+		actual = run
+	})
+
+	// Scaling fails without Docker, which shows that the scale command was used
+	s.Equal(RunFailed, actual.Status)
+	s.Contains(actual.Message, "could not scale service my-service")
+}
+
+// Util
+
+func (s *JobModeTestSuite) assertConcurrency(expectedMaxConcurrent, expectedCompletions int, data JobData) {
+	maxConcurrent, completions := jobConcurrency(data)
+	s.Equal(expectedMaxConcurrent, maxConcurrent)
+	s.Equal(expectedCompletions, completions)
+}
+
+func (s *JobModeTestSuite) task(iteration uint64, state swarm.TaskState, timestamp time.Time) swarm.Task {
+	return swarm.Task{
+		JobIteration: &swarm.Version{Index: iteration},
+		Status:       swarm.TaskStatus{State: state, Timestamp: timestamp},
+	}
+}
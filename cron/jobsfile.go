@@ -0,0 +1,351 @@
+package cron
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"gopkg.in/yaml.v2"
+)
+
+const ManagedByFile = "file"
+
+type PlanChange struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Reason string   `json:"reason"`
+	Job    *JobData `json:"job,omitempty"`
+	// Error is set when the job is invalid. Changes with errors are not applied.
+	Error string `json:"error,omitempty"`
+}
+
+type Plan struct {
+	Changes []PlanChange `json:"changes"`
+}
+
+// LoadJobsFile reads job definitions from a YAML or JSON file.
+func LoadJobsFile(path string) ([]JobData, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []JobData{}, err
+	}
+	return ParseJobs(content)
+}
+
+// ParseJobs parses job definitions in YAML or JSON format.
+// Definitions can be either a list of jobs or an object with the `jobs` list.
+// Field names are the same as the ones used by the API.
+func ParseJobs(content []byte) ([]JobData, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return []JobData{}, err
+	}
+	raw = convertYAML(raw)
+	if m, ok := raw.(map[string]interface{}); ok {
+		raw = m["jobs"]
+	}
+	js, _ := json.Marshal(raw)
+	jobs := []JobData{}
+	if raw != nil {
+		if err := json.Unmarshal(js, &jobs); err != nil {
+			return []JobData{}, fmt.Errorf("could not parse jobs: %s", err.Error())
+		}
+	}
+	names := map[string]bool{}
+	for i, job := range jobs {
+		if len(job.Name) == 0 {
+			return []JobData{}, fmt.Errorf("job %d does not have a name", i+1)
+		}
+		if names[job.Name] {
+			return []JobData{}, fmt.Errorf("job %s is defined more than once", job.Name)
+		}
+		names[job.Name] = true
+	}
+	return jobs, nil
+}
+
+// convertYAML converts maps decoded by yaml into maps that can be encoded as JSON.
+func convertYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, val := range v {
+			m[fmt.Sprintf("%v", key)] = convertYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = convertYAML(val)
+		}
+	}
+	return value
+}
+
+// Plan compares job definitions with the jobs scheduled in Swarm.
+// Jobs that are not defined are deleted only if they are managed by the jobs file.
+func (c *Cron) Plan(jobs []JobData) (Plan, error) {
+	plan := Plan{Changes: []PlanChange{}}
+	services, err := c.Service.GetServices("")
+	if err != nil && c.Executor != ExecutorContainer {
+		return plan, err
+	}
+	current := map[string]swarm.Service{}
+	for _, service := range services {
+		current[service.Spec.Annotations.Labels["com.df.cron.name"]] = service
+	}
+	for name, job := range c.getLocalJobs() {
+		current[name] = localJobService(job)
+	}
+	defined := map[string]bool{}
+	for _, job := range jobs {
+		job := job
+		job.Schedule = NormalizeSchedule(job.Schedule)
+		defined[job.Name] = true
+		service, ok := current[job.Name]
+		labels := service.Spec.Annotations.Labels
+		reason := ""
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, c.planChange("create", "job does not exist", job))
+			continue
+		case labels["com.df.cron.managed-by"] != ManagedByFile:
+			reason = "job is not managed by the jobs file"
+		case labels["com.df.cron.hash"] != jobHash(job):
+			reason = "definition changed"
+		case isModifiedManually(service, job):
+			reason = "service was modified outside of the jobs file"
+		default:
+			continue
+		}
+		plan.Changes = append(plan.Changes, c.planChange("update", reason, job))
+	}
+	names := []string{}
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] && current[name].Spec.Annotations.Labels["com.df.cron.managed-by"] == ManagedByFile {
+			plan.Changes = append(plan.Changes, PlanChange{Name: name, Action: "delete", Reason: "job is not defined"})
+		}
+	}
+	return plan, nil
+}
+
+// planChange returns a change that creates or updates the job. Invalid jobs are reported through the error of the change.
+func (c *Cron) planChange(action, reason string, job JobData) PlanChange {
+	change := PlanChange{Name: job.Name, Action: action, Reason: reason, Job: &job}
+	if err := c.validateJob(managedJob(job)); err != nil {
+		change.Error = err.Error()
+	}
+	return change
+}
+
+// Apply executes the changes of a plan. It continues on failures and returns the last error.
+// Jobs are validated before anything is changed so that an invalid update does not remove the existing job.
+func (c *Cron) Apply(plan Plan) error {
+	var lastErr error
+	for _, change := range plan.Changes {
+		err := c.validateChange(change)
+		if err == nil {
+			switch change.Action {
+			case "create":
+				err = c.AddJob(managedJob(*change.Job))
+			case "update":
+				err = c.updateJob(managedJob(*change.Job))
+			case "delete":
+				err = c.RemoveJob(change.Name)
+			}
+		}
+		if err != nil {
+			fmt.Printf("Could not %s job %s: %s\n", change.Action, change.Name, err.Error())
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// updateJob replaces the job with its new definition. Suspended jobs stay suspended.
+// When the new definition cannot be added, e.g. because its service cannot be created, the old job is restored.
+func (c *Cron) updateJob(data JobData) error {
+	old, err := c.getJobData(data.Name)
+	if err != nil {
+		return err
+	}
+	services := []swarm.Service{}
+	if !c.isLocalJob(old) {
+		if services, err = c.Service.GetServices(data.Name); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	reason, suspended := c.suspended[data.Name]
+	runs, counted := c.runs[data.Name]
+	c.mu.Unlock()
+	// RemoveJob forgets the state of the job, so it is set again before the job is added
+	keepState := func() {
+		if suspended {
+			c.setSuspended(data.Name, reason)
+		}
+		if counted {
+			c.mu.Lock()
+			c.runs[data.Name] = runs
+			c.mu.Unlock()
+		}
+	}
+	if err := c.RemoveJob(data.Name); err != nil {
+		return err
+	}
+	keepState()
+	addErr := c.AddJob(data)
+	if addErr == nil {
+		if suspended {
+			c.labelSuspended(data, reason)
+		}
+		return nil
+	}
+	fmt.Println("Restoring job", data.Name, "since it could not be updated:", addErr.Error())
+	if err := c.RemoveJob(data.Name); err != nil {
+		return fmt.Errorf("%s and the old job could not be restored: %s", addErr.Error(), err.Error())
+	}
+	keepState()
+	for _, service := range services {
+		if err := c.Service.CreateService(service.Spec); err != nil {
+			return fmt.Errorf("%s and the old job could not be restored: %s", addErr.Error(), err.Error())
+		}
+	}
+	old.Created = true
+	if err := c.AddJob(old); err != nil {
+		return fmt.Errorf("%s and the old job could not be restored: %s", addErr.Error(), err.Error())
+	}
+	if suspended {
+		c.labelSuspended(old, reason)
+	}
+	return addErr
+}
+
+// validateChange returns the error of the change or, since plans can be sent through the API, validates its job again.
+func (c *Cron) validateChange(change PlanChange) error {
+	if len(change.Error) > 0 {
+		return errors.New(change.Error)
+	}
+	if change.Job == nil {
+		return nil
+	}
+	job := managedJob(*change.Job)
+	job.Schedule = NormalizeSchedule(job.Schedule)
+	return c.validateJob(job)
+}
+
+// SyncJobsFile schedules the jobs defined in the file and removes the ones that were deleted from it.
+func (c *Cron) SyncJobsFile(path string) error {
+	jobs, err := LoadJobsFile(path)
+	if err != nil {
+		return err
+	}
+	plan, err := c.Plan(jobs)
+	if err != nil {
+		return err
+	}
+	return c.Apply(plan)
+}
+
+// WatchJobsFile synchronizes jobs every time the modification time of the file changes.
+func (c *Cron) WatchJobsFile(path string, interval time.Duration) {
+	lastModified := time.Time{}
+	if info, err := os.Stat(path); err == nil {
+		lastModified = info.ModTime()
+	}
+	go func() {
+		for range time.Tick(interval) {
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(lastModified) {
+				continue
+			}
+			lastModified = info.ModTime()
+			fmt.Println("Reloading jobs file", path)
+			if err := c.SyncJobsFile(path); err != nil {
+				fmt.Println("Could not reload jobs file:", err.Error())
+			}
+		}
+	}()
+}
+
+func managedJob(job JobData) JobData {
+	job.ManagedBy = ManagedByFile
+	job.Created = false
+	return job
+}
+
+// jobHash returns a digest of the fields that define a job.
+func jobHash(job JobData) string {
+	if job.Args == nil {
+		job.Args = []string{}
+	}
+	fields := []interface{}{job.Name, job.ServiceName, job.Image, job.Command, job.Schedule, job.Args}
+	if len(job.RunAt) > 0 || len(job.TTL) > 0 {
+		fields = append(fields, job.RunAt, job.TTL)
+	}
+	if len(job.StartingDeadline) > 0 || len(job.CatchUp) > 0 || job.CatchUpLimit > 0 {
+		fields = append(fields, job.StartingDeadline, job.CatchUp, job.CatchUpLimit)
+	}
+	if len(job.Jitter) > 0 {
+		fields = append(fields, job.Jitter)
+	}
+	if len(job.NotBefore) > 0 || len(job.NotAfter) > 0 || job.MaxRuns > 0 || len(job.OnExpiry) > 0 {
+		fields = append(fields, job.NotBefore, job.NotAfter, job.MaxRuns, job.OnExpiry)
+	}
+	if len(job.ExcludeCalendars) > 0 {
+		fields = append(fields, job.ExcludeCalendars)
+	}
+	if len(job.ExecutionMode) > 0 || job.Retention > 0 {
+		fields = append(fields, job.ExecutionMode, job.Retention)
+	}
+	if job.MaxConcurrent > 0 || job.TotalCompletions > 0 {
+		fields = append(fields, job.MaxConcurrent, job.TotalCompletions)
+	}
+	if len(job.Mode) > 0 || len(job.NodeLabels) > 0 {
+		fields = append(fields, job.Mode, job.NodeLabels)
+	}
+	if job.Parallelism > 0 || job.Completions > 0 {
+		fields = append(fields, job.Parallelism, job.Completions)
+	}
+	if len(job.Executor) > 0 {
+		fields = append(fields, job.Executor)
+	}
+	if len(job.Type) > 0 || job.HTTP != nil {
+		fields = append(fields, job.Type, job.HTTP)
+	}
+	if job.Exec != nil {
+		fields = append(fields, job.Exec)
+	}
+	js, _ := json.Marshal(fields)
+	return fmt.Sprintf("%x", sha256.Sum256(js))[:16]
+}
+
+// isModifiedManually returns true if the schedule or the image of the service no longer match the definition,
+// e.g. after `docker service update`.
+func isModifiedManually(service swarm.Service, job JobData) bool {
+	if normalizeImage(service.Spec.TaskTemplate.ContainerSpec.Image) != normalizeImage(job.Image) {
+		return true
+	}
+	return service.Spec.Annotations.Labels["com.df.cron.schedule"] != job.Schedule
+}
+
+// normalizeImage removes the digest Swarm appends to images and adds the default tag.
+func normalizeImage(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		image = image + ":latest"
+	}
+	return image
+}
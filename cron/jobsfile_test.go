@@ -0,0 +1,352 @@
+package cron
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type JobsFileTestSuite struct {
+	suite.Suite
+}
+
+func TestJobsFileUnitTestSuite(t *testing.T) {
+	s := new(JobsFileTestSuite)
+	suite.Run(t, s)
+}
+
+// ParseJobs
+
+func (s *JobsFileTestSuite) Test_ParseJobs_ParsesYAML() {
+	content := `
+jobs:
+  - name: my-job
+    image: alpine
+    schedule: "@daily"
+    servicename: my-service
+    args:
+      - --network my-net
+`
+	expected := []JobData{{
+		Name:        "my-job",
+		ServiceName: "my-service",
+		Image:       "alpine",
+		Schedule:    "@daily",
+		Args:        []string{"--network my-net"},
+	}}
+
+	actual, err := ParseJobs([]byte(content))
+
+	s.NoError(err)
+	s.Equal(expected, actual)
+}
+
+func (s *JobsFileTestSuite) Test_ParseJobs_ParsesJSONList() {
+	content := `[{"name": "my-job", "image": "alpine", "schedule": "@daily"}]`
+
+	actual, err := ParseJobs([]byte(content))
+
+	s.NoError(err)
+	s.Equal([]JobData{{Name: "my-job", Image: "alpine", Schedule: "@daily"}}, actual)
+}
+
+func (s *JobsFileTestSuite) Test_ParseJobs_ReturnsError_WhenNameIsMissing() {
+	_, err := ParseJobs([]byte(`[{"image": "alpine"}]`))
+
+	s.Error(err)
+}
+
+func (s *JobsFileTestSuite) Test_ParseJobs_ReturnsError_WhenNameIsDuplicated() {
+	_, err := ParseJobs([]byte(`[{"name": "my-job"}, {"name": "my-job"}]`))
+
+	s.Error(err)
+}
+
+// LoadJobsFile
+
+func (s *JobsFileTestSuite) Test_LoadJobsFile_ReturnsError_WhenFileDoesNotExist() {
+	_, err := LoadJobsFile("/this/file/does/not/exist.yml")
+
+	s.Error(err)
+}
+
+func (s *JobsFileTestSuite) Test_LoadJobsFile_ReadsFile() {
+	file, _ := ioutil.TempFile("", "jobs")
+	defer os.Remove(file.Name())
+	file.WriteString("- name: my-job\n  image: alpine\n")
+	file.Close()
+
+	actual, err := LoadJobsFile(file.Name())
+
+	s.NoError(err)
+	s.Len(actual, 1)
+}
+
+// Plan
+
+func (s *JobsFileTestSuite) Test_Plan_ReturnsChanges() {
+	unchanged := JobData{Name: "unchanged", Image: "alpine", Schedule: "@daily"}
+	changed := JobData{Name: "changed", Image: "alpine", Schedule: "@daily"}
+	edited := JobData{Name: "edited", Image: "alpine", Schedule: "@daily"}
+	manual := JobData{Name: "manual", Image: "alpine", Schedule: "@daily"}
+	created := JobData{Name: "created", Image: "alpine", Schedule: "@daily"}
+	oldChanged := changed
+	oldChanged.Schedule = "@hourly"
+	services := []swarm.Service{
+		s.service(unchanged, "alpine:latest@sha256:1234", true),
+		s.service(oldChanged, "alpine", true),
+		s.service(edited, "ubuntu", true),
+		s.service(manual, "alpine", false),
+		s.service(JobData{Name: "removed", Image: "alpine"}, "alpine", true),
+		s.service(JobData{Name: "not-managed", Image: "alpine"}, "alpine", false),
+	}
+	c := Cron{Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return services, nil
+		},
+	}}
+
+	plan, err := c.Plan([]JobData{unchanged, changed, edited, manual, created})
+
+	s.NoError(err)
+	actual := map[string]string{}
+	for _, change := range plan.Changes {
+		actual[change.Name] = fmt.Sprintf("%s: %s", change.Action, change.Reason)
+	}
+	s.Equal(map[string]string{
+		"changed": "update: definition changed",
+		"edited":  "update: service was modified outside of the jobs file",
+		"manual":  "update: job is not managed by the jobs file",
+		"created": "create: job does not exist",
+		"removed": "delete: job is not defined",
+	}, actual)
+}
+
+func (s *JobsFileTestSuite) Test_Plan_ReturnsError_WhenJobIsInvalid() {
+	valid := JobData{Name: "valid", Image: "alpine", Schedule: "@daily"}
+	invalid := JobData{Name: "invalid", Image: "alpine", Schedule: "not a schedule"}
+	c := Cron{Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{s.service(JobData{Name: "invalid", Image: "alpine", Schedule: "@hourly"}, "alpine", true)}, nil
+		},
+	}}
+
+	plan, err := c.Plan([]JobData{valid, invalid})
+
+	s.NoError(err)
+	s.Require().Len(plan.Changes, 2)
+	s.Equal("valid", plan.Changes[0].Name)
+	s.Empty(plan.Changes[0].Error)
+	s.Equal("invalid", plan.Changes[1].Name)
+	s.Equal("update", plan.Changes[1].Action)
+	s.NotEmpty(plan.Changes[1].Error)
+}
+
+func (s *JobsFileTestSuite) Test_Plan_ComparesNormalizedSchedules() {
+	job := JobData{Name: "my-job", Image: "alpine", Schedule: "CRON_TZ=UTC 0 2 * * *"}
+	scheduled := job
+	scheduled.Schedule = "TZ=UTC 0 0 2 * * *"
+	c := Cron{Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{s.service(scheduled, "alpine", true)}, nil
+		},
+	}}
+
+	plan, _ := c.Plan([]JobData{job})
+
+	s.Empty(plan.Changes)
+}
+
+func (s *JobsFileTestSuite) Test_Plan_ReturnsError_WhenGetServicesFail() {
+	c := Cron{Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{}, fmt.Errorf("This is an error")
+		},
+	}}
+
+	_, err := c.Plan([]JobData{})
+
+	s.Error(err)
+}
+
+// Apply
+
+func (s *JobsFileTestSuite) Test_Apply_ExecutesChanges() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	createServiceFuncOrig := createServiceFunc
+	defer func() { createServiceFunc = createServiceFuncOrig }()
+	createServiceFunc = func(cmd string) ([]byte, error) {
+		return nil, nil
+	}
+	removed := []string{}
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{},
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{s.service(JobData{Name: "updated", Schedule: "@daily"}, "alpine", true)}, nil
+			},
+			RemoveServicesMock: func(jobName string) error {
+				removed = append(removed, jobName)
+				return nil
+			},
+		},
+		Policy: &Policy{DeniedImages: []string{"ubuntu"}},
+	}
+	plan := Plan{Changes: []PlanChange{
+		{Name: "updated", Action: "update", Job: &JobData{Name: "updated", Image: "alpine", Schedule: "@daily", Created: true}},
+		{Name: "denied", Action: "create", Job: &JobData{Name: "denied", Image: "ubuntu", Schedule: "@daily", Created: true}},
+		{Name: "deleted", Action: "delete"},
+	}}
+
+	err := c.Apply(plan)
+
+	s.Error(err)
+	s.Equal([]string{"updated", "deleted"}, removed)
+	s.Contains(c.Jobs, "updated")
+	s.NotContains(c.Jobs, "denied")
+}
+
+func (s *JobsFileTestSuite) Test_AddJob_ReturnsError_WhenManagedByIsUnknown() {
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", ManagedBy: `x" ; rm -rf / #`})
+
+	s.EqualError(err, "managedBy must be empty or file")
+	s.NotContains(c.Jobs, "my-job")
+}
+
+func (s *JobsFileTestSuite) Test_Apply_DoesNotRemoveJob_WhenUpdateIsInvalid() {
+	removed := []string{}
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{"existing": 1, "denied": 2},
+		Service: ServicerMock{
+			RemoveServicesMock: func(jobName string) error {
+				removed = append(removed, jobName)
+				return nil
+			},
+		},
+		Policy: &Policy{DeniedImages: []string{"ubuntu"}},
+	}
+	plan := Plan{Changes: []PlanChange{
+		{Name: "existing", Action: "update", Job: &JobData{Name: "existing", Image: "alpine", Schedule: "@daily"}, Error: "schedule is invalid"},
+		{Name: "denied", Action: "update", Job: &JobData{Name: "denied", Image: "ubuntu", Schedule: "@daily"}},
+	}}
+
+	err := c.Apply(plan)
+
+	s.Error(err)
+	s.Empty(removed)
+	s.Contains(c.Jobs, "existing")
+	s.Contains(c.Jobs, "denied")
+}
+
+func (s *JobsFileTestSuite) Test_Apply_RestoresJob_WhenUpdateFails() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	createServiceFuncOrig := createServiceFunc
+	defer func() { createServiceFunc = createServiceFuncOrig }()
+	createServiceFunc = func(cmd string) ([]byte, error) {
+		return nil, fmt.Errorf("exit status 1")
+	}
+	old := s.service(JobData{Name: "my-job", Schedule: "@daily"}, "alpine", true)
+	restored := []swarm.ServiceSpec{}
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{"my-job": 1},
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{old}, nil
+			},
+			RemoveServicesMock: func(jobName string) error {
+				return nil
+			},
+			CreateServiceMock: func(spec swarm.ServiceSpec) error {
+				restored = append(restored, spec)
+				return nil
+			},
+		},
+	}
+	plan := Plan{Changes: []PlanChange{
+		{Name: "my-job", Action: "update", Job: &JobData{Name: "my-job", Image: "alpine:3.5", Schedule: "@hourly"}},
+	}}
+
+	err := c.Apply(plan)
+
+	s.Error(err)
+	s.Equal([]swarm.ServiceSpec{old.Spec}, restored)
+	s.Contains(c.Jobs, "my-job")
+}
+
+func (s *JobsFileTestSuite) Test_Apply_KeepsJobSuspended_WhenUpdated() {
+	createServiceFuncOrig := createServiceFunc
+	updateServiceFuncOrig := updateServiceFunc
+	defer func() {
+		createServiceFunc = createServiceFuncOrig
+		updateServiceFunc = updateServiceFuncOrig
+	}()
+	createServiceFunc = func(cmd string) ([]byte, error) {
+		return nil, nil
+	}
+	updates := []string{}
+	updateServiceFunc = func(cmd string) ([]byte, error) {
+		updates = append(updates, cmd)
+		return nil, nil
+	}
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{},
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{s.service(JobData{Name: "my-job", Schedule: "@daily"}, "alpine", true)}, nil
+			},
+			RemoveServicesMock: func(jobName string) error {
+				return nil
+			},
+		},
+	}
+	c.setSuspended("my-job", "suspended by ops")
+	plan := Plan{Changes: []PlanChange{
+		{Name: "my-job", Action: "update", Job: &JobData{Name: "my-job", Image: "alpine:3.5", Schedule: "@hourly"}},
+	}}
+
+	err := c.Apply(plan)
+
+	s.NoError(err)
+	s.NotContains(c.Jobs, "my-job")
+	reason, ok := c.suspendedReason("my-job")
+	s.True(ok)
+	s.Equal("suspended by ops", reason)
+	s.Equal([]string{`docker service update --label-add 'com.df.cron.suspended=suspended by ops' my-job`}, updates)
+}
+
+// Util
+
+func (s *JobsFileTestSuite) service(job JobData, image string, managed bool) swarm.Service {
+	service := swarm.Service{}
+	service.Spec.Name = job.Name
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: image}
+	service.Spec.Annotations.Labels = map[string]string{
+		"com.df.cron":          "true",
+		"com.df.cron.name":     job.Name,
+		"com.df.cron.schedule": job.Schedule,
+	}
+	if managed {
+		service.Spec.Annotations.Labels["com.df.cron.managed-by"] = ManagedByFile
+		service.Spec.Annotations.Labels["com.df.cron.hash"] = jobHash(job)
+	}
+	return service
+}
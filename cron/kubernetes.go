@@ -0,0 +1,586 @@
+package cron
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+type cronJob struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   objectMeta             `yaml:"metadata"`
+	Spec       cronJobSpec            `yaml:"spec"`
+	Other      map[string]interface{} `yaml:",inline"`
+}
+
+type objectMeta struct {
+	Name   string                 `yaml:"name"`
+	Labels map[string]string      `yaml:"labels,omitempty"`
+	Other  map[string]interface{} `yaml:",inline"`
+}
+
+type cronJobSpec struct {
+	Schedule                string                 `yaml:"schedule"`
+	TimeZone                string                 `yaml:"timeZone,omitempty"`
+	ConcurrencyPolicy       string                 `yaml:"concurrencyPolicy,omitempty"`
+	StartingDeadlineSeconds *int64                 `yaml:"startingDeadlineSeconds,omitempty"`
+	JobTemplate             jobTemplate            `yaml:"jobTemplate"`
+	Other                   map[string]interface{} `yaml:",inline"`
+}
+
+type jobTemplate struct {
+	Metadata map[string]interface{} `yaml:"metadata,omitempty"`
+	Spec     jobSpec                `yaml:"spec"`
+	Other    map[string]interface{} `yaml:",inline"`
+}
+
+type jobSpec struct {
+	ActiveDeadlineSeconds *int64                 `yaml:"activeDeadlineSeconds,omitempty"`
+	BackoffLimit          *int64                 `yaml:"backoffLimit,omitempty"`
+	Template              podTemplate            `yaml:"template"`
+	Other                 map[string]interface{} `yaml:",inline"`
+}
+
+type podTemplate struct {
+	Metadata map[string]interface{} `yaml:"metadata,omitempty"`
+	Spec     podSpec                `yaml:"spec"`
+	Other    map[string]interface{} `yaml:",inline"`
+}
+
+type podSpec struct {
+	RestartPolicy string                 `yaml:"restartPolicy"`
+	Containers    []container            `yaml:"containers"`
+	Other         map[string]interface{} `yaml:",inline"`
+}
+
+type container struct {
+	Name       string                 `yaml:"name"`
+	Image      string                 `yaml:"image"`
+	Command    []string               `yaml:"command,omitempty"`
+	Args       []string               `yaml:"args,omitempty"`
+	WorkingDir string                 `yaml:"workingDir,omitempty"`
+	Env        []envVar               `yaml:"env,omitempty"`
+	Resources  resourceRequirements   `yaml:"resources,omitempty"`
+	Other      map[string]interface{} `yaml:",inline"`
+}
+
+type envVar struct {
+	Name      string      `yaml:"name"`
+	Value     string      `yaml:"value"`
+	ValueFrom interface{} `yaml:"valueFrom,omitempty"`
+}
+
+type resourceRequirements struct {
+	Limits   map[string]string `yaml:"limits,omitempty"`
+	Requests map[string]string `yaml:"requests,omitempty"`
+}
+
+type serviceFlag struct {
+	Name  string
+	Value string
+}
+
+// serviceBoolFlags are the `docker service create` flags that do not take a value.
+var serviceBoolFlags = map[string]bool{
+	"-d": true, "--detach": true, "--init": true, "--no-healthcheck": true, "--no-resolve-image": true,
+	"-q": true, "--quiet": true, "--read-only": true, "-t": true, "--tty": true, "--with-registry-auth": true,
+}
+
+var k8sDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+var k8sQuantityRegexp = regexp.MustCompile(`^([0-9.]+)(Ki|Mi|Gi|Ti|k|M|G|T)?$`)
+
+// ExportCronJobs converts jobs into Kubernetes `batch/v1` CronJob manifests separated by `---`.
+// Jobs that cannot be converted are skipped. The returned warnings list skipped jobs
+// and the fields that could not be translated.
+func ExportCronJobs(jobs []JobData) ([]byte, []string) {
+	warnings := []string{}
+	buffer := bytes.Buffer{}
+	for _, job := range jobs {
+		manifest, jobWarnings, err := toCronJob(job)
+		for _, w := range jobWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", job.Name, w))
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: job was skipped: %s", job.Name, err.Error()))
+			continue
+		}
+		out, _ := yaml.Marshal(manifest)
+		if buffer.Len() > 0 {
+			buffer.WriteString("---\n")
+		}
+		buffer.Write(out)
+	}
+	return buffer.Bytes(), warnings
+}
+
+// ParseCronJobs converts Kubernetes CronJob manifests into jobs.
+// Multiple manifests can be separated by `---`. Documents of other kinds are skipped.
+func ParseCronJobs(content []byte) (Import, error) {
+	result := Import{Jobs: []JobData{}, Warnings: []string{}}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	names := map[string]bool{}
+	for i := 1; ; i++ {
+		manifest := cronJob{}
+		err := decoder.Decode(&manifest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("document %d: %s", i, err.Error())
+		}
+		if len(manifest.Kind) == 0 && len(manifest.Metadata.Name) == 0 {
+			continue
+		}
+		if manifest.Kind != "CronJob" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("document %d: kind %s is not supported and was skipped", i, manifest.Kind))
+			continue
+		}
+		job, warnings, err := fromCronJob(manifest)
+		for _, w := range warnings {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", job.Name, w))
+		}
+		if err != nil {
+			return result, fmt.Errorf("document %d: %s", i, err.Error())
+		}
+		if names[job.Name] {
+			return result, fmt.Errorf("document %d: job %s is defined more than once", i, job.Name)
+		}
+		names[job.Name] = true
+		result.Jobs = append(result.Jobs, job)
+	}
+	return result, nil
+}
+
+func toCronJob(data JobData) (cronJob, []string, error) {
+	warnings := []string{}
+	schedule, timeZone, err := toKubernetesSchedule(data.Schedule)
+	if err != nil {
+		return cronJob{}, warnings, err
+	}
+	flags, image, command, err := jobArguments(data)
+	if err != nil {
+		return cronJob{}, warnings, err
+	}
+	c := container{Name: data.Name, Image: image, Args: command}
+	pod := podSpec{RestartPolicy: "Never"}
+	job := jobSpec{}
+	labels := map[string]string{}
+	for _, flag := range flags {
+		switch flag.Name {
+		case "-e", "--env":
+			kv := strings.SplitN(flag.Value, "=", 2)
+			if len(kv) == 1 {
+				kv = append(kv, "")
+			}
+			c.Env = append(c.Env, envVar{Name: kv[0], Value: kv[1]})
+		case "--limit-cpu", "--reserve-cpu":
+			cpu, err := strconv.ParseFloat(flag.Value, 64)
+			if err != nil {
+				return cronJob{}, warnings, fmt.Errorf("invalid cpu value %s", flag.Value)
+			}
+			setResource(&c.Resources, flag.Name == "--limit-cpu", "cpu", formatCPU(cpu))
+		case "--limit-memory", "--reserve-memory":
+			memory, err := parseBytes(flag.Value)
+			if err != nil {
+				return cronJob{}, warnings, err
+			}
+			setResource(&c.Resources, flag.Name == "--limit-memory", "memory", formatMemory(memory))
+		case "--restart-condition":
+			if flag.Value == "on-failure" {
+				pod.RestartPolicy = "OnFailure"
+			}
+		case "--restart-max-attempts":
+			attempts, err := strconv.ParseInt(flag.Value, 10, 64)
+			if err != nil {
+				return cronJob{}, warnings, fmt.Errorf("invalid --restart-max-attempts value %s", flag.Value)
+			}
+			job.BackoffLimit = &attempts
+		case "-w", "--workdir":
+			c.WorkingDir = flag.Value
+		case "--entrypoint":
+			c.Command, _ = splitShellWords(flag.Value)
+		case "-l", "--label":
+			kv := strings.SplitN(flag.Value, "=", 2)
+			if strings.HasPrefix(kv[0], "com.df.cron") {
+				continue
+			}
+			if len(kv) == 1 {
+				kv = append(kv, "")
+			}
+			labels[kv[0]] = kv[1]
+		case "--name", "--replicas":
+		default:
+			warnings = append(warnings, fmt.Sprintf("argument %s cannot be translated", flag.Name))
+		}
+	}
+	pod.Containers = []container{c}
+	job.Template = podTemplate{Spec: pod}
+	manifest := cronJob{
+		APIVersion: "batch/v1",
+		Kind:       "CronJob",
+		Metadata:   objectMeta{Name: data.Name},
+		Spec: cronJobSpec{
+			Schedule: schedule,
+			TimeZone: timeZone,
+			// Scaling a service that is already running does not start another task.
+			ConcurrencyPolicy: "Forbid",
+			JobTemplate:       jobTemplate{Spec: job},
+		},
+	}
+	if len(labels) > 0 {
+		manifest.Metadata.Labels = labels
+	}
+	if deadline, err := time.ParseDuration(data.StartingDeadline); err == nil {
+		seconds := int64(deadline / time.Second)
+		manifest.Spec.StartingDeadlineSeconds = &seconds
+	}
+	if data.CatchUp == CatchUpAll {
+		warnings = append(warnings, "catchUp all cannot be translated; Kubernetes starts only the latest missed run")
+	}
+	return manifest, warnings, nil
+}
+
+func fromCronJob(manifest cronJob) (JobData, []string, error) {
+	warnings := []string{}
+	data := JobData{Name: manifest.Metadata.Name, Args: []string{}}
+	if len(data.Name) == 0 {
+		return data, warnings, fmt.Errorf("metadata.name is mandatory")
+	}
+	spec := manifest.Spec
+	timeZone, schedule, err := splitTimeZone(spec.Schedule)
+	if err != nil {
+		return data, warnings, err
+	}
+	if len(spec.TimeZone) > 0 {
+		timeZone = spec.TimeZone
+	}
+	if fields := strings.Fields(schedule); len(fields) == 5 {
+		schedule = "0 " + strings.Join(fields, " ")
+	} else if !strings.HasPrefix(schedule, "@") {
+		return data, warnings, fmt.Errorf("invalid schedule %s", spec.Schedule)
+	}
+	if len(timeZone) > 0 {
+		schedule = fmt.Sprintf("TZ=%s %s", timeZone, schedule)
+	}
+	data.Schedule = schedule
+	switch spec.ConcurrencyPolicy {
+	case "", "Forbid":
+	default:
+		warnings = append(warnings, fmt.Sprintf("concurrencyPolicy %s cannot be translated; executions never overlap", spec.ConcurrencyPolicy))
+	}
+	if spec.StartingDeadlineSeconds != nil {
+		// Kubernetes starts the latest missed run as long as the deadline did not pass.
+		data.StartingDeadline = (time.Duration(*spec.StartingDeadlineSeconds) * time.Second).String()
+		data.CatchUp = CatchUpLast
+	}
+	warnings = append(warnings, unknownFields("spec", spec.Other)...)
+	warnings = append(warnings, unknownFields("spec.jobTemplate", spec.JobTemplate.Other)...)
+	job := spec.JobTemplate.Spec
+	if job.ActiveDeadlineSeconds != nil {
+		warnings = append(warnings, "spec.jobTemplate.spec.activeDeadlineSeconds cannot be translated")
+	}
+	warnings = append(warnings, unknownFields("spec.jobTemplate.spec", job.Other)...)
+	warnings = append(warnings, unknownFields("spec.jobTemplate.spec.template", job.Template.Other)...)
+	pod := job.Template.Spec
+	warnings = append(warnings, unknownFields("spec.jobTemplate.spec.template.spec", pod.Other)...)
+	if len(pod.Containers) == 0 {
+		return data, warnings, fmt.Errorf("the job does not have containers")
+	}
+	if len(pod.Containers) > 1 {
+		warnings = append(warnings, "only the first container was translated")
+	}
+	c := pod.Containers[0]
+	data.Image = c.Image
+	data.Command = shellJoin(c.Args)
+	if len(c.Command) > 0 {
+		data.Args = append(data.Args, fmt.Sprintf("--entrypoint %s", shellQuote(shellJoin(c.Command))))
+	}
+	if len(c.WorkingDir) > 0 {
+		data.Args = append(data.Args, fmt.Sprintf("--workdir %s", shellQuote(c.WorkingDir)))
+	}
+	for _, env := range c.Env {
+		if env.ValueFrom != nil {
+			warnings = append(warnings, fmt.Sprintf("env %s uses valueFrom and cannot be translated", env.Name))
+			continue
+		}
+		data.Args = append(data.Args, fmt.Sprintf("--env %s", shellQuote(env.Name+"="+env.Value)))
+	}
+	resources := []struct {
+		values map[string]string
+		flag   string
+	}{{c.Resources.Limits, "--limit"}, {c.Resources.Requests, "--reserve"}}
+	for _, r := range resources {
+		for _, name := range sortedKeys(r.values) {
+			value, err := fromQuantity(name, r.values[name])
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			data.Args = append(data.Args, fmt.Sprintf("%s-%s %s", r.flag, name, value))
+		}
+	}
+	warnings = append(warnings, unknownFields(fmt.Sprintf("container %s", c.Name), c.Other)...)
+	switch pod.RestartPolicy {
+	case "OnFailure":
+		data.Args = append(data.Args, "--restart-condition on-failure")
+		if job.BackoffLimit != nil {
+			data.Args = append(data.Args, fmt.Sprintf("--restart-max-attempts %d", *job.BackoffLimit))
+		}
+	case "", "Never":
+		data.Args = append(data.Args, "--restart-condition none")
+	default:
+		warnings = append(warnings, fmt.Sprintf("restartPolicy %s cannot be translated", pod.RestartPolicy))
+	}
+	for _, name := range sortedKeys(manifest.Metadata.Labels) {
+		data.Args = append(data.Args, fmt.Sprintf("-l %s", shellQuote(name+"="+manifest.Metadata.Labels[name])))
+	}
+	return data, warnings, nil
+}
+
+// toKubernetesSchedule converts a schedule into the five-field format and the time zone used by CronJobs.
+func toKubernetesSchedule(schedule string) (string, string, error) {
+	timeZone, schedule, err := splitTimeZone(schedule)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if !k8sDescriptors[schedule] {
+			return "", "", fmt.Errorf("schedule %s cannot be translated", schedule)
+		}
+		return schedule, timeZone, nil
+	}
+	fields := strings.Fields(schedule)
+	switch {
+	case len(fields) == 5:
+		return schedule, timeZone, nil
+	case len(fields) == 6 && fields[0] == "0":
+		return strings.Join(fields[1:], " "), timeZone, nil
+	}
+	return "", "", fmt.Errorf("schedule %s cannot be translated", schedule)
+}
+
+// splitTimeZone returns the time zone set through the `TZ=` or `CRON_TZ=` prefix and the rest of the schedule.
+func splitTimeZone(schedule string) (string, string, error) {
+	if !strings.HasPrefix(schedule, "TZ=") && !strings.HasPrefix(schedule, "CRON_TZ=") {
+		return "", schedule, nil
+	}
+	i := strings.Index(schedule, " ")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid schedule %s", schedule)
+	}
+	return schedule[strings.Index(schedule, "=")+1 : i], strings.TrimSpace(schedule[i:]), nil
+}
+
+// jobArguments returns the `docker service create` flags, the image and the command of a job.
+// Jobs read from services store the whole `docker service create` command, which is split back into its parts.
+func jobArguments(data JobData) ([]serviceFlag, string, []string, error) {
+	var words []string
+	var err error
+	image := data.Image
+	command := []string{}
+	prefix := "docker service create "
+	if strings.HasPrefix(data.Command, prefix) {
+		if words, err = splitShellWords(strings.TrimPrefix(data.Command, prefix)); err != nil {
+			return nil, "", nil, err
+		}
+	} else {
+		if words, err = splitShellWords(strings.Join(data.Args, " ")); err != nil {
+			return nil, "", nil, err
+		}
+		if command, err = splitShellWords(data.Command); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	flags := []serviceFlag{}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, "-") {
+			if strings.HasPrefix(data.Command, prefix) {
+				image = word
+				command = words[i+1:]
+			}
+			break
+		}
+		flag := serviceFlag{Name: word}
+		if j := strings.Index(word, "="); j > 0 {
+			flag = serviceFlag{Name: word[:j], Value: word[j+1:]}
+		} else if !serviceBoolFlags[word] && i+1 < len(words) {
+			i++
+			flag.Value = words[i]
+		}
+		flags = append(flags, flag)
+	}
+	return flags, image, command, nil
+}
+
+func setResource(resources *resourceRequirements, limit bool, name, value string) {
+	if limit {
+		if resources.Limits == nil {
+			resources.Limits = map[string]string{}
+		}
+		resources.Limits[name] = value
+	} else {
+		if resources.Requests == nil {
+			resources.Requests = map[string]string{}
+		}
+		resources.Requests[name] = value
+	}
+}
+
+func formatCPU(cpu float64) string {
+	if cpu == float64(int64(cpu)) {
+		return strconv.FormatInt(int64(cpu), 10)
+	}
+	return fmt.Sprintf("%dm", int64(cpu*1000+0.5))
+}
+
+func formatMemory(memory int64) string {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"Ti", 1 << 40}, {"Gi", 1 << 30}, {"Mi", 1 << 20}, {"Ki", 1 << 10}}
+	for _, unit := range units {
+		if memory >= unit.size && memory%unit.size == 0 {
+			return fmt.Sprintf("%d%s", memory/unit.size, unit.suffix)
+		}
+	}
+	return strconv.FormatInt(memory, 10)
+}
+
+// fromQuantity converts Kubernetes cpu and memory quantities into values accepted by `docker service create`.
+func fromQuantity(name, value string) (string, error) {
+	switch name {
+	case "cpu":
+		if strings.HasSuffix(value, "m") {
+			millis, err := strconv.ParseFloat(strings.TrimSuffix(value, "m"), 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid cpu value %s", value)
+			}
+			return strconv.FormatFloat(millis/1000, 'f', -1, 64), nil
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("invalid cpu value %s", value)
+		}
+		return value, nil
+	case "memory":
+		matches := k8sQuantityRegexp.FindStringSubmatch(value)
+		if matches == nil {
+			return "", fmt.Errorf("invalid memory value %s", value)
+		}
+		number, _ := strconv.ParseFloat(matches[1], 64)
+		multiplier := map[string]float64{
+			"": 1, "Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40,
+			"k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12,
+		}
+		return formatDockerMemory(int64(number * multiplier[matches[2]])), nil
+	}
+	return "", fmt.Errorf("resource %s cannot be translated", name)
+}
+
+func formatDockerMemory(memory int64) string {
+	value := formatMemory(memory)
+	if strings.HasSuffix(value, "i") {
+		return strings.TrimSuffix(value, "i")
+	}
+	return value
+}
+
+func unknownFields(path string, fields map[string]interface{}) []string {
+	warnings := []string{}
+	for _, name := range sortedKeys(fields) {
+		warnings = append(warnings, fmt.Sprintf("%s.%s cannot be translated", path, name))
+	}
+	return warnings
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch v := m.(type) {
+	case map[string]string:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]interface{}:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitShellWords splits a command into words the same way sh does it for quoted and escaped characters.
+func splitShellWords(value string) ([]string, error) {
+	words := []string{}
+	current := bytes.Buffer{}
+	inWord := false
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '\'':
+			end := strings.IndexByte(value[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in %s", value)
+			}
+			current.WriteString(value[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case ch == '"':
+			i++
+			for ; i < len(value) && value[i] != '"'; i++ {
+				if value[i] == '\\' && i+1 < len(value) && strings.IndexByte(`"\$`+"`", value[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(value[i])
+			}
+			if i >= len(value) {
+				return nil, fmt.Errorf("unterminated quote in %s", value)
+			}
+			inWord = true
+		case ch == '\\' && i+1 < len(value):
+			i++
+			current.WriteByte(value[i])
+			inWord = true
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteByte(ch)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// shellJoin is the reverse of splitShellWords.
+func shellJoin(words []string) string {
+	quoted := []string{}
+	for _, word := range words {
+		if shellSafeRegexp.MatchString(word) {
+			quoted = append(quoted, word)
+		} else {
+			quoted = append(quoted, shellQuote(word))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,269 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KubernetesTestSuite struct {
+	suite.Suite
+}
+
+func TestKubernetesUnitTestSuite(t *testing.T) {
+	s := new(KubernetesTestSuite)
+	suite.Run(t, s)
+}
+
+// ExportCronJobs
+
+func (s *KubernetesTestSuite) Test_ExportCronJobs_ConvertsJobs() {
+	jobs := []JobData{{
+		Name:     "backup",
+		Image:    "acme/backup:1.0",
+		Command:  `backup.sh "all databases"`,
+		Schedule: "TZ=Europe/Berlin 0 30 2 * * *",
+		Args: []string{
+			"--env 'DB_HOST=db'",
+			"--limit-cpu 0.5",
+			"--limit-memory 512M",
+			"--reserve-memory=1G",
+			"--restart-condition on-failure",
+			"--restart-max-attempts 3",
+			"-l team=ops",
+		},
+	}}
+	expected := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  labels:
+    team: ops
+spec:
+  schedule: 30 2 * * *
+  timeZone: Europe/Berlin
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      backoffLimit: 3
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: backup
+            image: acme/backup:1.0
+            args:
+            - backup.sh
+            - all databases
+            env:
+            - name: DB_HOST
+              value: db
+            resources:
+              limits:
+                cpu: 500m
+                memory: 512Mi
+              requests:
+                memory: 1Gi
+`
+
+	actual, warnings := ExportCronJobs(jobs)
+
+	s.Equal(expected, string(actual))
+	s.Empty(warnings)
+}
+
+func (s *KubernetesTestSuite) Test_ExportCronJobs_SplitsServiceCommand() {
+	jobs := []JobData{{
+		Name:     "my-job",
+		Image:    "alpine:latest@sha256:1234",
+		Command:  `docker service create --network my-net --env A=B --read-only --restart-condition none alpine echo "Hello World!"`,
+		Schedule: "@daily",
+	}}
+
+	actual, warnings := ExportCronJobs(jobs)
+
+	s.Contains(string(actual), "image: alpine\n")
+	s.Contains(string(actual), "args:\n            - echo\n            - Hello World!\n")
+	s.Contains(string(actual), "- name: A\n              value: B\n")
+	s.Equal([]string{
+		"my-job: argument --network cannot be translated",
+		"my-job: argument --read-only cannot be translated",
+	}, warnings)
+}
+
+func (s *KubernetesTestSuite) Test_ExportCronJobs_SkipsJobs_WhenScheduleCannotBeTranslated() {
+	jobs := []JobData{
+		{Name: "every", Image: "alpine", Schedule: "@every 15s"},
+		{Name: "seconds", Image: "alpine", Schedule: "*/10 * * * * *"},
+		{Name: "daily", Image: "alpine", Schedule: "@daily"},
+	}
+
+	actual, warnings := ExportCronJobs(jobs)
+
+	s.Contains(string(actual), "name: daily")
+	s.NotContains(string(actual), "---")
+	s.Equal([]string{
+		"every: job was skipped: schedule @every 15s cannot be translated",
+		"seconds: job was skipped: schedule */10 * * * * * cannot be translated",
+	}, warnings)
+}
+
+func (s *KubernetesTestSuite) Test_ExportCronJobs_ConvertsStartingDeadline() {
+	jobs := []JobData{{
+		Name:             "my-job",
+		Image:            "alpine",
+		Schedule:         "@daily",
+		StartingDeadline: "5m",
+		CatchUp:          CatchUpAll,
+	}}
+
+	actual, warnings := ExportCronJobs(jobs)
+
+	s.Contains(string(actual), "startingDeadlineSeconds: 300\n")
+	s.Equal([]string{"my-job: catchUp all cannot be translated; Kubernetes starts only the latest missed run"}, warnings)
+}
+
+// ParseCronJobs
+
+func (s *KubernetesTestSuite) Test_ParseCronJobs_ConvertsManifests() {
+	content := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: ops
+  labels:
+    team: ops
+spec:
+  schedule: "30 2 * * *"
+  timeZone: Europe/Berlin
+  jobTemplate:
+    spec:
+      backoffLimit: 3
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: backup
+            image: acme/backup:1.0
+            command: ["/bin/sh", "-c"]
+            args: ["backup.sh 'all databases'"]
+            workingDir: /data
+            env:
+            - name: DB_HOST
+              value: db
+            resources:
+              limits:
+                cpu: 500m
+                memory: 512Mi
+              requests:
+                cpu: 1
+                memory: 1G
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+	expected := JobData{
+		Name:     "backup",
+		Image:    "acme/backup:1.0",
+		Command:  `'backup.sh '\''all databases'\'''`,
+		Schedule: "TZ=Europe/Berlin 0 30 2 * * *",
+		Args: []string{
+			`--entrypoint '/bin/sh -c'`,
+			"--workdir '/data'",
+			"--env 'DB_HOST=db'",
+			"--limit-cpu 0.5",
+			"--limit-memory 512M",
+			"--reserve-cpu 1",
+			"--reserve-memory 1000000000",
+			"--restart-condition on-failure",
+			"--restart-max-attempts 3",
+			"-l 'team=ops'",
+		},
+	}
+
+	actual, err := ParseCronJobs([]byte(content))
+
+	s.NoError(err)
+	s.Equal([]JobData{expected}, actual.Jobs)
+	s.Equal([]string{"document 2: kind ConfigMap is not supported and was skipped"}, actual.Warnings)
+}
+
+func (s *KubernetesTestSuite) Test_ParseCronJobs_ReportsFieldsThatCannotBeTranslated() {
+	content := `kind: CronJob
+metadata:
+  name: my-job
+spec:
+  schedule: "CRON_TZ=UTC @hourly"
+  concurrencyPolicy: Replace
+  startingDeadlineSeconds: 60
+  suspend: true
+  jobTemplate:
+    spec:
+      activeDeadlineSeconds: 600
+      template:
+        spec:
+          nodeSelector:
+            disk: ssd
+          containers:
+          - name: main
+            image: alpine
+            ports:
+            - containerPort: 80
+            env:
+            - name: PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: db
+                  key: password
+`
+
+	actual, err := ParseCronJobs([]byte(content))
+
+	s.NoError(err)
+	s.Equal("TZ=UTC @hourly", actual.Jobs[0].Schedule)
+	s.Equal([]string{"--restart-condition none"}, actual.Jobs[0].Args)
+	s.Equal("1m0s", actual.Jobs[0].StartingDeadline)
+	s.Equal(CatchUpLast, actual.Jobs[0].CatchUp)
+	s.Equal([]string{
+		"my-job: concurrencyPolicy Replace cannot be translated; executions never overlap",
+		"my-job: spec.suspend cannot be translated",
+		"my-job: spec.jobTemplate.spec.activeDeadlineSeconds cannot be translated",
+		"my-job: spec.jobTemplate.spec.template.spec.nodeSelector cannot be translated",
+		"my-job: env PASSWORD uses valueFrom and cannot be translated",
+		"my-job: container main.ports cannot be translated",
+	}, actual.Warnings)
+}
+
+func (s *KubernetesTestSuite) Test_ParseCronJobs_ReturnsError_WhenScheduleIsInvalid() {
+	_, err := ParseCronJobs([]byte("kind: CronJob\nmetadata:\n  name: my-job\nspec:\n  schedule: \"* *\"\n"))
+
+	s.Error(err)
+}
+
+func (s *KubernetesTestSuite) Test_ParseCronJobs_ReturnsError_WhenThereAreNoContainers() {
+	_, err := ParseCronJobs([]byte("kind: CronJob\nmetadata:\n  name: my-job\nspec:\n  schedule: \"@daily\"\n"))
+
+	s.Error(err)
+}
+
+func (s *KubernetesTestSuite) Test_ExportCronJobs_ProducesManifestsThatCanBeImported() {
+	jobs := []JobData{{
+		Name:     "my-job",
+		Image:    "alpine",
+		Command:  `echo "Hello World!"`,
+		Schedule: "0 0 * * * *",
+		Args:     []string{"--env 'A=B'", "--restart-condition none"},
+	}}
+
+	manifests, _ := ExportCronJobs(jobs)
+	actual, err := ParseCronJobs(manifests)
+
+	s.NoError(err)
+	s.Equal("my-job", actual.Jobs[0].Name)
+	s.Equal("0 0 * * * *", actual.Jobs[0].Schedule)
+	s.Equal(`echo 'Hello World!'`, actual.Jobs[0].Command)
+	s.Equal([]string{"--env 'A=B'", "--restart-condition none"}, actual.Jobs[0].Args)
+	s.Empty(actual.Warnings)
+}
@@ -0,0 +1,179 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	// TypeService jobs run the image of the job
+	TypeService = "service"
+	// TypeHTTP jobs send an HTTP request from Docker Flow Cron
+	TypeHTTP = "http"
+	// TypeExec jobs run a command in the containers of a running service
+	TypeExec = "exec"
+)
+
+// runsImage returns true if runs of the job start containers from the image of the job.
+func runsImage(data JobData) bool {
+	return data.Type == "" || data.Type == TypeService
+}
+
+// localJobRecord is a local job as it is stored in the local jobs file.
+// Local jobs do not have service labels, so the file keeps their suspended and completed states as well.
+// Records with StateOnly set keep only the states of jobs stored as job mode services.
+type localJobRecord struct {
+	Job         JobData    `json:"job"`
+	StateOnly   bool       `json:"stateOnly,omitempty"`
+	Suspended   string     `json:"suspended,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// isLocalJob returns true if the job is not stored as a service.
+// Local jobs are kept in memory and, when LoadLocalJobs was invoked with a path, in the local jobs file.
+func (c *Cron) isLocalJob(data JobData) bool {
+	return !runsImage(data) || c.executorName(data) == ExecutorContainer
+}
+
+// storesStateLocally returns true if the suspended and completed states of the job are kept in the local jobs file
+// instead of the labels of its service. Each update of a job mode service starts a new iteration of the job,
+// so labelling such services would start runs.
+func (c *Cron) storesStateLocally(data JobData) bool {
+	return c.isLocalJob(data) || c.jobMode
+}
+
+// LoadLocalJobs restores the local jobs stored in the file and keeps the file updated afterwards.
+// The restored jobs are scheduled by RescheduleJobs. Nothing is stored when the path is empty.
+func (c *Cron) LoadLocalJobs(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.localJobsFile = path
+	if len(path) == 0 {
+		return nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	records := []localJobRecord{}
+	if err := json.Unmarshal(content, &records); err != nil {
+		return fmt.Errorf("could not parse local jobs %s: %s", path, err.Error())
+	}
+	if c.localJobs == nil {
+		c.localJobs = map[string]JobData{}
+	}
+	if c.suspended == nil {
+		c.suspended = map[string]string{}
+	}
+	if c.completed == nil {
+		c.completed = map[string]time.Time{}
+	}
+	for _, record := range records {
+		if !record.StateOnly {
+			c.localJobs[record.Job.Name] = record.Job
+		}
+		if len(record.Suspended) > 0 {
+			c.suspended[record.Job.Name] = record.Suspended
+		}
+		if record.CompletedAt != nil {
+			c.completed[record.Job.Name] = *record.CompletedAt
+		}
+	}
+	return nil
+}
+
+func (c *Cron) setLocalJob(data JobData) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.localJobs == nil {
+		c.localJobs = map[string]JobData{}
+	}
+	c.localJobs[data.Name] = data
+	return c.writeLocalJobs()
+}
+
+// saveLocalJobs writes the local jobs and their states to the local jobs file.
+func (c *Cron) saveLocalJobs() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeLocalJobs()
+}
+
+// writeLocalJobs is saveLocalJobs for callers that hold the lock.
+func (c *Cron) writeLocalJobs() error {
+	if len(c.localJobsFile) == 0 {
+		return nil
+	}
+	records := []localJobRecord{}
+	for name, job := range c.localJobs {
+		record := localJobRecord{Job: job, Suspended: c.suspended[name]}
+		if completedAt, ok := c.completed[name]; ok {
+			record.CompletedAt = &completedAt
+		}
+		records = append(records, record)
+	}
+	if c.jobMode {
+		records = append(records, c.stateRecords()...)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Job.Name < records[j].Job.Name })
+	js, _ := json.MarshalIndent(records, "", "  ")
+	return ioutil.WriteFile(c.localJobsFile, js, 0600)
+}
+
+// stateRecords returns the records of the states of jobs that are not local jobs. The caller must hold the lock.
+func (c *Cron) stateRecords() []localJobRecord {
+	states := map[string]*localJobRecord{}
+	state := func(name string) *localJobRecord {
+		if _, ok := states[name]; !ok {
+			states[name] = &localJobRecord{Job: JobData{Name: name}, StateOnly: true}
+		}
+		return states[name]
+	}
+	for name, reason := range c.suspended {
+		if _, ok := c.localJobs[name]; !ok {
+			state(name).Suspended = reason
+		}
+	}
+	for name, completedAt := range c.completed {
+		if _, ok := c.localJobs[name]; !ok {
+			completedAt := completedAt
+			state(name).CompletedAt = &completedAt
+		}
+	}
+	records := []localJobRecord{}
+	for _, record := range states {
+		records = append(records, *record)
+	}
+	return records
+}
+
+func (c *Cron) getLocalJobs() map[string]JobData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	jobs := map[string]JobData{}
+	for name, job := range c.localJobs {
+		jobs[name] = job
+	}
+	return jobs
+}
+
+// localJobService describes a local job the way Plan sees jobs stored as services.
+func localJobService(data JobData) swarm.Service {
+	service := swarm.Service{}
+	service.Spec.Annotations.Labels = map[string]string{
+		"com.df.cron.name":       data.Name,
+		"com.df.cron.schedule":   data.Schedule,
+		"com.df.cron.managed-by": data.ManagedBy,
+		"com.df.cron.hash":       jobHash(data),
+	}
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: data.Image}
+	return service
+}
@@ -0,0 +1,185 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// ReasonMaintenance is the reason recorded for runs skipped while the scheduler was paused.
+const ReasonMaintenance = "maintenance"
+
+// Maintenance describes whether the scheduler is paused.
+type Maintenance struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"pausedAt,omitempty"`
+	PausedBy string     `json:"pausedBy,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+// LoadMaintenance restores the maintenance state stored in the file and keeps the file updated afterwards.
+// When the scheduler was paused, it stays paused. Nothing is stored when the path is empty.
+func (c *Cron) LoadMaintenance(path string) error {
+	c.mu.Lock()
+	c.maintenanceFile = path
+	c.mu.Unlock()
+	if len(path) == 0 {
+		return nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	maintenance := Maintenance{}
+	if err := json.Unmarshal(content, &maintenance); err != nil {
+		return fmt.Errorf("could not parse maintenance state %s: %s", path, err.Error())
+	}
+	if !maintenance.Paused {
+		return nil
+	}
+	fmt.Println("Docker Flow Cron is paused for maintenance")
+	c.stopDispatcher()
+	c.mu.Lock()
+	c.maintenance = maintenance
+	c.mu.Unlock()
+	c.scheduleResume(maintenance.Until)
+	return nil
+}
+
+// Pause stops running jobs until Resume is invoked or, if set, the until time passes.
+// Pausing a paused scheduler changes only the time it resumes at.
+func (c *Cron) Pause(until *time.Time, by string) error {
+	now := timeNow().UTC()
+	if until != nil && !until.After(now) {
+		return fmt.Errorf("until must be in the future")
+	}
+	c.mu.Lock()
+	maintenance := c.maintenance
+	c.mu.Unlock()
+	if !maintenance.Paused {
+		maintenance = Maintenance{Paused: true, PausedAt: &now, PausedBy: by}
+	}
+	maintenance.Until = until
+	if err := c.saveMaintenance(maintenance); err != nil {
+		return err
+	}
+	fmt.Println("Pausing Docker Flow Cron for maintenance")
+	c.stopDispatcher()
+	c.mu.Lock()
+	c.maintenance = maintenance
+	c.mu.Unlock()
+	c.scheduleResume(until)
+	return nil
+}
+
+// Resume starts running jobs again. The times the jobs should have run at while the scheduler was paused are recorded as skipped.
+func (c *Cron) Resume() error {
+	c.mu.Lock()
+	maintenance := c.maintenance
+	c.mu.Unlock()
+	if !maintenance.Paused {
+		return fmt.Errorf("Docker Flow Cron is not paused")
+	}
+	if err := c.saveMaintenance(Maintenance{}); err != nil {
+		return err
+	}
+	fmt.Println("Resuming Docker Flow Cron")
+	c.mu.Lock()
+	c.maintenance = Maintenance{}
+	if c.maintenanceTimer != nil {
+		c.maintenanceTimer.Stop()
+		c.maintenanceTimer = nil
+	}
+	c.mu.Unlock()
+	if maintenance.PausedAt != nil {
+		c.skipPausedRuns(*maintenance.PausedAt, timeNow())
+	}
+	c.startDispatcher()
+	return nil
+}
+
+// GetMaintenance returns whether the scheduler is paused.
+func (c *Cron) GetMaintenance() Maintenance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maintenance
+}
+
+func (c *Cron) startDispatcher() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		c.Cron.Start()
+		c.running = true
+	}
+}
+
+func (c *Cron) stopDispatcher() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		c.Cron.Stop()
+		c.running = false
+	}
+}
+
+// scheduleResume resumes the scheduler when the time passes.
+func (c *Cron) scheduleResume(until *time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maintenanceTimer != nil {
+		c.maintenanceTimer.Stop()
+		c.maintenanceTimer = nil
+	}
+	if until == nil {
+		return
+	}
+	at := *until
+	delay := at.Sub(timeNow())
+	if delay < 0 {
+		delay = 0
+	}
+	c.maintenanceTimer = timeAfterFunc(delay, func() {
+		if current := c.GetMaintenance(); current.Paused && current.Until != nil && current.Until.Equal(at) {
+			if err := c.Resume(); err != nil {
+				fmt.Println("Could not resume Docker Flow Cron:", err.Error())
+			}
+		}
+	})
+}
+
+// skipPausedRuns records the runs of scheduled jobs that fell into the pause as skipped.
+// Only the latest runs that fit into the history of each job are recorded.
+func (c *Cron) skipPausedRuns(from, to time.Time) {
+	for name, id := range c.entries() {
+		schedule := c.Cron.Entry(id).Schedule
+		if schedule == nil {
+			continue
+		}
+		times := []time.Time{}
+		for t, i := schedule.Next(from), 0; !t.IsZero() && t.Before(to) && i < catchUpMaxTicks; t, i = schedule.Next(t), i+1 {
+			times = append(times, t)
+			if len(times) > historyMemorySize {
+				times = times[1:]
+			}
+		}
+		for _, t := range times {
+			c.skipRun(name, ReasonMaintenance, t)
+		}
+	}
+}
+
+func (c *Cron) saveMaintenance(maintenance Maintenance) error {
+	c.mu.Lock()
+	path := c.maintenanceFile
+	c.mu.Unlock()
+	if len(path) == 0 {
+		return nil
+	}
+	js, _ := json.Marshal(maintenance)
+	return ioutil.WriteFile(path, js, 0600)
+}
@@ -0,0 +1,148 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type MaintenanceTestSuite struct {
+	suite.Suite
+	now time.Time
+	dir string
+}
+
+func TestMaintenanceUnitTestSuite(t *testing.T) {
+	s := new(MaintenanceTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *MaintenanceTestSuite) SetupTest() {
+	s.now = time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		return s.now
+	}
+	s.dir, _ = ioutil.TempDir("", "maintenance")
+}
+
+func (s *MaintenanceTestSuite) TearDownTest() {
+	timeNow = time.Now
+	os.RemoveAll(s.dir)
+}
+
+// Pause
+
+func (s *MaintenanceTestSuite) Test_Pause_StopsDispatcherAndStoresState() {
+	path := filepath.Join(s.dir, "maintenance.json")
+	c := s.newCron()
+	c.LoadMaintenance(path)
+
+	err := c.Pause(nil, "ops")
+	restored := s.newCron()
+	restored.LoadMaintenance(path)
+
+	s.NoError(err)
+	s.False(c.running)
+	s.Equal(Maintenance{Paused: true, PausedAt: &s.now, PausedBy: "ops"}, c.GetMaintenance())
+	s.False(restored.running)
+	s.True(restored.GetMaintenance().Paused)
+	s.True(s.now.Equal(*restored.GetMaintenance().PausedAt))
+}
+
+func (s *MaintenanceTestSuite) Test_Pause_SchedulesResume() {
+	timeAfterFuncOrig := timeAfterFunc
+	defer func() { timeAfterFunc = timeAfterFuncOrig }()
+	actualDelay := time.Duration(0)
+	var resume func()
+	timeAfterFunc = func(d time.Duration, f func()) *time.Timer {
+		actualDelay = d
+		resume = f
+		return nil
+	}
+	until := s.now.Add(2 * time.Hour)
+	c := s.newCron()
+
+	err := c.Pause(&until, "ops")
+	s.now = until
+	resume()
+
+	s.NoError(err)
+	s.Equal(2*time.Hour, actualDelay)
+	s.False(c.GetMaintenance().Paused)
+	s.True(c.running)
+	c.Stop()
+}
+
+func (s *MaintenanceTestSuite) Test_Pause_ReturnsError_WhenUntilPassed() {
+	until := s.now.Add(-time.Minute)
+	c := s.newCron()
+
+	s.Error(c.Pause(&until, "ops"))
+	s.True(c.running)
+	c.Stop()
+}
+
+// Resume
+
+func (s *MaintenanceTestSuite) Test_Resume_RecordsSkippedRuns() {
+	history, _ := NewHistorian("")
+	c := s.newCron()
+	c.History = history
+	c.Jobs["my-job"], _ = c.Cron.AddFunc("0 0 * * * *", func() {})
+	c.Pause(nil, "ops")
+	s.now = s.now.Add(150 * time.Minute)
+
+	err := c.Resume()
+	runs, _ := history.Runs("my-job", 0)
+
+	s.NoError(err)
+	s.True(c.running)
+	s.Len(runs, 2)
+	for _, run := range runs {
+		s.Equal(RunSkipped, run.Status)
+		s.Equal(ReasonMaintenance, run.Message)
+	}
+	s.True(time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC).Equal(*runs[0].ScheduledAt))
+	s.True(time.Date(2017, 5, 5, 11, 0, 0, 0, time.UTC).Equal(*runs[1].ScheduledAt))
+	c.Stop()
+}
+
+// scheduleOnce
+
+func (s *MaintenanceTestSuite) Test_AddJob_SkipsMissedRunAt_WhenPaused() {
+	history, _ := NewHistorian("")
+	c := s.newCron()
+	c.History = history
+	c.Pause(nil, "ops")
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", RunAt: "2017-05-05T09:00:00Z", Created: true})
+	runs, _ := history.Runs("my-job", 0)
+
+	s.NoError(err)
+	s.Len(runs, 1)
+	s.Equal(RunSkipped, runs[0].Status)
+	s.Equal(ReasonMaintenance, runs[0].Message)
+	s.True(time.Date(2017, 5, 5, 9, 0, 0, 0, time.UTC).Equal(*runs[0].ScheduledAt))
+	s.NotContains(c.Jobs, "my-job")
+}
+
+func (s *MaintenanceTestSuite) Test_Resume_ReturnsError_WhenNotPaused() {
+	c := s.newCron()
+
+	s.Error(c.Resume())
+	c.Stop()
+}
+
+// Util
+
+func (s *MaintenanceTestSuite) newCron() *Cron {
+	c := &Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.Cron.Start()
+	c.running = true
+	return c
+}
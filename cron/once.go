@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+const (
+	JobScheduled = "Scheduled"
+	JobCompleted = "Completed"
+)
+
+var rCronScheduleFunc = func(c *rcron.Cron, schedule rcron.Schedule, cmd func()) rcron.EntryID {
+	return c.Schedule(schedule, rcron.FuncJob(cmd))
+}
+
+var timeAfterFunc = time.AfterFunc
+
+// onceSchedule fires a single time.
+type onceSchedule struct {
+	at time.Time
+}
+
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// validateRunAt checks the fields of a job that runs once.
+func validateRunAt(data JobData) error {
+	if len(data.RunAt) == 0 {
+		return fmt.Errorf("ttl can only be used together with runAt")
+	}
+	if len(data.Schedule) > 0 {
+		return fmt.Errorf("schedule and runAt cannot be used together")
+	}
+	if _, err := time.Parse(time.RFC3339, data.RunAt); err != nil {
+		return fmt.Errorf("runAt must be an RFC3339 timestamp: %s", err.Error())
+	}
+	if len(data.TTL) > 0 {
+		if _, err := time.ParseDuration(data.TTL); err != nil {
+			return fmt.Errorf("invalid ttl %s: %s", data.TTL, err.Error())
+		}
+	}
+	return nil
+}
+
+// scheduleOnce runs the job at the time set through runAt or, if the time was missed, straight away.
+// A missed run is recorded as skipped while the scheduler is paused, the same way as runs that fall into the pause.
+// Jobs that already ran are not scheduled again. Their services are removed once the TTL expires.
+func (c *Cron) scheduleOnce(data JobData, serviceName string) error {
+	if completedAt, ok := c.completedAt(data.Name); ok {
+		c.scheduleRemoval(data, completedAt)
+		return nil
+	}
+	runAt, _ := time.Parse(time.RFC3339, data.RunAt)
+	done := func(run Run) {
+		c.completeOnce(data, serviceName, *run.FinishedAt)
+	}
+	if !timeNow().Before(runAt) && c.GetMaintenance().Paused {
+		c.skipRun(data.Name, ReasonMaintenance, runAt)
+		return nil
+	}
+	if !timeNow().Before(runAt) {
+		fmt.Println("Running", data.Name, "since its time was missed")
+		go c.runJob(data, serviceName, TriggerSchedule, done)
+		return nil
+	}
+	c.setEntry(data.Name, rCronScheduleFunc(c.Cron, onceSchedule{at: runAt}, func() {
+		c.runJob(data, serviceName, TriggerSchedule, done)
+	}))
+	return nil
+}
+
+// completeOnce marks the job as completed through the `com.df.cron.completed` label so that it does not run again after a restart.
+// Job mode services are not labelled since the update would run the job again.
+func (c *Cron) completeOnce(data JobData, serviceName string, completedAt time.Time) {
+	c.setCompleted(data.Name, completedAt)
+	if id, ok := c.entry(data.Name); ok {
+		c.Cron.Remove(id)
+	}
+	if c.storesStateLocally(data) {
+		if err := c.saveLocalJobs(); err != nil {
+			fmt.Println("Could not save local jobs:", err.Error())
+		}
+		c.scheduleRemoval(data, completedAt)
+		return
+	}
+	update := fmt.Sprintf(
+		`docker service update --label-add "com.df.cron.completed=%s" %s`,
+		completedAt.UTC().Format(time.RFC3339),
+		serviceName,
+	)
+	fmt.Println(update)
+	if _, err := updateServiceFunc(update); err != nil {
+		fmt.Println("Could not execute command: ", update)
+	}
+	c.scheduleRemoval(data, completedAt)
+}
+
+// scheduleRemoval removes the job when the TTL after its completion expires.
+func (c *Cron) scheduleRemoval(data JobData, completedAt time.Time) {
+	if len(data.TTL) == 0 {
+		return
+	}
+	ttl, _ := time.ParseDuration(data.TTL)
+	delay := completedAt.Add(ttl).Sub(timeNow())
+	if delay < 0 {
+		delay = 0
+	}
+	timeAfterFunc(delay, func() {
+		fmt.Println("Removing job", data.Name, "since its TTL expired")
+		if err := c.RemoveJob(data.Name); err != nil {
+			fmt.Println("Could not remove job", data.Name, err.Error())
+		}
+	})
+}
+
+func (c *Cron) setCompleted(jobName string, completedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.completed == nil {
+		c.completed = map[string]time.Time{}
+	}
+	c.completed[jobName] = completedAt
+}
+
+func (c *Cron) completedAt(jobName string) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	completedAt, ok := c.completed[jobName]
+	return completedAt, ok
+}
@@ -0,0 +1,164 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type OnceTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestOnceUnitTestSuite(t *testing.T) {
+	s := new(OnceTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *OnceTestSuite) SetupTest() {
+	s.now = time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *OnceTestSuite) TearDownTest() {
+	timeNow = time.Now
+}
+
+// onceSchedule
+
+func (s *OnceTestSuite) Test_OnceSchedule_FiresOnce() {
+	schedule := onceSchedule{at: s.now.Add(time.Hour)}
+
+	s.Equal(s.now.Add(time.Hour), schedule.Next(s.now))
+	s.True(schedule.Next(s.now.Add(time.Hour)).IsZero())
+}
+
+// AddJob
+
+func (s *OnceTestSuite) Test_AddJob_SchedulesJobOnce() {
+	rCronScheduleFuncOrig := rCronScheduleFunc
+	defer func() { rCronScheduleFunc = rCronScheduleFuncOrig }()
+	var actualSchedule rcron.Schedule
+	rCronScheduleFunc = func(c *rcron.Cron, schedule rcron.Schedule, cmd func()) rcron.EntryID {
+		actualSchedule = schedule
+		return 7
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "migration", Image: "alpine", RunAt: "2017-05-06T03:00:00Z", Created: true})
+
+	s.NoError(err)
+	s.Equal(onceSchedule{at: time.Date(2017, 5, 6, 3, 0, 0, 0, time.UTC)}, actualSchedule)
+	s.Equal(rcron.EntryID(7), c.Jobs["migration"])
+	s.Equal(JobScheduled, c.GetState("migration").Status)
+}
+
+func (s *OnceTestSuite) Test_AddJob_DoesNotScheduleCompletedJob() {
+	rCronScheduleFuncOrig := rCronScheduleFunc
+	timeAfterFuncOrig := timeAfterFunc
+	defer func() {
+		rCronScheduleFunc = rCronScheduleFuncOrig
+		timeAfterFunc = timeAfterFuncOrig
+	}()
+	scheduled := false
+	rCronScheduleFunc = func(c *rcron.Cron, schedule rcron.Schedule, cmd func()) rcron.EntryID {
+		scheduled = true
+		return 1
+	}
+	actualDelay := time.Duration(-1)
+	timeAfterFunc = func(d time.Duration, f func()) *time.Timer {
+		actualDelay = d
+		return nil
+	}
+	completedAt := s.now.Add(-time.Hour)
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.setCompleted("migration", completedAt)
+
+	c.AddJob(JobData{Name: "migration", Image: "alpine", RunAt: "2017-05-05T09:00:00Z", TTL: "2h", Created: true})
+
+	s.False(scheduled)
+	s.Equal(time.Hour, actualDelay)
+	state := c.GetState("migration")
+	s.Equal(JobCompleted, state.Status)
+	s.Equal(completedAt, *state.CompletedAt)
+}
+
+func (s *OnceTestSuite) Test_AddJob_ReturnsError_WhenRunAtIsInvalid() {
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	jobs := []JobData{
+		{Name: "my-job", Image: "alpine", RunAt: "tomorrow"},
+		{Name: "my-job", Image: "alpine", RunAt: "2017-05-06T03:00:00Z", Schedule: "@daily"},
+		{Name: "my-job", Image: "alpine", RunAt: "2017-05-06T03:00:00Z", TTL: "forever"},
+		{Name: "my-job", Image: "alpine", Schedule: "@daily", TTL: "1h"},
+	}
+
+	for _, job := range jobs {
+		s.Error(c.AddJob(job))
+	}
+}
+
+// completeOnce
+
+func (s *OnceTestSuite) Test_CompleteOnce_DoesNotUpdateService_WhenJobModeIsSupported() {
+	updateServiceFuncOrig := updateServiceFunc
+	defer func() { updateServiceFunc = updateServiceFuncOrig }()
+	updates := []string{}
+	updateServiceFunc = func(cmd string) ([]byte, error) {
+		updates = append(updates, cmd)
+		return nil, nil
+	}
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, jobMode: true}
+	c.LoadLocalJobs(path)
+
+	c.completeOnce(JobData{Name: "migration", Image: "alpine", RunAt: "2017-05-05T09:00:00Z"}, "migration", s.now)
+
+	s.Empty(updates)
+	restored := Cron{jobMode: true}
+	s.NoError(restored.LoadLocalJobs(path))
+	completedAt, ok := restored.completedAt("migration")
+	s.True(ok)
+	s.True(s.now.Equal(completedAt))
+	s.Empty(restored.getLocalJobs())
+}
+
+// scheduleRemoval
+
+func (s *OnceTestSuite) Test_ScheduleRemoval_RemovesJobImmediately_WhenTTLExpired() {
+	timeAfterFuncOrig := timeAfterFunc
+	defer func() { timeAfterFunc = timeAfterFuncOrig }()
+	timeAfterFunc = func(d time.Duration, f func()) *time.Timer {
+		s.Equal(time.Duration(0), d)
+		f()
+		return nil
+	}
+	removed := ""
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{"migration": 1},
+		Service: ServicerMock{
+			RemoveServicesMock: func(jobName string) error {
+				removed = jobName
+				return nil
+			},
+		},
+	}
+	c.setCompleted("migration", s.now.Add(-time.Hour))
+
+	c.scheduleRemoval(JobData{Name: "migration", TTL: "30m"}, s.now.Add(-time.Hour))
+
+	s.Equal("migration", removed)
+	s.NotContains(c.Jobs, "migration")
+	_, completed := c.completedAt("migration")
+	s.False(completed)
+}
@@ -0,0 +1,75 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	// EnvTaskIndex holds the index of the task within a parallel run.
+	EnvTaskIndex = "CRON_TASK_INDEX"
+	// EnvCompletions holds the number of tasks that have to complete for a parallel run to succeed.
+	EnvCompletions = "CRON_COMPLETIONS"
+)
+
+func (c *Cron) validateParallelism(data JobData) error {
+	if data.Parallelism < 0 {
+		return fmt.Errorf("parallelism must not be negative")
+	}
+	if data.Completions < 0 {
+		return fmt.Errorf("completions must not be negative")
+	}
+	if data.Parallelism == 0 && data.Completions == 0 {
+		return nil
+	}
+	if data.MaxConcurrent > 0 || data.TotalCompletions > 0 {
+		return fmt.Errorf("parallelism and completions cannot be used together with maxConcurrent and totalCompletions")
+	}
+	if data.Mode == ModeGlobal {
+		return fmt.Errorf("parallelism and completions cannot be used with mode %s", ModeGlobal)
+	}
+	if data.ExecutionMode == ExecutionPerRun {
+		return fmt.Errorf("parallelism and completions cannot be used with the %s execution mode", ExecutionPerRun)
+	}
+	if parallelism, completions := jobConcurrency(data); !c.jobMode && completions > parallelism {
+		return fmt.Errorf("completions greater than parallelism require a Docker daemon with job mode services")
+	}
+	return nil
+}
+
+// parallelEnv returns the environment variables that tell each task of a parallel run its index and the number of completions.
+func parallelEnv(data JobData) []string {
+	if data.Parallelism == 0 && data.Completions == 0 {
+		return []string{}
+	}
+	_, completions := jobConcurrency(data)
+	return []string{
+		fmt.Sprintf("%s={{.Task.Slot}}", EnvTaskIndex),
+		fmt.Sprintf("%s=%d", EnvCompletions, completions),
+	}
+}
+
+// scaledConcurrency returns the number of replicas a run of a scaled service starts and how many of them have to complete.
+func scaledConcurrency(data JobData) (int, int) {
+	if data.Parallelism == 0 && data.Completions == 0 {
+		return 1, 1
+	}
+	replicas, completions := jobConcurrency(data)
+	if completions > replicas {
+		completions = replicas
+	}
+	return replicas, completions
+}
+
+// tasksCreatedAfter returns the tasks created after the given time.
+func tasksCreatedAfter(tasks []swarm.Task, after time.Time) []swarm.Task {
+	created := []swarm.Task{}
+	for _, task := range tasks {
+		if !task.CreatedAt.Before(after.Add(-time.Second)) {
+			created = append(created, task)
+		}
+	}
+	return created
+}
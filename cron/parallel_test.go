@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+)
+
+type ParallelTestSuite struct {
+	suite.Suite
+}
+
+func TestParallelUnitTestSuite(t *testing.T) {
+	s := new(ParallelTestSuite)
+	suite.Run(t, s)
+}
+
+// validateParallelism
+
+func (s *ParallelTestSuite) Test_ValidateParallelism_AcceptsValidFields() {
+	c := Cron{}
+
+	s.NoError(c.validateParallelism(JobData{}))
+	s.NoError(c.validateParallelism(JobData{Parallelism: 4}))
+	s.NoError(c.validateParallelism(JobData{Parallelism: 4, Completions: 2}))
+}
+
+func (s *ParallelTestSuite) Test_ValidateParallelism_ReturnsError_WhenFieldsAreInvalid() {
+	c := Cron{jobMode: true}
+
+	s.Error(c.validateParallelism(JobData{Parallelism: -1}))
+	s.Error(c.validateParallelism(JobData{Completions: -1}))
+	s.Error(c.validateParallelism(JobData{Parallelism: 2, MaxConcurrent: 2}))
+	s.Error(c.validateParallelism(JobData{Parallelism: 2, Mode: ModeGlobal}))
+	s.Error(c.validateParallelism(JobData{Parallelism: 2, ExecutionMode: ExecutionPerRun}))
+}
+
+func (s *ParallelTestSuite) Test_ValidateParallelism_RequiresJobMode_WhenCompletionsExceedParallelism() {
+	data := JobData{Parallelism: 2, Completions: 6}
+
+	s.Error((&Cron{}).validateParallelism(data))
+	s.NoError((&Cron{jobMode: true}).validateParallelism(data))
+}
+
+// parallelEnv
+
+func (s *ParallelTestSuite) Test_ParallelEnv_ReturnsIndexAndCompletions() {
+	s.Equal([]string{}, parallelEnv(JobData{}))
+	s.Equal(
+		[]string{"CRON_TASK_INDEX={{.Task.Slot}}", "CRON_COMPLETIONS=6"},
+		parallelEnv(JobData{Parallelism: 2, Completions: 6}),
+	)
+}
+
+// scaledConcurrency
+
+func (s *ParallelTestSuite) Test_ScaledConcurrency_LimitsCompletionsToReplicas() {
+	replicas, completions := scaledConcurrency(JobData{})
+	s.Equal(1, replicas)
+	s.Equal(1, completions)
+
+	replicas, completions = scaledConcurrency(JobData{Parallelism: 3})
+	s.Equal(3, replicas)
+	s.Equal(3, completions)
+
+	replicas, completions = scaledConcurrency(JobData{Parallelism: 2, Completions: 6})
+	s.Equal(2, replicas)
+	s.Equal(2, completions)
+}
+
+// watchRun
+
+func (s *ParallelTestSuite) Test_WatchRun_WaitsForAllCompletions() {
+	runWatchIntervalOrig := runWatchInterval
+	defer func() { runWatchInterval = runWatchIntervalOrig }()
+	runWatchInterval = time.Millisecond
+	started := time.Now().UTC()
+	calls := 0
+	history, _ := NewHistorian("")
+	c := Cron{
+		History: history,
+		Service: ServicerMock{
+			GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+				calls++
+				old := s.task(started.Add(-time.Hour), swarm.TaskStateFailed)
+				first := s.task(started, swarm.TaskStateComplete)
+				second := s.task(started, swarm.TaskStateRunning)
+				if calls > 1 {
+					second.Status.State = swarm.TaskStateComplete
+				}
+				return []swarm.Task{old, first, second}, nil
+			},
+		},
+	}
+
+	c.watchRun(Run{ID: "1", Job: "my-job", Status: RunRunning, StartedAt: started, completions: 2}, nil)
+
+	actual, _ := history.Runs("my-job", 0)
+	s.Equal(2, calls)
+	s.Equal(RunSucceeded, actual[0].Status)
+}
+
+// Util
+
+func (s *ParallelTestSuite) task(createdAt time.Time, state swarm.TaskState) swarm.Task {
+	task := swarm.Task{Status: swarm.TaskStatus{State: state, Timestamp: createdAt}}
+	task.CreatedAt = createdAt
+	return task
+}
@@ -0,0 +1,326 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Policy restricts the jobs that can be scheduled.
+// Allow lists are ignored when empty, except for AllowedExecServices since exec jobs can run anything in their target.
+// Deny lists take precedence over allow lists. Patterns can contain `*` which matches any sequence of characters.
+type Policy struct {
+	AllowedImages       []string `json:"allowedImages"`
+	DeniedImages        []string `json:"deniedImages"`
+	AllowedRegistries   []string `json:"allowedRegistries"`
+	DeniedRegistries    []string `json:"deniedRegistries"`
+	AllowedMountTypes   []string `json:"allowedMountTypes"`
+	AllowedHostPaths    []string `json:"allowedHostPaths"`
+	DeniedHostPaths     []string `json:"deniedHostPaths"`
+	AllowedNetworks     []string `json:"allowedNetworks"`
+	DeniedNetworks      []string `json:"deniedNetworks"`
+	AllowedCapabilities []string `json:"allowedCapabilities"`
+	DeniedCapabilities  []string `json:"deniedCapabilities"`
+	MaxCPU              float64  `json:"maxCpu"`
+	MaxMemory           string   `json:"maxMemory"`
+	RequiredLabels      []string `json:"requiredLabels"`
+	AllowedExecServices []string `json:"allowedExecServices"`
+	DeniedExecServices  []string `json:"deniedExecServices"`
+}
+
+// PolicyError is returned when a job violates a policy rule.
+type PolicyError struct {
+	Rule    string
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("policy rule %s violated: %s", e.Rule, e.Message)
+}
+
+// LoadPolicy reads a JSON policy file. It returns nil when the path is empty.
+func LoadPolicy(path string) (*Policy, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := Policy{}
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("could not parse policy %s: %s", path, err.Error())
+	}
+	if _, err := parseBytes(policy.MaxMemory); len(policy.MaxMemory) > 0 && err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Evaluate returns a PolicyError for the first rule the job violates.
+func (p *Policy) Evaluate(data JobData) error {
+	if p == nil {
+		return nil
+	}
+	if data.Type == TypeExec && data.Exec != nil {
+		return p.evaluateExecService(data.Exec.Service)
+	}
+	// Jobs of the http type do not start containers
+	if !runsImage(data) {
+		return nil
+	}
+	if err := validateImage(data); err != nil {
+		return err
+	}
+	args, err := parseArgs(data.Args)
+	if err != nil {
+		return fmt.Errorf("args are invalid: %s", err.Error())
+	}
+	checks := []func(JobData, map[string][]string) error{
+		p.evaluateImage,
+		p.evaluateMounts,
+		p.evaluateNetworks,
+		p.evaluateCapabilities,
+		p.evaluateResources,
+		p.evaluateLabels,
+	}
+	for _, check := range checks {
+		if err := check(data, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateExecService allows exec jobs only in the services listed in AllowedExecServices.
+func (p *Policy) evaluateExecService(service string) error {
+	if matchAny(p.DeniedExecServices, service) {
+		return &PolicyError{"deniedExecServices", fmt.Sprintf("exec in service %s is denied", service)}
+	}
+	if !matchAny(p.AllowedExecServices, service) {
+		return &PolicyError{"allowedExecServices", fmt.Sprintf("exec in service %s is not allowed", service)}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateImage(data JobData, args map[string][]string) error {
+	registry := imageRegistry(data.Image)
+	if matchAny(p.DeniedRegistries, registry) {
+		return &PolicyError{"deniedRegistries", fmt.Sprintf("registry %s is denied", registry)}
+	}
+	if len(p.AllowedRegistries) > 0 && !matchAny(p.AllowedRegistries, registry) {
+		return &PolicyError{"allowedRegistries", fmt.Sprintf("registry %s is not allowed", registry)}
+	}
+	if matchAny(p.DeniedImages, data.Image) {
+		return &PolicyError{"deniedImages", fmt.Sprintf("image %s is denied", data.Image)}
+	}
+	if len(p.AllowedImages) > 0 && !matchAny(p.AllowedImages, data.Image) {
+		return &PolicyError{"allowedImages", fmt.Sprintf("image %s is not allowed", data.Image)}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateMounts(data JobData, args map[string][]string) error {
+	for _, mount := range args["--mount"] {
+		options := parseOptions(mount)
+		mountType := options["type"]
+		if len(mountType) == 0 {
+			mountType = "volume"
+		}
+		if len(p.AllowedMountTypes) > 0 && !matchAny(p.AllowedMountTypes, mountType) {
+			return &PolicyError{"allowedMountTypes", fmt.Sprintf("mount type %s is not allowed", mountType)}
+		}
+		if mountType != "bind" {
+			continue
+		}
+		source := options["source"]
+		if len(source) == 0 {
+			source = options["src"]
+		}
+		if hasPathPrefix(p.DeniedHostPaths, source) {
+			return &PolicyError{"deniedHostPaths", fmt.Sprintf("host path %s is denied", source)}
+		}
+		if len(p.AllowedHostPaths) > 0 && !hasPathPrefix(p.AllowedHostPaths, source) {
+			return &PolicyError{"allowedHostPaths", fmt.Sprintf("host path %s is not allowed", source)}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateNetworks(data JobData, args map[string][]string) error {
+	for _, network := range args["--network"] {
+		if options := parseOptions(network); len(options["name"]) > 0 {
+			network = options["name"]
+		}
+		if matchAny(p.DeniedNetworks, network) {
+			return &PolicyError{"deniedNetworks", fmt.Sprintf("network %s is denied", network)}
+		}
+		if len(p.AllowedNetworks) > 0 && !matchAny(p.AllowedNetworks, network) {
+			return &PolicyError{"allowedNetworks", fmt.Sprintf("network %s is not allowed", network)}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateCapabilities(data JobData, args map[string][]string) error {
+	for _, value := range args["--cap-add"] {
+		for _, capability := range strings.Split(value, ",") {
+			capability = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(capability)), "CAP_")
+			if matchAny(p.DeniedCapabilities, capability) {
+				return &PolicyError{"deniedCapabilities", fmt.Sprintf("capability %s is denied", capability)}
+			}
+			if len(p.AllowedCapabilities) > 0 && !matchAny(p.AllowedCapabilities, capability) {
+				return &PolicyError{"allowedCapabilities", fmt.Sprintf("capability %s is not allowed", capability)}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateResources(data JobData, args map[string][]string) error {
+	if p.MaxCPU > 0 {
+		for _, flag := range []string{"--limit-cpu", "--reserve-cpu"} {
+			values := args[flag]
+			if len(values) == 0 && flag == "--limit-cpu" {
+				return &PolicyError{"maxCpu", fmt.Sprintf("%s is required", flag)}
+			}
+			for _, value := range values {
+				cpu, err := strconv.ParseFloat(value, 64)
+				if err != nil || cpu > p.MaxCPU {
+					return &PolicyError{"maxCpu", fmt.Sprintf("%s %s exceeds %g", flag, value, p.MaxCPU)}
+				}
+			}
+		}
+	}
+	if len(p.MaxMemory) > 0 {
+		max, _ := parseBytes(p.MaxMemory)
+		for _, flag := range []string{"--limit-memory", "--reserve-memory"} {
+			values := args[flag]
+			if len(values) == 0 && flag == "--limit-memory" {
+				return &PolicyError{"maxMemory", fmt.Sprintf("%s is required", flag)}
+			}
+			for _, value := range values {
+				memory, err := parseBytes(value)
+				if err != nil || memory > max {
+					return &PolicyError{"maxMemory", fmt.Sprintf("%s %s exceeds %s", flag, value, p.MaxMemory)}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) evaluateLabels(data JobData, args map[string][]string) error {
+	labels := map[string]string{}
+	for _, label := range append(args["--label"], args["-l"]...) {
+		kv := strings.SplitN(label, "=", 2)
+		labels[kv[0]] = ""
+		if len(kv) == 2 {
+			labels[kv[0]] = kv[1]
+		}
+	}
+	for _, required := range p.RequiredLabels {
+		kv := strings.SplitN(required, "=", 2)
+		value, ok := labels[kv[0]]
+		if !ok || (len(kv) == 2 && value != kv[1]) {
+			return &PolicyError{"requiredLabels", fmt.Sprintf("label %s is required", required)}
+		}
+	}
+	return nil
+}
+
+// parseArgs groups the arguments by flag. The arguments are split into words the same way the shell that runs
+// `docker service create` splits them, so that flags cannot hide inside another argument. A value that looks
+// like a flag is checked as a flag as well.
+func parseArgs(args []string) (map[string][]string, error) {
+	words, err := splitShellWords(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+	parsed := map[string][]string{}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, "-") {
+			continue
+		}
+		flag := word
+		value := ""
+		if j := strings.Index(word, "="); j > 0 {
+			flag = word[:j]
+			value = word[j+1:]
+		} else if !serviceBoolFlags[word] && i+1 < len(words) {
+			value = words[i+1]
+			if !strings.HasPrefix(value, "-") {
+				i++
+			}
+		}
+		parsed[flag] = append(parsed[flag], value)
+	}
+	return parsed, nil
+}
+
+// parseOptions parses comma separated key=value options, e.g. the value of the `--mount` flag.
+func parseOptions(value string) map[string]string {
+	options := map[string]string{}
+	for _, option := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) == 2 {
+			options[kv[0]] = kv[1]
+		}
+	}
+	return options
+}
+
+func imageRegistry(image string) string {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return "docker.io"
+	}
+	first := image[:i]
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first
+	}
+	return "docker.io"
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		expr := "^" + strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1) + "$"
+		if matched, _ := regexp.MatchString(expr, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPathPrefix returns true if the path is one of the prefixes or inside of one of them.
+// Paths are cleaned first so that e.g. `//var/run/./docker.sock` matches `/var/run/docker.sock`.
+func hasPathPrefix(prefixes []string, source string) bool {
+	source = strings.TrimRight(path.Clean(source), "/") + "/"
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(source, strings.TrimRight(path.Clean(prefix), "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var bytesRegexp = regexp.MustCompile(`^([0-9.]+)\s*([kKmMgGtT]?)[iI]?[bB]?$`)
+
+// parseBytes converts memory values used by `docker service create`, e.g. 512M or 1GB, into bytes.
+func parseBytes(value string) (int64, error) {
+	matches := bytesRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid memory value %s", value)
+	}
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory value %s", value)
+	}
+	multiplier := map[string]float64{"": 1, "k": 1 << 10, "m": 1 << 20, "g": 1 << 30, "t": 1 << 40}
+	return int64(number * multiplier[strings.ToLower(matches[2])]), nil
+}
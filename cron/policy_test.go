@@ -0,0 +1,247 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+}
+
+func TestPolicyUnitTestSuite(t *testing.T) {
+	s := new(PolicyTestSuite)
+	suite.Run(t, s)
+}
+
+// LoadPolicy
+
+func (s *PolicyTestSuite) Test_LoadPolicy_ReturnsNil_WhenPathIsEmpty() {
+	policy, err := LoadPolicy("")
+
+	s.NoError(err)
+	s.Nil(policy)
+}
+
+func (s *PolicyTestSuite) Test_LoadPolicy_ReadsJSONFile() {
+	file, _ := ioutil.TempFile("", "policy")
+	defer os.Remove(file.Name())
+	file.WriteString(`{"deniedHostPaths": ["/var/run/docker.sock"], "maxMemory": "1G"}`)
+	file.Close()
+
+	policy, err := LoadPolicy(file.Name())
+
+	s.NoError(err)
+	s.Equal([]string{"/var/run/docker.sock"}, policy.DeniedHostPaths)
+	s.Equal("1G", policy.MaxMemory)
+}
+
+func (s *PolicyTestSuite) Test_LoadPolicy_ReturnsError_WhenMaxMemoryIsInvalid() {
+	file, _ := ioutil.TempFile("", "policy")
+	defer os.Remove(file.Name())
+	file.WriteString(`{"maxMemory": "a lot"}`)
+	file.Close()
+
+	_, err := LoadPolicy(file.Name())
+
+	s.Error(err)
+}
+
+// Evaluate
+
+func (s *PolicyTestSuite) Test_Evaluate_ReturnsNil_WhenPolicyIsNil() {
+	var policy *Policy
+
+	s.NoError(policy.Evaluate(JobData{Image: "alpine"}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksRegistries() {
+	policy := Policy{AllowedRegistries: []string{"registry.acme.com"}}
+
+	s.NoError(policy.Evaluate(JobData{Image: "registry.acme.com/team/job:1.0"}))
+	s.assertRule("allowedRegistries", policy.Evaluate(JobData{Image: "alpine"}))
+	s.assertRule("allowedRegistries", policy.Evaluate(JobData{Image: "evil.com/registry.acme.com/job"}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksImages() {
+	policy := Policy{
+		AllowedImages: []string{"alpine*", "acme/*"},
+		DeniedImages:  []string{"acme/miner*"},
+	}
+
+	s.NoError(policy.Evaluate(JobData{Image: "alpine:3.5"}))
+	s.NoError(policy.Evaluate(JobData{Image: "acme/backup"}))
+	s.assertRule("deniedImages", policy.Evaluate(JobData{Image: "acme/miner:latest"}))
+	s.assertRule("allowedImages", policy.Evaluate(JobData{Image: "ubuntu"}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksHostPaths() {
+	policy := Policy{
+		AllowedHostPaths: []string{"/data"},
+		DeniedHostPaths:  []string{"/var/run/docker.sock", "/data/secrets"},
+	}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--mount type=bind,source=/data/backups,target=/backups"}}))
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--mount", "type=volume,source=/etc,target=/etc"}}))
+	s.assertRule("deniedHostPaths", policy.Evaluate(JobData{
+		Args: []string{"--mount=type=bind,source=/var/run/docker.sock,target=/var/run/docker.sock"},
+	}))
+	s.assertRule("deniedHostPaths", policy.Evaluate(JobData{
+		Args: []string{"--mount type=bind,src=/data/secrets/db,target=/secrets"},
+	}))
+	s.assertRule("allowedHostPaths", policy.Evaluate(JobData{
+		Args: []string{"--mount type=bind,source=/datastore,target=/data"},
+	}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_CleansHostPaths() {
+	policy := Policy{AllowedHostPaths: []string{"/data/"}, DeniedHostPaths: []string{"/var/run/docker.sock"}}
+	sources := []string{"/var/run/./docker.sock", "//var/run/docker.sock", "/var/../var/run/docker.sock"}
+
+	for _, source := range sources {
+		s.assertRule("deniedHostPaths", policy.Evaluate(JobData{
+			Args: []string{"--mount type=bind,source=" + source + ",target=/s"},
+		}))
+	}
+	s.assertRule("allowedHostPaths", policy.Evaluate(JobData{
+		Args: []string{"--mount type=bind,source=/data/../etc,target=/s"},
+	}))
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--mount type=bind,source=/data/./reports,target=/s"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksMountTypes() {
+	policy := Policy{AllowedMountTypes: []string{"volume", "tmpfs"}}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--mount source=my-volume,target=/data"}}))
+	s.assertRule("allowedMountTypes", policy.Evaluate(JobData{Args: []string{"--mount type=bind,source=/,target=/host"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksNetworks() {
+	policy := Policy{DeniedNetworks: []string{"host", "ingress"}}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--network my-net"}}))
+	s.assertRule("deniedNetworks", policy.Evaluate(JobData{Args: []string{"--network name=host,alias=job"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksCapabilities() {
+	policy := Policy{
+		AllowedCapabilities: []string{"NET_BIND_SERVICE", "CHOWN"},
+		DeniedCapabilities:  []string{"SYS_ADMIN"},
+	}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--cap-add CAP_CHOWN,net_bind_service"}}))
+	s.assertRule("deniedCapabilities", policy.Evaluate(JobData{Args: []string{"--cap-add SYS_ADMIN"}}))
+	s.assertRule("allowedCapabilities", policy.Evaluate(JobData{Args: []string{"--cap-add NET_ADMIN"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksEveryFlagInAnArgument() {
+	policy := Policy{DeniedHostPaths: []string{"/var/run/docker.sock"}, DeniedCapabilities: []string{"SYS_ADMIN"}}
+
+	s.assertRule("deniedHostPaths", policy.Evaluate(JobData{
+		Args: []string{"-e A=1 --mount type=bind,source=/var/run/docker.sock,target=/s"},
+	}))
+	s.assertRule("deniedCapabilities", policy.Evaluate(JobData{Args: []string{"--env A=1 --cap-add SYS_ADMIN"}}))
+	s.assertRule("deniedCapabilities", policy.Evaluate(JobData{Args: []string{"--env 'A=1 B=2' --cap-add=SYS_ADMIN"}}))
+	s.assertRule("deniedCapabilities", policy.Evaluate(JobData{Args: []string{"--hostname", "--cap-add SYS_ADMIN"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ReturnsError_WhenImageIsNotASingleWord() {
+	policy := Policy{DeniedHostPaths: []string{"/var/run/docker.sock"}}
+
+	err := policy.Evaluate(JobData{Image: "--mount=type=bind,source=/var/run/docker.sock,target=/s alpine"})
+
+	s.Error(err)
+	s.Error(policy.Evaluate(JobData{Image: "alpine --cap-add SYS_ADMIN"}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ReturnsError_WhenArgsCannotBeParsed() {
+	policy := Policy{}
+
+	s.Error(policy.Evaluate(JobData{Args: []string{`--env "A=1`}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksResourceCeilings() {
+	policy := Policy{MaxCPU: 1, MaxMemory: "512M"}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"--limit-cpu 0.5", "--limit-memory 256M"}}))
+	s.assertRule("maxCpu", policy.Evaluate(JobData{Args: []string{"--limit-memory 256M"}}))
+	s.assertRule("maxCpu", policy.Evaluate(JobData{Args: []string{"--limit-cpu 2", "--limit-memory 256M"}}))
+	s.assertRule("maxMemory", policy.Evaluate(JobData{Args: []string{"--limit-cpu 1", "--limit-memory 1G"}}))
+	s.assertRule("maxMemory", policy.Evaluate(JobData{Args: []string{"--limit-cpu 1"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksRequiredLabels() {
+	policy := Policy{RequiredLabels: []string{"team", "env=prod"}}
+
+	s.NoError(policy.Evaluate(JobData{Args: []string{"-l team=ops", "--label env=prod"}}))
+	s.assertRule("requiredLabels", policy.Evaluate(JobData{Args: []string{"-l team=ops"}}))
+	s.assertRule("requiredLabels", policy.Evaluate(JobData{Args: []string{"-l team=ops", "--label env=dev"}}))
+}
+
+func (s *PolicyTestSuite) Test_Evaluate_ChecksExecServices() {
+	exec := func(service string) JobData {
+		return JobData{Type: TypeExec, Exec: &ExecCommand{Service: service, Command: "ls"}}
+	}
+	policy := Policy{AllowedExecServices: []string{"web*"}, DeniedExecServices: []string{"web-admin"}}
+
+	s.NoError(policy.Evaluate(exec("web")))
+	s.assertRule("deniedExecServices", policy.Evaluate(exec("web-admin")))
+	s.assertRule("allowedExecServices", policy.Evaluate(exec("docker-flow-cron")))
+
+	empty := Policy{}
+	s.assertRule("allowedExecServices", empty.Evaluate(exec("web")))
+}
+
+// AddJob
+
+func (s *PolicyTestSuite) Test_AddJob_ReturnsPolicyError_WhenPolicyIsViolated() {
+	addFuncCalled := false
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		addFuncCalled = true
+		return 0, nil
+	}
+	c := Cron{
+		Cron:   rcron.New(),
+		Jobs:   map[string]rcron.EntryID{},
+		Policy: &Policy{DeniedImages: []string{"alpine"}},
+	}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@yearly"})
+
+	s.assertRule("deniedImages", err)
+	s.False(addFuncCalled)
+}
+
+func (s *PolicyTestSuite) Test_AddJob_ReturnsError_WhenImageStartsWithAFlag() {
+	addFuncCalled := false
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		addFuncCalled = true
+		return 0, nil
+	}
+	c := Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{},
+	}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "--cap-add=SYS_ADMIN alpine", Schedule: "@yearly"})
+
+	s.EqualError(err, "image --cap-add=SYS_ADMIN alpine is invalid")
+	s.False(addFuncCalled)
+}
+
+// Util
+
+func (s *PolicyTestSuite) assertRule(rule string, err error) {
+	policyErr, ok := err.(*PolicyError)
+	if s.True(ok, "expected a PolicyError, got %v", err) {
+		s.Equal(rule, policyErr.Rule)
+	}
+}
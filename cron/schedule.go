@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+var weekdays = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+var descriptorDescriptions = map[string]string{
+	"@yearly":   "every year on January 1 at 00:00",
+	"@annually": "every year on January 1 at 00:00",
+	"@monthly":  "on day 1 of every month at 00:00",
+	"@weekly":   "every Sunday at 00:00",
+	"@daily":    "every day at 00:00",
+	"@midnight": "every day at 00:00",
+	"@hourly":   "every hour at minute 0",
+}
+
+// NormalizeSchedule converts a schedule into the form stored in the `com.df.cron.schedule` label.
+// Standard five-field specs get the seconds field set to 0 and the `CRON_TZ=` prefix is replaced with `TZ=`,
+// the prefix understood by the scheduler. Schedules that are not recognized are returned unchanged.
+func NormalizeSchedule(schedule string) string {
+	fields := strings.Fields(schedule)
+	if len(fields) == 0 {
+		return schedule
+	}
+	prefix := []string{}
+	if strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=") {
+		prefix = []string{"TZ=" + fields[0][strings.Index(fields[0], "=")+1:]}
+		fields = fields[1:]
+	}
+	if len(fields) == 5 && !strings.HasPrefix(fields[0], "@") {
+		fields = append([]string{"0"}, fields...)
+	}
+	return strings.Join(append(prefix, fields...), " ")
+}
+
+// ValidateSchedule returns an error if the schedule cannot be parsed.
+func ValidateSchedule(schedule string) error {
+	_, err := parseSchedule(schedule)
+	return err
+}
+
+// PreviewSchedule returns the next count times the schedule fires after the given time.
+// Schedules without the `TZ=` prefix are evaluated in the location of the time.
+func PreviewSchedule(schedule string, from time.Time, count int) ([]time.Time, error) {
+	return PreviewJob(JobData{Schedule: schedule}, from, count)
+}
+
+// DescribeSchedule returns a human readable description of the schedule, e.g. `runs every weekday at 02:00 UTC`.
+func DescribeSchedule(schedule string) string {
+	timeZone, spec, err := splitTimeZone(NormalizeSchedule(schedule))
+	if err != nil {
+		return ""
+	}
+	suffix := ""
+	if len(timeZone) > 0 {
+		suffix = " " + timeZone
+	}
+	if description, ok := descriptorDescriptions[spec]; ok {
+		return "runs " + description + suffix
+	}
+	if strings.HasPrefix(spec, "@every ") {
+		duration, err := time.ParseDuration(strings.TrimPrefix(spec, "@every "))
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("runs every %s", duration)
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 6 {
+		return ""
+	}
+	sec, min, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+	if isNumber(sec) && isNumber(min) && isNumber(hour) {
+		at := fmt.Sprintf("%02d:%02d", toNumber(hour), toNumber(min))
+		if sec != "0" {
+			at = fmt.Sprintf("%s:%02d", at, toNumber(sec))
+		}
+		return fmt.Sprintf("runs %s at %s%s", describeDays(dom, month, dow, true), at, suffix)
+	}
+	parts := []string{}
+	switch {
+	case sec == "*" && min == "*" && hour == "*":
+		return strings.TrimSpace(fmt.Sprintf("runs every second %s", describeDays(dom, month, dow, false)))
+	case sec != "0":
+		parts = append(parts, describeField(sec, "second"))
+	}
+	if min != "*" || sec == "0" {
+		parts = append(parts, describeField(min, "minute"))
+	}
+	if hour != "*" {
+		parts = append(parts, describeField(hour, "hour"))
+	}
+	description := "runs " + strings.Join(parts, ", ")
+	if days := describeDays(dom, month, dow, false); len(days) > 0 {
+		description = fmt.Sprintf("%s, %s", description, days)
+	}
+	return description + suffix
+}
+
+// parseSchedule parses the schedule with the same parser the scheduler uses.
+// Time zones are checked first since the parser does not report invalid ones as errors.
+func parseSchedule(schedule string) (rcron.Schedule, error) {
+	normalized := NormalizeSchedule(schedule)
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("schedule is mandatory")
+	}
+	timeZone, spec, err := splitTimeZone(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if len(timeZone) > 0 {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid schedule %s: unknown time zone %s", schedule, timeZone)
+		}
+	}
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("invalid schedule %s", schedule)
+	}
+	parsed, err := rcron.Parse(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %s: %s", schedule, err.Error())
+	}
+	return parsed, nil
+}
+
+func describeField(value, unit string) string {
+	switch {
+	case value == "*":
+		return "every " + unit
+	case strings.HasPrefix(value, "*/"):
+		return fmt.Sprintf("every %s %ss", value[2:], unit)
+	case strings.Contains(value, "/"):
+		parts := strings.SplitN(value, "/", 2)
+		return fmt.Sprintf("every %s %ss from %s %s", parts[1], unit, unit, parts[0])
+	case strings.Contains(value, "-") && !strings.Contains(value, ","):
+		bounds := strings.SplitN(value, "-", 2)
+		return fmt.Sprintf("%ss %s through %s", unit, bounds[0], bounds[1])
+	case strings.Contains(value, ","):
+		return fmt.Sprintf("%ss %s", unit, strings.Replace(value, ",", ", ", -1))
+	}
+	return fmt.Sprintf("at %s %s", unit, value)
+}
+
+// describeDays describes the day of month, month and day of week fields.
+// When everyDay is false, an empty string is returned for schedules that run every day.
+func describeDays(dom, month, dow string, everyDay bool) string {
+	days := ""
+	switch {
+	case dow == "1-5" && isAny(dom):
+		days = "every weekday"
+	case !isAny(dow) && isAny(dom):
+		days = "every " + describeWeekdays(dow)
+	case !isAny(dom) && isAny(dow):
+		days = fmt.Sprintf("on day %s of every month", strings.Replace(dom, ",", ", ", -1))
+	case !isAny(dom) && !isAny(dow):
+		days = fmt.Sprintf("on day %s of every month and every %s", strings.Replace(dom, ",", ", ", -1), describeWeekdays(dow))
+	case everyDay:
+		days = "every day"
+	}
+	if !isAny(month) {
+		if strings.Contains(days, " of every month") {
+			return strings.Replace(days, "every month", describeMonths(month), 1)
+		}
+		if len(days) > 0 {
+			days += " "
+		}
+		days += "in " + describeMonths(month)
+	}
+	return days
+}
+
+func describeWeekdays(dow string) string {
+	names := []string{}
+	for _, value := range strings.Split(dow, ",") {
+		bounds := strings.SplitN(value, "-", 2)
+		for i, bound := range bounds {
+			if n, err := strconv.Atoi(bound); err == nil && n >= 0 && n <= 7 {
+				bounds[i] = weekdays[n%7]
+			} else if len(bound) >= 3 {
+				bounds[i] = strings.ToUpper(bound[:1]) + strings.ToLower(bound[1:])
+			}
+		}
+		names = append(names, strings.Join(bounds, " through "))
+	}
+	return strings.Join(names, " and ")
+}
+
+func describeMonths(month string) string {
+	names := []string{}
+	for _, value := range strings.Split(month, ",") {
+		bounds := strings.SplitN(value, "-", 2)
+		for i, bound := range bounds {
+			if n, err := strconv.Atoi(bound); err == nil && n >= 1 && n <= 12 {
+				bounds[i] = time.Month(n).String()
+			} else if len(bound) >= 3 {
+				bounds[i] = strings.ToUpper(bound[:1]) + strings.ToLower(bound[1:])
+			}
+		}
+		names = append(names, strings.Join(bounds, " through "))
+	}
+	return strings.Join(names, " and ")
+}
+
+func isAny(value string) bool {
+	return value == "*" || value == "?"
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.Atoi(value)
+	return err == nil
+}
+
+func toNumber(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
@@ -0,0 +1,135 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+}
+
+func TestScheduleUnitTestSuite(t *testing.T) {
+	s := new(ScheduleTestSuite)
+	suite.Run(t, s)
+}
+
+// NormalizeSchedule
+
+func (s *ScheduleTestSuite) Test_NormalizeSchedule_AddsSeconds_WhenSpecHasFiveFields() {
+	s.Equal("0 */5 * * * *", NormalizeSchedule("*/5 * * * *"))
+	s.Equal("0 30 2 * * 1-5", NormalizeSchedule("  30  2 * *\t1-5 "))
+}
+
+func (s *ScheduleTestSuite) Test_NormalizeSchedule_DoesNotChangeSixFieldSpecs() {
+	s.Equal("*/10 * * * * *", NormalizeSchedule("*/10 * * * * *"))
+}
+
+func (s *ScheduleTestSuite) Test_NormalizeSchedule_ReplacesCronTZPrefix() {
+	s.Equal("TZ=Europe/Berlin 0 0 2 * * *", NormalizeSchedule("CRON_TZ=Europe/Berlin 0 2 * * *"))
+	s.Equal("TZ=UTC @daily", NormalizeSchedule("CRON_TZ=UTC @daily"))
+	s.Equal("TZ=UTC 0 0 2 * * *", NormalizeSchedule("TZ=UTC 0 2 * * *"))
+}
+
+func (s *ScheduleTestSuite) Test_NormalizeSchedule_DoesNotChangeDescriptors() {
+	s.Equal("@every 1h30m", NormalizeSchedule("@every 1h30m"))
+	s.Equal("@hourly", NormalizeSchedule("@hourly"))
+	s.Equal("", NormalizeSchedule(""))
+}
+
+func (s *ScheduleTestSuite) Test_AddJob_SchedulesNormalizedSpec() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	actualSpec := ""
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		actualSpec = spec
+		return 0, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	c.AddJob(JobData{Image: "alpine", Name: "my-job", Schedule: "CRON_TZ=UTC */5 * * * *", Created: true})
+
+	s.Equal("TZ=UTC 0 */5 * * * *", actualSpec)
+}
+
+// ValidateSchedule
+
+func (s *ScheduleTestSuite) Test_ValidateSchedule_ReturnsNil_WhenScheduleIsValid() {
+	for _, schedule := range []string{"@daily", "@every 1h", "0 2 * * *", "*/10 * * * * *", "CRON_TZ=UTC 0 2 * * 1-5"} {
+		s.NoError(ValidateSchedule(schedule), schedule)
+	}
+}
+
+func (s *ScheduleTestSuite) Test_ValidateSchedule_ReturnsError_WhenScheduleIsInvalid() {
+	for _, schedule := range []string{"", "* *", "0 61 * * *", "@every forever", "@sometimes", "TZ=Mars/Olympus 0 2 * * *", "CRON_TZ=UTC"} {
+		s.Error(ValidateSchedule(schedule), schedule)
+	}
+}
+
+func (s *ScheduleTestSuite) Test_AddJob_ReturnsError_WhenScheduleIsInvalid() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	called := false
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		called = true
+		return 0, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Image: "alpine", Name: "my-job", Schedule: "0 61 * * *"})
+
+	s.Error(err)
+	s.False(called)
+	s.NotContains(c.Jobs, "my-job")
+}
+
+// PreviewSchedule
+
+func (s *ScheduleTestSuite) Test_PreviewSchedule_ReturnsNextTimes() {
+	from := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+
+	actual, err := PreviewSchedule("0 2 * * 1-5", from, 3)
+
+	s.NoError(err)
+	s.Equal([]time.Time{
+		time.Date(2017, 5, 8, 2, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 9, 2, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 10, 2, 0, 0, 0, time.UTC),
+	}, actual)
+}
+
+func (s *ScheduleTestSuite) Test_PreviewSchedule_UsesTimeZoneOfSchedule() {
+	from := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+
+	actual, _ := PreviewSchedule("CRON_TZ=Europe/Berlin 0 2 * * *", from, 1)
+
+	s.Equal(time.Date(2017, 5, 6, 0, 0, 0, 0, time.UTC), actual[0].UTC())
+}
+
+// DescribeSchedule
+
+func (s *ScheduleTestSuite) Test_DescribeSchedule_ReturnsDescription() {
+	expected := map[string]string{
+		"TZ=UTC 0 2 * * 1-5":  "runs every weekday at 02:00 UTC",
+		"30 14 * * *":         "runs every day at 14:30",
+		"15 30 6 1 * *":       "runs on day 1 of every month at 06:30:15",
+		"0 0 1 1,7 *":         "runs on day 1 of January and July at 00:00",
+		"0 9 * * MON,fri":     "runs every Mon and Fri at 09:00",
+		"0 9 * * 0,6":         "runs every Sunday and Saturday at 09:00",
+		"*/5 * * * *":         "runs every 5 minutes",
+		"*/10 * * * * *":      "runs every 10 seconds",
+		"0 */15 9-17 * * 1-5": "runs every 15 minutes, hours 9 through 17, every weekday",
+		"0 * * * 12 *":        "runs every minute, in December",
+		"@daily":              "runs every day at 00:00",
+		"CRON_TZ=UTC @hourly": "runs every hour at minute 0 UTC",
+		"@every 1h30m":        "runs every 1h30m0s",
+		"* * * * * *":         "runs every second",
+		"not a schedule":      "",
+	}
+	for schedule, description := range expected {
+		s.Equal(description, DescribeSchedule(schedule), schedule)
+	}
+}
@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"fmt"
+)
+
+// SuspendJob stops scheduling the job until it is resumed. Runs that already started are not affected.
+func (c *Cron) SuspendJob(jobName, by string) error {
+	data, err := c.getJobData(jobName)
+	if err != nil {
+		return err
+	}
+	if reason, ok := c.suspendedReason(jobName); ok {
+		return &ConflictError{Message: fmt.Sprintf("job %s is already suspended: %s", jobName, reason)}
+	}
+	reason := fmt.Sprintf("suspended by %s", by)
+	fmt.Println("Suspending job", jobName, "since it was", reason)
+	c.removeEntry(jobName)
+	c.setSuspended(jobName, reason)
+	c.labelSuspended(data, reason)
+	return nil
+}
+
+// ResumeJob schedules a suspended job again.
+// Jobs that were suspended because they expired are suspended again unless their window or max runs change.
+func (c *Cron) ResumeJob(jobName string) error {
+	data, err := c.getJobData(jobName)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.suspendedReason(jobName); !ok {
+		return &ConflictError{Message: fmt.Sprintf("job %s is not suspended", jobName)}
+	}
+	fmt.Println("Resuming job", jobName)
+	c.mu.Lock()
+	delete(c.suspended, jobName)
+	c.mu.Unlock()
+	c.labelSuspended(data, "")
+	data.Created = true
+	return c.AddJob(data)
+}
+
+// labelSuspended marks the service of the job with the `com.df.cron.suspended` label so that the job stays
+// suspended after a restart. The label is removed when the reason is empty.
+// Local jobs do not have a service and, like jobs stored as job mode services, their state is stored in the local
+// jobs file instead.
+func (c *Cron) labelSuspended(data JobData, reason string) {
+	if c.storesStateLocally(data) {
+		if err := c.saveLocalJobs(); err != nil {
+			fmt.Println("Could not save local jobs:", err.Error())
+		}
+		return
+	}
+	serviceName := data.Name
+	if len(data.ServiceName) > 0 {
+		serviceName = data.ServiceName
+	}
+	update := fmt.Sprintf(`docker service update --label-rm com.df.cron.suspended %s`, serviceName)
+	if len(reason) > 0 {
+		update = fmt.Sprintf(
+			`docker service update --label-add %s %s`,
+			shellQuote("com.df.cron.suspended="+reason),
+			serviceName,
+		)
+	}
+	fmt.Println(update)
+	if _, err := updateServiceFunc(update); err != nil {
+		fmt.Println("Could not execute command: ", update)
+	}
+}
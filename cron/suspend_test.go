@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type SuspendTestSuite struct {
+	suite.Suite
+	rCronAddFuncOrig func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error)
+	scheduled        int
+}
+
+func TestSuspendUnitTestSuite(t *testing.T) {
+	s := new(SuspendTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *SuspendTestSuite) SetupTest() {
+	s.rCronAddFuncOrig = rCronAddFunc
+	s.scheduled = 0
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		s.scheduled++
+		return rcron.EntryID(s.scheduled), nil
+	}
+}
+
+func (s *SuspendTestSuite) TearDownTest() {
+	rCronAddFunc = s.rCronAddFuncOrig
+}
+
+// SuspendJob
+
+func (s *SuspendTestSuite) Test_SuspendJob_StopsSchedulingJob() {
+	c := s.cron()
+
+	err := c.SuspendJob("my-job", "ops")
+
+	s.NoError(err)
+	s.NotContains(c.Jobs, "my-job")
+	state := c.GetState("my-job")
+	s.Equal(JobSuspended, state.Status)
+	s.Equal("suspended by ops", state.Reason)
+}
+
+func (s *SuspendTestSuite) Test_SuspendJob_ReturnsConflictError_WhenJobIsSuspended() {
+	c := s.cron()
+	c.SuspendJob("my-job", "ops")
+
+	err := c.SuspendJob("my-job", "ops")
+
+	s.IsType(&ConflictError{}, err)
+}
+
+func (s *SuspendTestSuite) Test_SuspendJob_ReturnsNotFoundError_WhenJobDoesNotExist() {
+	c := s.cron()
+
+	err := c.SuspendJob("other-job", "ops")
+
+	s.IsType(&NotFoundError{}, err)
+}
+
+func (s *SuspendTestSuite) Test_SuspendJob_DoesNotUpdateService_WhenJobModeIsSupported() {
+	updateServiceFuncOrig := updateServiceFunc
+	defer func() { updateServiceFunc = updateServiceFuncOrig }()
+	updates := []string{}
+	updateServiceFunc = func(cmd string) ([]byte, error) {
+		updates = append(updates, cmd)
+		return nil, nil
+	}
+	dir, _ := ioutil.TempDir("", "local-jobs")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+	service := swarm.Service{}
+	service.Spec.Name = "my-job"
+	service.Spec.Annotations.Labels = map[string]string{"com.df.cron.name": "my-job", "com.df.cron.schedule": "@daily"}
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: "alpine"}
+	c := &Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, jobMode: true, Service: ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{service}, nil
+		},
+	}}
+	c.LoadLocalJobs(path)
+
+	err := c.SuspendJob("my-job", "ops")
+
+	s.NoError(err)
+	s.Empty(updates)
+	restored := Cron{jobMode: true}
+	s.NoError(restored.LoadLocalJobs(path))
+	reason, ok := restored.suspendedReason("my-job")
+	s.True(ok)
+	s.Equal("suspended by ops", reason)
+	s.Empty(restored.getLocalJobs())
+}
+
+// ResumeJob
+
+func (s *SuspendTestSuite) Test_ResumeJob_SchedulesJob() {
+	c := s.cron()
+	c.SuspendJob("my-job", "ops")
+
+	err := c.ResumeJob("my-job")
+
+	s.NoError(err)
+	s.Equal(2, s.scheduled)
+	s.Contains(c.Jobs, "my-job")
+	s.Equal(JobScheduled, c.GetState("my-job").Status)
+}
+
+func (s *SuspendTestSuite) Test_ResumeJob_ReturnsConflictError_WhenJobIsNotSuspended() {
+	c := s.cron()
+
+	err := c.ResumeJob("my-job")
+
+	s.IsType(&ConflictError{}, err)
+}
+
+// Util
+
+func (s *SuspendTestSuite) cron() *Cron {
+	c := &Cron{
+		Cron: rcron.New(),
+		Jobs: map[string]rcron.EntryID{},
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{}, nil
+			},
+		},
+	}
+	s.NoError(c.AddJob(JobData{Name: "my-job", Schedule: "@daily", Type: TypeHTTP, HTTP: &HTTPRequest{URL: "http://example.com"}}))
+	return c
+}
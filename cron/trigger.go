@@ -0,0 +1,48 @@
+package cron
+
+import (
+	"fmt"
+)
+
+// TriggerJob starts a run of the job right away, outside of its schedule.
+// The returned run is the one that was started; its outcome is recorded in the history.
+func (c *Cron) TriggerJob(jobName, by string) (Run, error) {
+	data, err := c.getJobData(jobName)
+	if err != nil {
+		return Run{}, err
+	}
+	serviceName := data.Name
+	if len(data.ServiceName) > 0 {
+		serviceName = data.ServiceName
+	}
+	fmt.Println("Running", jobName, "triggered by", by)
+	run := newRun(jobName, TriggerManual)
+	run.TriggeredBy = by
+	c.startRun(run, data, serviceName, nil)
+	return run, nil
+}
+
+// GetRuns returns the latest runs of the job, newest first. All stored runs are returned when the limit is 0.
+func (c *Cron) GetRuns(jobName string, limit int) ([]Run, error) {
+	if c.History == nil {
+		return nil, &NotFoundError{Message: "the history of runs is not available"}
+	}
+	if _, err := c.getJobData(jobName); err != nil {
+		return nil, err
+	}
+	return c.History.Runs(jobName, limit)
+}
+
+// getJobData returns the job or NotFoundError if it does not exist.
+func (c *Cron) getJobData(jobName string) (JobData, error) {
+	jobs, err := c.GetJobs()
+	if err != nil {
+		return JobData{}, err
+	}
+	data, ok := jobs[jobName]
+	if !ok {
+		return JobData{}, &NotFoundError{Message: fmt.Sprintf("job %s does not exist", jobName)}
+	}
+	data.State = nil
+	return data, nil
+}
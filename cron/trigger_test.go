@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type TriggerTestSuite struct {
+	suite.Suite
+	rCronAddFuncOrig func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error)
+}
+
+func TestTriggerUnitTestSuite(t *testing.T) {
+	s := new(TriggerTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *TriggerTestSuite) SetupTest() {
+	s.rCronAddFuncOrig = rCronAddFunc
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+}
+
+func (s *TriggerTestSuite) TearDownTest() {
+	rCronAddFunc = s.rCronAddFuncOrig
+}
+
+// TriggerJob
+
+func (s *TriggerTestSuite) Test_TriggerJob_RunsJob() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+	history, _ := NewHistorian("")
+	c := s.cron(history)
+	s.NoError(c.AddJob(JobData{Name: "my-job", Schedule: "@daily", Type: TypeHTTP, HTTP: &HTTPRequest{URL: server.URL}}))
+
+	actual, err := c.TriggerJob("my-job", "ops")
+
+	s.NoError(err)
+	s.Equal("my-job", actual.Job)
+	s.Equal(TriggerManual, actual.Trigger)
+	s.Equal("ops", actual.TriggeredBy)
+	for i := 0; i < 100; i++ {
+		if runs, _ := history.Runs("my-job", 1); len(runs) > 0 && runs[0].Status != RunRunning {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	runs, _ := history.Runs("my-job", 0)
+	s.Len(runs, 1)
+	s.Equal(actual.ID, runs[0].ID)
+	s.Equal(RunSucceeded, runs[0].Status)
+}
+
+func (s *TriggerTestSuite) Test_TriggerJob_ReturnsNotFoundError_WhenJobDoesNotExist() {
+	history, _ := NewHistorian("")
+	c := s.cron(history)
+
+	_, err := c.TriggerJob("my-job", "ops")
+
+	s.IsType(&NotFoundError{}, err)
+}
+
+// GetRuns
+
+func (s *TriggerTestSuite) Test_GetRuns_ReturnsRunsOfJob() {
+	history, _ := NewHistorian("")
+	c := s.cron(history)
+	s.NoError(c.AddJob(JobData{Name: "my-job", Schedule: "@daily", Type: TypeHTTP, HTTP: &HTTPRequest{URL: "http://example.com"}}))
+	history.Record(Run{ID: "1", Job: "my-job", Status: RunSucceeded})
+	history.Record(Run{ID: "2", Job: "my-job", Status: RunFailed})
+	history.Record(Run{ID: "3", Job: "other-job", Status: RunFailed})
+
+	actual, err := c.GetRuns("my-job", 1)
+
+	s.NoError(err)
+	s.Len(actual, 1)
+	s.Equal("2", actual[0].ID)
+}
+
+func (s *TriggerTestSuite) Test_GetRuns_ReturnsNotFoundError_WhenJobDoesNotExist() {
+	history, _ := NewHistorian("")
+	c := s.cron(history)
+
+	_, err := c.GetRuns("my-job", 0)
+
+	s.IsType(&NotFoundError{}, err)
+}
+
+// Util
+
+func (s *TriggerTestSuite) cron(history Historian) *Cron {
+	return &Cron{
+		Cron:    rcron.New(),
+		Jobs:    map[string]rcron.EntryID{},
+		History: history,
+		Service: ServicerMock{
+			GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+				return []swarm.Service{}, nil
+			},
+		},
+	}
+}
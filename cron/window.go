@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+const JobSuspended = "Suspended"
+
+const (
+	ExpirySuspend = "suspend"
+	ExpiryDelete  = "delete"
+)
+
+// validateWindow checks the fields that limit when and how many times a job runs.
+func validateWindow(data JobData) error {
+	notBefore, notAfter := time.Time{}, time.Time{}
+	var err error
+	if len(data.NotBefore) > 0 {
+		if notBefore, err = time.Parse(time.RFC3339, data.NotBefore); err != nil {
+			return fmt.Errorf("notBefore must be an RFC3339 timestamp: %s", err.Error())
+		}
+	}
+	if len(data.NotAfter) > 0 {
+		if notAfter, err = time.Parse(time.RFC3339, data.NotAfter); err != nil {
+			return fmt.Errorf("notAfter must be an RFC3339 timestamp: %s", err.Error())
+		}
+	}
+	if !notBefore.IsZero() && !notAfter.IsZero() && !notBefore.Before(notAfter) {
+		return fmt.Errorf("notBefore must be before notAfter")
+	}
+	if data.MaxRuns < 0 {
+		return fmt.Errorf("maxRuns cannot be negative")
+	}
+	// Runs are counted from the history, which keeps only the latest runs of each job after a restart
+	if data.MaxRuns > historyMemorySize {
+		return fmt.Errorf("maxRuns cannot be more than %d", historyMemorySize)
+	}
+	switch data.OnExpiry {
+	case "", ExpirySuspend, ExpiryDelete:
+	default:
+		return fmt.Errorf("onExpiry must be either %s or %s", ExpirySuspend, ExpiryDelete)
+	}
+	return nil
+}
+
+// inWindow returns false if the time is before notBefore.
+// Times after notAfter are handled by expiryReason.
+func inWindow(data JobData, t time.Time) bool {
+	notBefore, err := time.Parse(time.RFC3339, data.NotBefore)
+	return err != nil || !t.Before(notBefore)
+}
+
+// expiryReason returns why the job should no longer run or an empty string if it should.
+func (c *Cron) expiryReason(data JobData, now time.Time) string {
+	if notAfter, err := time.Parse(time.RFC3339, data.NotAfter); err == nil && now.After(notAfter) {
+		return fmt.Sprintf("the job expired at %s", data.NotAfter)
+	}
+	if data.MaxRuns > 0 && c.runCount(data.Name) >= data.MaxRuns {
+		return fmt.Sprintf("the job reached the maximum of %d runs", data.MaxRuns)
+	}
+	return ""
+}
+
+// tick runs the job at a time set by its schedule unless the time is outside of its window.
+func (c *Cron) tick(data JobData, serviceName string) {
+	now := timeNow()
+	if !inWindow(data, now) {
+		fmt.Println("Skipping", data.Name, "since it cannot run before", data.NotBefore)
+		return
+	}
+	if reason := c.expiryReason(data, now); len(reason) > 0 {
+		c.expire(data, reason)
+		return
+	}
+	if reason, ok := excludedBy(c.Calendars, data.ExcludeCalendars, now); ok {
+		c.skipRun(data.Name, reason, now)
+		return
+	}
+	var done func(Run)
+	if data.MaxRuns > 0 {
+		done = func(Run) {
+			if reason := c.expiryReason(data, timeNow()); len(reason) > 0 {
+				c.expire(data, reason)
+			}
+		}
+	}
+	c.runJob(data, serviceName, TriggerSchedule, done)
+}
+
+// scheduleExpiry expires the job when notAfter passes, even if the schedule does not fire afterwards.
+func (c *Cron) scheduleExpiry(data JobData) {
+	notAfter, err := time.Parse(time.RFC3339, data.NotAfter)
+	if err != nil {
+		return
+	}
+	id, _ := c.entry(data.Name)
+	timeAfterFunc(notAfter.Sub(timeNow())+time.Second, func() {
+		if current, ok := c.entry(data.Name); ok && current == id {
+			c.expire(data, c.expiryReason(data, timeNow()))
+		}
+	})
+}
+
+// expire stops scheduling the job and, depending on onExpiry, suspends or deletes it.
+// Suspended jobs are marked with the `com.df.cron.suspended` label so that they stay suspended after a restart.
+func (c *Cron) expire(data JobData, reason string) {
+	if data.OnExpiry == ExpiryDelete {
+		fmt.Println("Removing job", data.Name, "since", reason)
+		if err := c.RemoveJob(data.Name); err != nil {
+			fmt.Println("Could not remove job", data.Name, err.Error())
+		}
+		return
+	}
+	fmt.Println("Suspending job", data.Name, "since", reason)
+	c.removeEntry(data.Name)
+	c.setSuspended(data.Name, reason)
+	c.labelSuspended(data, reason)
+}
+
+func (c *Cron) setSuspended(jobName, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.suspended == nil {
+		c.suspended = map[string]string{}
+	}
+	c.suspended[jobName] = reason
+}
+
+func (c *Cron) suspendedReason(jobName string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reason, ok := c.suspended[jobName]
+	return reason, ok
+}
+
+// countRun increases the number of runs of the job.
+func (c *Cron) countRun(jobName string) {
+	count := c.runCount(jobName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runs[jobName] = count + 1
+}
+
+// runCount returns the number of runs of the job. The count starts from the runs recorded in the history.
+// Skipped runs are not counted.
+func (c *Cron) runCount(jobName string) int {
+	c.mu.Lock()
+	count, ok := c.runs[jobName]
+	c.mu.Unlock()
+	if ok {
+		return count
+	}
+	if c.History != nil {
+		if runs, err := c.History.Runs(jobName, 0); err == nil {
+			for _, run := range runs {
+				if run.Status != RunSkipped {
+					count++
+				}
+			}
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runs == nil {
+		c.runs = map[string]int{}
+	}
+	if _, ok := c.runs[jobName]; !ok {
+		c.runs[jobName] = count
+	}
+	return c.runs[jobName]
+}
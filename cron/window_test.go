@@ -0,0 +1,217 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	rcron "gopkg.in/robfig/cron.v2"
+)
+
+type WindowTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestWindowUnitTestSuite(t *testing.T) {
+	s := new(WindowTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *WindowTestSuite) SetupTest() {
+	s.now = time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *WindowTestSuite) TearDownTest() {
+	timeNow = time.Now
+}
+
+// validateWindow
+
+func (s *WindowTestSuite) Test_ValidateWindow_AcceptsValidFields() {
+	s.NoError(validateWindow(JobData{}))
+	s.NoError(validateWindow(JobData{
+		NotBefore: "2017-05-01T00:00:00Z",
+		NotAfter:  "2017-06-01T00:00:00Z",
+		MaxRuns:   3,
+		OnExpiry:  ExpiryDelete,
+	}))
+}
+
+func (s *WindowTestSuite) Test_ValidateWindow_ReturnsError_WhenFieldsAreInvalid() {
+	s.Error(validateWindow(JobData{NotBefore: "tomorrow"}))
+	s.Error(validateWindow(JobData{NotAfter: "2017-05-01"}))
+	s.Error(validateWindow(JobData{NotBefore: "2017-06-01T00:00:00Z", NotAfter: "2017-05-01T00:00:00Z"}))
+	s.Error(validateWindow(JobData{MaxRuns: -1}))
+	s.Error(validateWindow(JobData{MaxRuns: historyMemorySize + 1}))
+	s.Error(validateWindow(JobData{OnExpiry: "forget"}))
+}
+
+// tick
+
+func (s *WindowTestSuite) Test_Tick_SkipsRun_WhenWindowDidNotStart() {
+	history, _ := NewHistorian("")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{"my-job": 1}, History: history}
+
+	c.tick(JobData{Name: "my-job", NotBefore: "2017-05-06T00:00:00Z"}, "my-job")
+	runs, _ := history.Runs("my-job", 0)
+
+	s.Empty(runs)
+	s.Contains(c.Jobs, "my-job")
+}
+
+func (s *WindowTestSuite) Test_Tick_SuspendsJob_WhenWindowEnded() {
+	history, _ := NewHistorian("")
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{"my-job": 1}, History: history}
+
+	c.tick(JobData{Name: "my-job", NotAfter: "2017-05-05T09:00:00Z"}, "my-job")
+	runs, _ := history.Runs("my-job", 0)
+	state := c.GetState("my-job")
+
+	s.Empty(runs)
+	s.NotContains(c.Jobs, "my-job")
+	s.Equal(JobSuspended, state.Status)
+	s.Equal("the job expired at 2017-05-05T09:00:00Z", state.Reason)
+}
+
+func (s *WindowTestSuite) Test_Tick_DeletesJob_WhenMaxRunsIsReachedAndOnExpiryIsDelete() {
+	history, _ := NewHistorian("")
+	history.Record(Run{ID: "1", Job: "my-job"})
+	history.Record(Run{ID: "2", Job: "my-job"})
+	removed := ""
+	c := Cron{
+		Cron:    rcron.New(),
+		Jobs:    map[string]rcron.EntryID{"my-job": 1},
+		History: history,
+		Service: ServicerMock{
+			RemoveServicesMock: func(jobName string) error {
+				removed = jobName
+				return nil
+			},
+		},
+	}
+
+	c.tick(JobData{Name: "my-job", MaxRuns: 2, OnExpiry: ExpiryDelete}, "my-job")
+
+	s.Equal("my-job", removed)
+	s.NotContains(c.Jobs, "my-job")
+	s.Empty(c.GetState("my-job").Status)
+}
+
+// AddJob
+
+func (s *WindowTestSuite) Test_AddJob_SuspendsJob_WhenMaxRunsIsReached() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	scheduled := false
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		scheduled = true
+		return 1, nil
+	}
+	history, _ := NewHistorian("")
+	history.Record(Run{ID: "1", Job: "my-job"})
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}, History: history}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", MaxRuns: 1, Created: true})
+
+	s.NoError(err)
+	s.False(scheduled)
+	s.Equal("the job reached the maximum of 1 runs", c.GetState("my-job").Reason)
+}
+
+func (s *WindowTestSuite) Test_AddJob_DoesNotScheduleSuspendedJob() {
+	rCronAddFuncOrig := rCronAddFunc
+	defer func() { rCronAddFunc = rCronAddFuncOrig }()
+	scheduled := false
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		scheduled = true
+		return 1, nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.setSuspended("my-job", "the job expired")
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", Created: true})
+
+	s.NoError(err)
+	s.False(scheduled)
+	s.Equal(JobSuspended, c.GetState("my-job").Status)
+}
+
+func (s *WindowTestSuite) Test_AddJob_SchedulesExpiry() {
+	rCronAddFuncOrig := rCronAddFunc
+	timeAfterFuncOrig := timeAfterFunc
+	defer func() {
+		rCronAddFunc = rCronAddFuncOrig
+		timeAfterFunc = timeAfterFuncOrig
+	}()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	actualDelay := time.Duration(0)
+	var expire func()
+	timeAfterFunc = func(d time.Duration, f func()) *time.Timer {
+		actualDelay = d
+		expire = f
+		return nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+
+	err := c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", NotAfter: "2017-05-05T12:00:00Z", Created: true})
+	s.now = s.now.Add(3 * time.Hour)
+	expire()
+
+	s.NoError(err)
+	s.Equal(2*time.Hour+time.Second, actualDelay)
+	s.Equal(JobSuspended, c.GetState("my-job").Status)
+}
+
+func (s *WindowTestSuite) Test_Expire_DoesNotRace_WithJobsBeingAdded() {
+	rCronAddFuncOrig := rCronAddFunc
+	timeAfterFuncOrig := timeAfterFunc
+	defer func() {
+		rCronAddFunc = rCronAddFuncOrig
+		timeAfterFunc = timeAfterFuncOrig
+	}()
+	rCronAddFunc = func(c *rcron.Cron, spec string, cmd func()) (rcron.EntryID, error) {
+		return 1, nil
+	}
+	var expire func()
+	timeAfterFunc = func(d time.Duration, f func()) *time.Timer {
+		expire = f
+		return nil
+	}
+	c := Cron{Cron: rcron.New(), Jobs: map[string]rcron.EntryID{}}
+	c.AddJob(JobData{Name: "my-job", Image: "alpine", Schedule: "@daily", NotAfter: "2017-05-05T12:00:00Z", Created: true})
+	s.now = s.now.Add(3 * time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		expire()
+		close(done)
+	}()
+	for i := 0; i < 20; i++ {
+		c.GetState("my-job")
+		c.AddJob(JobData{Name: fmt.Sprintf("other-job-%d", i), Image: "alpine", Schedule: "@daily", Created: true})
+	}
+	<-done
+
+	s.Equal(JobSuspended, c.GetState("my-job").Status)
+	s.Len(c.Jobs, 20)
+}
+
+// runCount
+
+func (s *WindowTestSuite) Test_RunCount_StartsFromHistory() {
+	history, _ := NewHistorian("")
+	history.Record(Run{ID: "1", Job: "my-job"})
+	c := Cron{History: history}
+
+	c.countRun("my-job")
+
+	s.Equal(2, c.runCount("my-job"))
+	s.Equal(0, c.runCount("other-job"))
+}
@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"errors"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+)
+
+type Containerer interface {
+	RunContainer(name string, spec ContainerSpec) (int64, error)
+	RemoveContainer(name string) error
+}
+
+// ContainerSpec describes a container that runs a job without Swarm.
+type ContainerSpec struct {
+	Image      string
+	Cmd        []string
+	Entrypoint []string
+	Env        []string
+	Labels     map[string]string
+	Mounts     []mount.Mount
+	Network    string
+	User       string
+	WorkingDir string
+	Hostname   string
+}
+
+// RunContainer creates and starts a container, waits until it exits and removes it.
+// The image is pulled when it does not exist. It returns the exit code of the container.
+func (s *Service) RunContainer(name string, spec ContainerSpec) (int64, error) {
+	ctx := context.Background()
+	config := &container.Config{
+		Image:      spec.Image,
+		Cmd:        spec.Cmd,
+		Entrypoint: spec.Entrypoint,
+		Env:        spec.Env,
+		Labels:     spec.Labels,
+		User:       spec.User,
+		WorkingDir: spec.WorkingDir,
+		Hostname:   spec.Hostname,
+	}
+	hostConfig := &container.HostConfig{
+		Mounts:      spec.Mounts,
+		NetworkMode: container.NetworkMode(spec.Network),
+	}
+	created, err := s.Client.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
+	if client.IsErrNotFound(err) {
+		if err = s.pullImage(spec.Image); err != nil {
+			return 0, err
+		}
+		created, err = s.Client.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer s.Client.ContainerRemove(ctx, created.ID, types.ContainerRemoveOptions{Force: true})
+	if err := s.Client.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return 0, err
+	}
+	statusCh, errCh := s.Client.ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		if status.Error != nil {
+			return status.StatusCode, errors.New(status.Error.Message)
+		}
+		return status.StatusCode, nil
+	case err := <-errCh:
+		return 0, err
+	}
+}
+
+// RemoveContainer stops and removes a container.
+func (s *Service) RemoveContainer(name string) error {
+	return s.Client.ContainerRemove(context.Background(), name, types.ContainerRemoveOptions{Force: true})
+}
+
+func (s *Service) pullImage(image string) error {
+	reader, err := s.Client.ImagePull(context.Background(), image, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(ioutil.Discard, reader)
+	return err
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerUnitTestSuite(t *testing.T) {
+	s := new(ContainerTestSuite)
+	suite.Run(t, s)
+}
+
+// RunContainer
+
+func (s *ContainerTestSuite) Test_RunContainer_ReturnsExitCode() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	actual, err := services.RunContainer("test-container", ContainerSpec{Image: "alpine", Cmd: []string{"sh", "-c", "exit 3"}})
+
+	s.NoError(err)
+	s.Equal(int64(3), actual)
+}
+
+func (s *ContainerTestSuite) Test_RunContainer_RemovesContainer() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	services.RunContainer("test-container", ContainerSpec{Image: "alpine", Cmd: []string{"true"}})
+
+	out, _ := exec.Command("docker", "ps", "-aq", "-f", "name=test-container").CombinedOutput()
+	s.Empty(string(out))
+}
+
+// RemoveContainer
+
+func (s *ContainerTestSuite) Test_RemoveContainer_ReturnsError_WhenContainerDoesNotExist() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	err := services.RemoveContainer("this-container-does-not-exist")
+
+	s.Error(err)
+}
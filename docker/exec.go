@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"bytes"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"golang.org/x/net/context"
+	"sort"
+	"strings"
+)
+
+type Execer interface {
+	GetServiceContainers(serviceName string) ([]ServiceContainer, error)
+	CountRemoteTasks(serviceName string) (int, error)
+	ExecContainer(containerID string, cmd []string) (int64, string, error)
+}
+
+// ServiceContainer is a running container of a task of a Swarm service.
+type ServiceContainer struct {
+	ID   string
+	Name string
+}
+
+// GetServiceContainers returns the running containers of the service on the node Docker Flow Cron is connected to,
+// sorted by name so that the task with the lowest slot comes first.
+func (s *Service) GetServiceContainers(serviceName string) ([]ServiceContainer, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", "com.docker.swarm.service.name="+serviceName)
+	filter.Add("status", "running")
+	list, err := s.Client.ContainerList(context.Background(), types.ContainerListOptions{Filters: filter})
+	if err != nil {
+		return []ServiceContainer{}, err
+	}
+	containers := []ServiceContainer{}
+	for _, c := range list {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		containers = append(containers, ServiceContainer{ID: c.ID, Name: name})
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Name < containers[j].Name
+	})
+	return containers, nil
+}
+
+// CountRemoteTasks returns the number of running tasks of the service on nodes other than the one
+// Docker Flow Cron is connected to. Their containers cannot be reached through the exec API.
+func (s *Service) CountRemoteTasks(serviceName string) (int, error) {
+	ctx := context.Background()
+	info, err := s.Client.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+	filter := filters.NewArgs()
+	filter.Add("service", serviceName)
+	filter.Add("desired-state", "running")
+	tasks, err := s.Client.TaskList(ctx, types.TaskListOptions{Filters: filter})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, task := range tasks {
+		if task.Status.State == swarm.TaskStateRunning && task.NodeID != info.Swarm.NodeID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ExecContainer runs the command in the container and waits until it exits.
+// It returns the exit code of the command and its combined stdout and stderr.
+func (s *Service) ExecContainer(containerID string, cmd []string) (int64, string, error) {
+	ctx := context.Background()
+	created, err := s.Client.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	attached, err := s.Client.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return 0, "", err
+	}
+	defer attached.Close()
+	output := bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(&output, &output, attached.Reader); err != nil {
+		return 0, output.String(), err
+	}
+	inspected, err := s.Client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 0, output.String(), err
+	}
+	return int64(inspected.ExitCode), output.String(), nil
+}
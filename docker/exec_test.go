@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecTestSuite struct {
+	suite.Suite
+}
+
+func TestExecUnitTestSuite(t *testing.T) {
+	s := new(ExecTestSuite)
+	suite.Run(t, s)
+}
+
+// GetServiceContainers
+
+func (s *ExecTestSuite) Test_GetServiceContainers_ReturnsEmptyList_WhenServiceDoesNotExist() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	actual, err := services.GetServiceContainers("this-service-does-not-exist")
+
+	s.NoError(err)
+	s.Empty(actual)
+}
+
+// CountRemoteTasks
+
+func (s *ExecTestSuite) Test_CountRemoteTasks_ReturnsZero_WhenServiceDoesNotExist() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	actual, err := services.CountRemoteTasks("this-service-does-not-exist")
+
+	s.NoError(err)
+	s.Equal(0, actual)
+}
+
+// ExecContainer
+
+func (s *ExecTestSuite) Test_ExecContainer_ReturnsError_WhenContainerDoesNotExist() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	_, _, err := services.ExecContainer("this-container-does-not-exist", []string{"ls"})
+
+	s.Error(err)
+}
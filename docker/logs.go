@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"errors"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"golang.org/x/net/context"
+	"io"
+)
+
+// ErrNoService is returned when logs are requested for a job that does not have a service.
+var ErrNoService = errors.New("the job does not have a service")
+
+type Logger interface {
+	GetLogs(jobName string, follow bool, tail string) (io.ReadCloser, error)
+}
+
+// GetLogs returns the combined stdout and stderr of the tasks of the service of the job, prefixed with timestamps.
+// The tail is the number of lines to return from the end of the logs; all lines are returned when it is empty.
+// When following, the reader stays open until it is closed.
+func (s *Service) GetLogs(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+	services, err := s.GetServices(jobName)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, ErrNoService
+	}
+	if len(tail) == 0 {
+		tail = "all"
+	}
+	logs, err := s.Client.ServiceLogs(context.Background(), services[0].ID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     follow,
+		Tail:       tail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		writer.CloseWithError(err)
+	}()
+	return logsReader{PipeReader: reader, logs: logs}, nil
+}
+
+// logsReader returns the demultiplexed logs and closes the stream of the daemon when it is closed.
+type logsReader struct {
+	*io.PipeReader
+	logs io.ReadCloser
+}
+
+func (r logsReader) Close() error {
+	r.logs.Close()
+	return r.PipeReader.Close()
+}
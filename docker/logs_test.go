@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LogsTestSuite struct {
+	suite.Suite
+}
+
+func TestLogsUnitTestSuite(t *testing.T) {
+	s := new(LogsTestSuite)
+	suite.Run(t, s)
+}
+
+// GetLogs
+
+func (s *LogsTestSuite) Test_GetLogs_ReturnsErrNoService_WhenJobDoesNotExist() {
+	services, _ := New("unix:///var/run/docker.sock")
+
+	_, err := services.GetLogs("this-job-does-not-exist", false, "")
+
+	s.Equal(ErrNoService, err)
+}
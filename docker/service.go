@@ -1,24 +1,46 @@
 package docker
 
 import (
+	"errors"
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"golang.org/x/net/context"
+	"sort"
+	"strings"
+	"time"
 )
 
 const dockerApiVersion = "v1.24"
 
+// jobModeApiVersion is the first API version that supports the replicated-job and global-job service modes.
+const jobModeApiVersion = "v1.41"
+
+// ErrNotJob is returned when a service that is not in a job mode is run as a job.
+var ErrNotJob = errors.New("the service is not a job")
+
+// runLabel marks services created for a single run of a job.
+const runLabel = "com.df.cron.run"
+
 type Servicer interface {
 	GetServices(jobName string) ([]swarm.Service, error)
 	GetTasks(jobName string) ([]swarm.Task, error)
 	RemoveServices(jobName string) error
+	ScaleService(serviceName string, replicas uint64) error
+	JobModeSupported() bool
+	RunJob(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error)
+	RunService(serviceName, runName string, labels map[string]string) (string, error)
+	GetRunServices(jobName string) ([]swarm.Service, error)
+	RemoveService(serviceName string) error
+	CreateService(spec swarm.ServiceSpec) error
 }
 
 type Service struct {
-	Client *client.Client
+	Client  *client.Client
+	jobMode bool
 }
 
 func New(host string) (*Service, error) {
@@ -27,7 +49,32 @@ func New(host string) (*Service, error) {
 	if err != nil {
 		return &Service{}, err
 	}
-	return &Service{Client: c}, nil
+	s := &Service{Client: c}
+	s.detectApiVersion(host, defaultHeaders)
+	return s, nil
+}
+
+// detectApiVersion switches the client to the API version with job mode services when the daemon supports it.
+// The client stays on the default version when the daemon cannot be reached.
+func (s *Service) detectApiVersion(host string, headers map[string]string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	version, err := s.Client.ServerVersion(ctx)
+	if err != nil || versions.LessThan(version.APIVersion, strings.TrimPrefix(jobModeApiVersion, "v")) {
+		return
+	}
+	c, err := client.NewClient(host, jobModeApiVersion, nil, headers)
+	if err != nil {
+		return
+	}
+	fmt.Println("Using job mode services of Docker API", version.APIVersion)
+	s.Client = c
+	s.jobMode = true
+}
+
+// JobModeSupported returns true when the daemon supports the replicated-job and global-job service modes.
+func (s *Service) JobModeSupported() bool {
+	return s.jobMode
 }
 
 func (s *Service) GetServices(jobName string) ([]swarm.Service, error) {
@@ -40,7 +87,13 @@ func (s *Service) GetServices(jobName string) ([]swarm.Service, error) {
 	if err != nil {
 		return []swarm.Service{}, err
 	}
-	return services, nil
+	jobServices := []swarm.Service{}
+	for _, service := range services {
+		if _, ok := service.Spec.Labels[runLabel]; !ok {
+			jobServices = append(jobServices, service)
+		}
+	}
+	return jobServices, nil
 }
 
 func (s *Service) GetTasks(jobName string) ([]swarm.Task, error) {
@@ -59,8 +112,132 @@ func (s *Service) RemoveServices(jobName string) error {
 	if err != nil {
 		return err
 	}
-	for _, service := range services {
+	runServices, err := s.GetRunServices(jobName)
+	if err != nil {
+		return err
+	}
+	for _, service := range append(services, runServices...) {
 		s.Client.ServiceRemove(context.Background(), service.Spec.Name)
 	}
 	return nil
 }
+
+// ScaleService sets the number of replicas of a replicated service or the total completions of a replicated job.
+// Scaling a replicated job starts a new iteration of the job and shuts down the tasks of the previous one,
+// so scaling it to 0 stops the tasks that are running. Global jobs cannot be scaled.
+func (s *Service) ScaleService(serviceName string, replicas uint64) error {
+	service, _, err := s.Client.ServiceInspectWithRaw(context.Background(), serviceName, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+	if service.Spec.Mode.Replicated != nil {
+		service.Spec.Mode.Replicated.Replicas = &replicas
+	} else if service.Spec.Mode.ReplicatedJob != nil {
+		service.Spec.Mode.ReplicatedJob.TotalCompletions = &replicas
+		if maxConcurrent := service.Spec.Mode.ReplicatedJob.MaxConcurrent; maxConcurrent == nil || *maxConcurrent > replicas {
+			service.Spec.Mode.ReplicatedJob.MaxConcurrent = &replicas
+		}
+	} else {
+		return fmt.Errorf("service %s is not replicated", serviceName)
+	}
+	_, err = s.Client.ServiceUpdate(context.Background(), service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{})
+	return err
+}
+
+// RunService creates a service with the spec of the job service, the given name and additional labels.
+// The new service runs a single task. It returns the ID of the created service.
+func (s *Service) RunService(serviceName, runName string, labels map[string]string) (string, error) {
+	service, _, err := s.Client.ServiceInspectWithRaw(context.Background(), serviceName, types.ServiceInspectOptions{})
+	if err != nil {
+		return "", err
+	}
+	spec := service.Spec
+	spec.Name = runName
+	spec.Labels = map[string]string{}
+	for k, v := range service.Spec.Labels {
+		spec.Labels[k] = v
+	}
+	for k, v := range labels {
+		spec.Labels[k] = v
+	}
+	replicas := uint64(1)
+	spec.Mode = swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}
+	response, err := s.Client.ServiceCreate(context.Background(), spec, types.ServiceCreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+// GetRunServices returns the services created for single runs of the job, oldest first.
+func (s *Service) GetRunServices(jobName string) ([]swarm.Service, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", "com.df.cron=true")
+	filter.Add("label", runLabel)
+	if len(jobName) > 0 {
+		filter.Add("label", fmt.Sprintf("com.df.cron.name=%s", jobName))
+	}
+	services, err := s.Client.ServiceList(context.Background(), types.ServiceListOptions{Filters: filter})
+	if err != nil {
+		return []swarm.Service{}, err
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].CreatedAt.Before(services[j].CreatedAt)
+	})
+	return services, nil
+}
+
+// RemoveService removes a single service.
+func (s *Service) RemoveService(serviceName string) error {
+	return s.Client.ServiceRemove(context.Background(), serviceName)
+}
+
+// CreateService creates a service with the spec, e.g. of a job service that was removed.
+// Replicated services and replicated jobs are created without tasks so that they do not start a run.
+func (s *Service) CreateService(spec swarm.ServiceSpec) error {
+	none := uint64(0)
+	if spec.Mode.Replicated != nil {
+		spec.Mode.Replicated = &swarm.ReplicatedService{Replicas: &none}
+	} else if spec.Mode.ReplicatedJob != nil {
+		spec.Mode.ReplicatedJob = &swarm.ReplicatedJob{MaxConcurrent: &none, TotalCompletions: &none}
+	}
+	_, err := s.Client.ServiceCreate(context.Background(), spec, types.ServiceCreateOptions{})
+	return err
+}
+
+// RunJob starts a new iteration of a job mode service and returns its index.
+// Replicated jobs run maxConcurrent tasks at a time until totalCompletions tasks complete.
+// When maxConcurrent is 0, one task runs at a time. When totalCompletions is 0, it is set to maxConcurrent.
+// ErrNotJob is returned when the service is not in a job mode.
+func (s *Service) RunJob(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+	service, _, err := s.Client.ServiceInspectWithRaw(context.Background(), serviceName, types.ServiceInspectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if service.Spec.Mode.ReplicatedJob == nil && service.Spec.Mode.GlobalJob == nil {
+		return 0, ErrNotJob
+	}
+	if service.Spec.Mode.ReplicatedJob != nil {
+		if maxConcurrent == 0 {
+			maxConcurrent = 1
+		}
+		if totalCompletions == 0 {
+			totalCompletions = maxConcurrent
+		}
+		service.Spec.Mode.ReplicatedJob.MaxConcurrent = &maxConcurrent
+		service.Spec.Mode.ReplicatedJob.TotalCompletions = &totalCompletions
+	}
+	// Every update of the spec starts a new iteration of the job
+	service.Spec.TaskTemplate.ForceUpdate++
+	if _, err = s.Client.ServiceUpdate(context.Background(), service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{}); err != nil {
+		return 0, err
+	}
+	service, _, err = s.Client.ServiceInspectWithRaw(context.Background(), serviceName, types.ServiceInspectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if service.JobStatus == nil {
+		return 0, fmt.Errorf("service %s has no job status", serviceName)
+	}
+	return service.JobStatus.JobIteration.Index, nil
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +84,89 @@ func (s *ServiceTestSuite) Test_GetServices_ReturnsFilteredServices() {
 	}
 }
 
+func (s *ServiceTestSuite) Test_GetServices_ExcludesRunServices() {
+	s.removeAllServices()
+	defer s.removeAllServices()
+	s.createTestService("util-12", "-l com.df.cron.name=my-job -l com.df.cron=true")
+	s.createTestService("util-12-1", "-l com.df.cron.name=my-job -l com.df.cron=true -l com.df.cron.run=1")
+	services, _ := New("unix:///var/run/docker.sock")
+
+	actual, _ := services.GetServices("my-job")
+
+	s.Equal(1, len(actual))
+}
+
+// GetRunServices
+
+func (s *ServiceTestSuite) Test_GetRunServices_ReturnsRunServicesOldestFirst() {
+	s.removeAllServices()
+	defer s.removeAllServices()
+	s.createTestService("util-13", "-l com.df.cron.name=my-job -l com.df.cron=true")
+	s.createTestService("util-13-1", "-l com.df.cron.name=my-job -l com.df.cron=true -l com.df.cron.run=1")
+	s.createTestService("util-13-2", "-l com.df.cron.name=my-job -l com.df.cron=true -l com.df.cron.run=2")
+	services, _ := New("unix:///var/run/docker.sock")
+
+	actual, _ := services.GetRunServices("my-job")
+
+	if len(actual) != 2 {
+		s.Fail("Run services not found")
+	} else {
+		s.Equal("util-13-1", actual[0].Spec.Name)
+		s.Equal("util-13-2", actual[1].Spec.Name)
+	}
+}
+
+// RunService
+
+func (s *ServiceTestSuite) Test_RunService_CreatesServiceWithSpecOfJobService() {
+	s.removeAllServices()
+	defer s.removeAllServices()
+	s.createTestService("util-14", "-l com.df.cron.name=my-job -l com.df.cron=true --replicas 0")
+	services, _ := New("unix:///var/run/docker.sock")
+
+	id, err := services.RunService("util-14", "util-14-1", map[string]string{"com.df.cron.run": "1"})
+
+	s.NoError(err)
+	actual, _ := services.GetRunServices("my-job")
+	if len(actual) != 1 {
+		s.Fail("Run service not found")
+	} else {
+		s.Equal(id, actual[0].ID)
+		s.Equal("util-14-1", actual[0].Spec.Name)
+		s.Equal("alpine", strings.Split(actual[0].Spec.TaskTemplate.ContainerSpec.Image, ":")[0])
+		s.Equal(uint64(1), *actual[0].Spec.Mode.Replicated.Replicas)
+	}
+}
+
+// RunJob
+
+func (s *ServiceTestSuite) Test_RunJob_ReturnsErrNotJob_WhenServiceIsReplicated() {
+	s.removeAllServices()
+	defer s.removeAllServices()
+	s.createTestService("util-15", "-l com.df.cron.name=my-job -l com.df.cron=true --replicas 0")
+	services, _ := New("unix:///var/run/docker.sock")
+
+	_, err := services.RunJob("util-15", 1, 1)
+
+	s.Equal(ErrNotJob, err)
+}
+
+func (s *ServiceTestSuite) Test_RunJob_StartsNewIteration() {
+	services, _ := New("unix:///var/run/docker.sock")
+	if !services.JobModeSupported() {
+		s.T().Skip("the daemon does not support job mode services")
+	}
+	s.removeAllServices()
+	defer s.removeAllServices()
+	s.createTestService("util-16", "-l com.df.cron.name=my-job -l com.df.cron=true --mode replicated-job --replicas 0")
+
+	first, err := services.RunJob("util-16", 1, 1)
+	second, _ := services.RunJob("util-16", 1, 1)
+
+	s.NoError(err)
+	s.True(second > first)
+}
+
 // GetTasks
 
 func (s *ServiceTestSuite) Test_GetTasks_ReturnsFilteredTasks() {
@@ -1,16 +1,88 @@
 package main
 
 import (
+	"./cli"
+	"./cron"
 	"./server"
+	"flag"
 	"log"
+	"os"
+	"path/filepath"
+	"time"
 )
 
-// TODO: Test
+// main starts the server when called without arguments or with the server command.
+// Other commands, and every call through the dfcron link, are handled by the command-line client.
 func main() {
-	s, err := server.New("0.0.0.0", "8080", "unix:///var/run/docker.sock")
+	args := os.Args[1:]
+	if filepath.Base(os.Args[0]) != "dfcron" && (len(args) == 0 || args[0] == "server") {
+		if len(args) > 0 {
+			args = args[1:]
+		}
+		serve(args)
+		return
+	}
+	os.Exit(cli.Run(args, os.Stdout, os.Stderr))
+}
+
+// TODO: Test
+func serve(args []string) {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	ip := fs.String("ip", "0.0.0.0", "IP the API listens on")
+	port := fs.String("port", "8080", "Port the API listens on")
+	dockerHost := fs.String("docker-host", "unix:///var/run/docker.sock", "Address of the Docker daemon")
+	fs.Parse(args)
+	if len(os.Getenv("DOCKER_HOST")) == 0 {
+		// Services are created with the docker CLI, which must use the same daemon
+		os.Setenv("DOCKER_HOST", *dockerHost)
+	}
+	s, err := server.New(*ip, *port, *dockerHost)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
+	s.CertFile = os.Getenv("TLS_CERT_FILE")
+	s.KeyFile = os.Getenv("TLS_KEY_FILE")
+	s.ClientCAFile = os.Getenv("TLS_CLIENT_CA_FILE")
+	if s.Roles, err = server.ParseRoles(os.Getenv("TLS_CLIENT_ROLES")); err != nil {
+		log.Fatal(err.Error())
+	}
+	c := s.Cron.(*cron.Cron)
+	if c.Policy, err = cron.LoadPolicy(os.Getenv("POLICY_FILE")); err != nil {
+		log.Fatal(err.Error())
+	}
+	if s.Auditor, err = server.NewAuditor(os.Getenv("AUDIT_LOG")); err != nil {
+		log.Fatal(err.Error())
+	}
+	if c.History, err = cron.NewHistorian(os.Getenv("HISTORY_FILE")); err != nil {
+		log.Fatal(err.Error())
+	}
+	if c.Calendars, err = cron.NewCalendarStore(os.Getenv("CALENDARS_FILE")); err != nil {
+		log.Fatal(err.Error())
+	}
+	s.Calendars = c.Calendars
+	if err := c.SetExecutor(os.Getenv("EXECUTOR")); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := c.LoadMaintenance(os.Getenv("MAINTENANCE_FILE")); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := c.LoadLocalJobs(os.Getenv("LOCAL_JOBS_FILE")); err != nil {
+		log.Fatal(err.Error())
+	}
 	s.Cron.RescheduleJobs()
-	s.Execute()
+	if s.JobsFile = os.Getenv("JOBS_FILE"); len(s.JobsFile) > 0 {
+		if err := c.SyncJobsFile(s.JobsFile); err != nil {
+			log.Println("Could not synchronize jobs file:", err.Error())
+		}
+		if watch := os.Getenv("JOBS_FILE_WATCH"); len(watch) > 0 {
+			interval, err := time.ParseDuration(watch)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			c.WatchJobsFile(s.JobsFile, interval)
+		}
+	}
+	if err := s.Execute(); err != nil {
+		log.Fatal(err.Error())
+	}
 }
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type MaintenanceResponse struct {
+	Status      string
+	Message     string
+	Maintenance cron.Maintenance
+}
+
+// AdminPauseHandler stops running jobs. The optional until parameter sets the time at which jobs run again.
+func (s *Serve) AdminPauseHandler(w http.ResponseWriter, req *http.Request) {
+	response := MaintenanceResponse{Status: "OK"}
+	status := http.StatusOK
+	var until *time.Time
+	var err error
+	if value := req.URL.Query().Get("until"); len(value) > 0 {
+		var t time.Time
+		if t, err = time.Parse(time.RFC3339, value); err != nil {
+			err = fmt.Errorf("until must be an RFC3339 timestamp: %s", err.Error())
+		} else {
+			until = &t
+		}
+	}
+	if err == nil {
+		err = s.Cron.Pause(until, getCaller(req))
+	}
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusBadRequest
+	} else if until != nil {
+		response.Message = fmt.Sprintf("Docker Flow Cron is paused until %s", until.Format(time.RFC3339))
+	} else {
+		response.Message = "Docker Flow Cron is paused"
+	}
+	s.audit(req, "pause", "", nil, nil, response.Status, response.Message)
+	s.writeMaintenanceResponse(w, response, status)
+}
+
+// AdminResumeHandler runs jobs again after a pause.
+func (s *Serve) AdminResumeHandler(w http.ResponseWriter, req *http.Request) {
+	response := MaintenanceResponse{Status: "OK", Message: "Docker Flow Cron is resumed"}
+	status := http.StatusOK
+	if err := s.Cron.Resume(); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusConflict
+	}
+	s.audit(req, "resume", "", nil, nil, response.Status, response.Message)
+	s.writeMaintenanceResponse(w, response, status)
+}
+
+// AdminMaintenanceHandler returns whether jobs are paused.
+func (s *Serve) AdminMaintenanceHandler(w http.ResponseWriter, req *http.Request) {
+	s.writeMaintenanceResponse(w, MaintenanceResponse{Status: "OK"}, http.StatusOK)
+}
+
+func (s *Serve) writeMaintenanceResponse(w http.ResponseWriter, response MaintenanceResponse, status int) {
+	response.Maintenance = s.Cron.GetMaintenance()
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
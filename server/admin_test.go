@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminTestSuite struct {
+	suite.Suite
+}
+
+func TestAdminUnitTestSuite(t *testing.T) {
+	s := new(AdminTestSuite)
+	suite.Run(t, s)
+}
+
+// AdminPauseHandler
+
+func (s *AdminTestSuite) Test_AdminPauseHandler_PausesCron() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/admin/pause?until=2017-05-05T12:00:00Z", nil)
+	var actualUntil *time.Time
+	actualBy := ""
+	cMock := CronerMock{
+		PauseMock: func(until *time.Time, by string) error {
+			actualUntil = until
+			actualBy = by
+			return nil
+		},
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{Paused: true, Until: actualUntil}
+		},
+	}
+	actual := MaintenanceResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.AdminPauseHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("Docker Flow Cron is paused until 2017-05-05T12:00:00Z", actual.Message)
+	s.True(time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC).Equal(*actualUntil))
+	s.Equal("anonymous", actualBy)
+	s.True(actual.Maintenance.Paused)
+}
+
+func (s *AdminTestSuite) Test_AdminPauseHandler_ReturnsBadRequest_WhenUntilIsInvalid() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/admin/pause?until=tomorrow", nil)
+	paused := false
+	cMock := CronerMock{
+		PauseMock: func(until *time.Time, by string) error {
+			paused = true
+			return nil
+		},
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{}
+		},
+	}
+	actual := MaintenanceResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.AdminPauseHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusBadRequest, actualStatus)
+	s.False(paused)
+}
+
+func (s *AdminTestSuite) Test_AdminPauseHandler_RecordsAuditEntry() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/admin/pause", nil)
+	cMock := CronerMock{
+		PauseMock: func(until *time.Time, by string) error {
+			return nil
+		},
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{Paused: true}
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.AdminPauseHandler(s.responseWriter(nil, nil), req)
+	entries, _ := auditor.Query(AuditFilter{})
+
+	s.Len(entries, 1)
+	s.Equal("pause", entries[0].Action)
+}
+
+// AdminResumeHandler
+
+func (s *AdminTestSuite) Test_AdminResumeHandler_ReturnsConflict_WhenCronIsNotPaused() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/admin/resume", nil)
+	cMock := CronerMock{
+		ResumeMock: func() error {
+			return fmt.Errorf("Docker Flow Cron is not paused")
+		},
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{}
+		},
+	}
+	actual := MaintenanceResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.AdminResumeHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusConflict, actualStatus)
+}
+
+// AdminMaintenanceHandler
+
+func (s *AdminTestSuite) Test_AdminMaintenanceHandler_ReturnsMaintenanceState() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/admin/maintenance", nil)
+	cMock := CronerMock{
+		GetMaintenanceMock: func() cron.Maintenance {
+			return cron.Maintenance{Paused: true, PausedBy: "ops"}
+		},
+	}
+	actual := MaintenanceResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.AdminMaintenanceHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal(cron.Maintenance{Paused: true, PausedBy: "ops"}, actual.Maintenance)
+}
+
+// Util
+
+func (s *AdminTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"../cron"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const auditMemorySize = 1000
+
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry is a change made through the API. Changes of calendars set Calendar instead of Job.
+// SourceIP is the address of the connection.
+// ForwardedFor is the X-Forwarded-For header as sent by the client, so it is not trusted.
+type AuditEntry struct {
+	Time         time.Time         `json:"time"`
+	Action       string            `json:"action"`
+	Job          string            `json:"job"`
+	Calendar     string            `json:"calendar,omitempty"`
+	Caller       string            `json:"caller"`
+	SourceIP     string            `json:"sourceIp"`
+	ForwardedFor string            `json:"forwardedFor,omitempty"`
+	Before       *cron.JobData     `json:"before,omitempty"`
+	After        *cron.JobData     `json:"after,omitempty"`
+	Diff         map[string]Change `json:"diff,omitempty"`
+	Status       string            `json:"status"`
+	Message      string            `json:"message"`
+}
+
+type AuditFilter struct {
+	Job    string
+	Caller string
+	Action string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+type AuditResponse struct {
+	Status  string
+	Message string
+	Entries []AuditEntry
+}
+
+// Auditor stores entries describing API mutations.
+type Auditor interface {
+	Record(entry AuditEntry) error
+	Query(filter AuditFilter) ([]AuditEntry, error)
+}
+
+// NewAuditor returns an auditor that appends entries to a JSON-lines file,
+// sends them to the local syslog daemon when the value is `syslog`,
+// or keeps the latest entries in memory when the value is empty.
+var NewAuditor = func(value string) (Auditor, error) {
+	switch value {
+	case "":
+		return &memoryAuditor{}, nil
+	case "syslog":
+		writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, "docker-flow-cron")
+		if err != nil {
+			return nil, err
+		}
+		return &syslogAuditor{writer: writer}, nil
+	default:
+		file, err := os.OpenFile(value, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return &fileAuditor{path: value, file: file}, nil
+	}
+}
+
+type memoryAuditor struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+}
+
+func (a *memoryAuditor) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > auditMemorySize {
+		a.entries = a.entries[len(a.entries)-auditMemorySize:]
+	}
+	return nil
+}
+
+func (a *memoryAuditor) Query(filter AuditFilter) ([]AuditEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return filter.apply(a.entries), nil
+}
+
+// syslogAuditor sends entries to syslog and keeps the latest ones in memory so that they can be queried.
+type syslogAuditor struct {
+	memoryAuditor
+	writer *syslog.Writer
+}
+
+func (a *syslogAuditor) Record(entry AuditEntry) error {
+	js, _ := json.Marshal(entry)
+	if err := a.writer.Info(string(js)); err != nil {
+		return err
+	}
+	return a.memoryAuditor.Record(entry)
+}
+
+type fileAuditor struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func (a *fileAuditor) Record(entry AuditEntry) error {
+	js, _ := json.Marshal(entry)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err := a.file.Write(append(js, '\n'))
+	return err
+}
+
+func (a *fileAuditor) Query(filter AuditFilter) ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	file, err := os.Open(a.path)
+	if err != nil {
+		return []AuditEntry{}, err
+	}
+	defer file.Close()
+	entries := []AuditEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		entry := AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return filter.apply(entries), scanner.Err()
+}
+
+// apply returns matching entries, newest first.
+func (f AuditFilter) apply(entries []AuditEntry) []AuditEntry {
+	filtered := []AuditEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if (len(f.Job) > 0 && e.Job != f.Job) ||
+			(len(f.Caller) > 0 && e.Caller != f.Caller) ||
+			(len(f.Action) > 0 && e.Action != f.Action) ||
+			(!f.Since.IsZero() && e.Time.Before(f.Since)) ||
+			(!f.Until.IsZero() && e.Time.After(f.Until)) {
+			continue
+		}
+		filtered = append(filtered, e)
+		if f.Limit > 0 && len(filtered) >= f.Limit {
+			break
+		}
+	}
+	return filtered
+}
+
+func (s *Serve) AuditGetHandler(w http.ResponseWriter, req *http.Request) {
+	response := AuditResponse{Status: "OK", Entries: []AuditEntry{}}
+	query := req.URL.Query()
+	filter := AuditFilter{
+		Job:    query.Get("job"),
+		Caller: query.Get("caller"),
+		Action: query.Get("action"),
+	}
+	var err error
+	if since := query.Get("since"); len(since) > 0 && err == nil {
+		filter.Since, err = time.Parse(time.RFC3339, since)
+	}
+	if until := query.Get("until"); len(until) > 0 && err == nil {
+		filter.Until, err = time.Parse(time.RFC3339, until)
+	}
+	if limit := query.Get("limit"); len(limit) > 0 && err == nil {
+		filter.Limit, err = strconv.Atoi(limit)
+	}
+	httpWriterSetContentType(w, "application/json")
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		w.WriteHeader(http.StatusBadRequest)
+	} else if s.Auditor == nil {
+		response.Message = "Auditing is disabled"
+	} else if entries, err := s.Auditor.Query(filter); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		response.Entries = entries
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+// getJobBeforeChange returns the current definition of a job or nil if it does not exist.
+// It is used only when auditing is enabled.
+func (s *Serve) getJobBeforeChange(jobName string) *cron.JobData {
+	if s.Auditor == nil {
+		return nil
+	}
+	jobs, err := s.Cron.GetJobs()
+	if err != nil {
+		return nil
+	}
+	if job, ok := jobs[jobName]; ok {
+		job.State = nil
+		return &job
+	}
+	return nil
+}
+
+func (s *Serve) audit(req *http.Request, action, jobName string, before, after *cron.JobData, status, message string) {
+	s.record(req, AuditEntry{
+		Action:  action,
+		Job:     jobName,
+		Before:  before,
+		After:   after,
+		Diff:    diffJobs(before, after),
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (s *Serve) auditCalendar(req *http.Request, action, calendarName, status, message string) {
+	s.record(req, AuditEntry{Action: action, Calendar: calendarName, Status: status, Message: message})
+}
+
+// record completes the entry with the time and the origin of the request and records it.
+func (s *Serve) record(req *http.Request, entry AuditEntry) {
+	if s.Auditor == nil {
+		return
+	}
+	entry.Time = time.Now().UTC()
+	entry.Caller = getCaller(req)
+	entry.SourceIP = getSourceIP(req)
+	entry.ForwardedFor = req.Header.Get("X-Forwarded-For")
+	if err := s.Auditor.Record(entry); err != nil {
+		fmt.Println("Could not record audit entry:", err.Error())
+	}
+}
+
+// getCaller returns the common name of the client certificate or `anonymous` if there is none.
+func getCaller(req *http.Request) string {
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		return req.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return "anonymous"
+}
+
+// getSourceIP returns the address of the connection. Headers set by the client, e.g. X-Forwarded-For, are not used
+// since anyone can set them.
+func getSourceIP(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+// diffJobs returns the JSON fields that differ between two job definitions.
+func diffJobs(before, after *cron.JobData) map[string]Change {
+	toMap := func(data *cron.JobData) map[string]interface{} {
+		fields := map[string]interface{}{}
+		if data != nil {
+			js, _ := json.Marshal(data)
+			json.Unmarshal(js, &fields)
+		}
+		return fields
+	}
+	beforeFields := toMap(before)
+	afterFields := toMap(after)
+	diff := map[string]Change{}
+	for key, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[key], value) {
+			diff[key] = Change{Before: beforeFields[key], After: value}
+		}
+	}
+	for key, value := range beforeFields {
+		if _, ok := afterFields[key]; !ok {
+			diff[key] = Change{Before: value}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+}
+
+func TestAuditUnitTestSuite(t *testing.T) {
+	s := new(AuditTestSuite)
+	suite.Run(t, s)
+}
+
+// NewAuditor
+
+func (s *AuditTestSuite) Test_NewAuditor_ReturnsMemoryAuditor_WhenValueIsEmpty() {
+	auditor, err := NewAuditor("")
+
+	s.NoError(err)
+	s.IsType(&memoryAuditor{}, auditor)
+}
+
+func (s *AuditTestSuite) Test_NewAuditor_ReturnsFileAuditor_WhenValueIsPath() {
+	file, _ := ioutil.TempFile("", "audit")
+	defer os.Remove(file.Name())
+
+	auditor, err := NewAuditor(file.Name())
+
+	s.NoError(err)
+	s.IsType(&fileAuditor{}, auditor)
+}
+
+// fileAuditor
+
+func (s *AuditTestSuite) Test_FileAuditor_AppendsJSONLines() {
+	file, _ := ioutil.TempFile("", "audit")
+	defer os.Remove(file.Name())
+	auditor, _ := NewAuditor(file.Name())
+
+	auditor.Record(AuditEntry{Action: "create", Job: "my-job"})
+	auditor.Record(AuditEntry{Action: "delete", Job: "my-job"})
+
+	content, _ := ioutil.ReadFile(file.Name())
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	s.Len(lines, 2)
+	s.Contains(lines[1], `"action":"delete"`)
+}
+
+func (s *AuditTestSuite) Test_FileAuditor_QueryReturnsFilteredEntriesNewestFirst() {
+	file, _ := ioutil.TempFile("", "audit")
+	defer os.Remove(file.Name())
+	auditor, _ := NewAuditor(file.Name())
+	auditor.Record(AuditEntry{Action: "create", Job: "my-job"})
+	auditor.Record(AuditEntry{Action: "create", Job: "other-job"})
+	auditor.Record(AuditEntry{Action: "update", Job: "my-job"})
+
+	actual, err := auditor.Query(AuditFilter{Job: "my-job"})
+
+	s.NoError(err)
+	s.Len(actual, 2)
+	s.Equal("update", actual[0].Action)
+	s.Equal("create", actual[1].Action)
+}
+
+// AuditFilter
+
+func (s *AuditTestSuite) Test_AuditFilter_FiltersByTimeAndLimit() {
+	now := time.Now()
+	entries := []AuditEntry{
+		{Job: "1", Time: now.Add(-3 * time.Hour)},
+		{Job: "2", Time: now.Add(-2 * time.Hour)},
+		{Job: "3", Time: now.Add(-1 * time.Hour)},
+		{Job: "4", Time: now},
+	}
+
+	actual := AuditFilter{Since: now.Add(-150 * time.Minute), Until: now.Add(-time.Minute), Limit: 1}.apply(entries)
+
+	s.Len(actual, 1)
+	s.Equal("3", actual[0].Job)
+}
+
+// JobPutHandler
+
+func (s *AuditTestSuite) Test_JobPutHandler_RecordsUpdate() {
+	muxVarsOrig := muxVars
+	defer func() { muxVars = muxVarsOrig }()
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+	before := cron.JobData{Name: "my-job", Image: "alpine", Schedule: "@yearly"}
+	js, _ := json.Marshal(cron.JobData{Image: "alpine", Schedule: "@daily"})
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/job/my-job", strings.NewReader(string(js)))
+	req.RemoteAddr = "10.0.0.2:54321"
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ci-bot"}}},
+	}
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			return nil
+		},
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{"my-job": before}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobPutHandler(s.responseWriter(nil), req)
+
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("update", entries[0].Action)
+	s.Equal("my-job", entries[0].Job)
+	s.Equal("ci-bot", entries[0].Caller)
+	s.Equal("10.0.0.2", entries[0].SourceIP)
+	s.Equal("OK", entries[0].Status)
+	s.Equal(&before, entries[0].Before)
+	s.Equal(map[string]Change{"schedule": {Before: "@yearly", After: "@daily"}}, entries[0].Diff)
+}
+
+func (s *AuditTestSuite) Test_JobPutHandler_RecordsFailedCreate() {
+	js, _ := json.Marshal(cron.JobData{Image: "alpine", Schedule: "@daily"})
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/job/my-job", strings.NewReader(string(js)))
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			return fmt.Errorf("This is an error")
+		},
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobPutHandler(s.responseWriter(nil), req)
+
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("create", entries[0].Action)
+	s.Equal("anonymous", entries[0].Caller)
+	s.Equal("NOK", entries[0].Status)
+	s.Equal("This is an error", entries[0].Message)
+	s.Nil(entries[0].Before)
+}
+
+// JobDeleteHandler
+
+func (s *AuditTestSuite) Test_JobDeleteHandler_RecordsDelete() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/remove?serviceName=my-job", nil)
+	req.RemoteAddr = "10.0.0.1:54321"
+	req.Header.Set("X-Forwarded-For", "192.168.1.1, 10.0.0.1")
+	before := cron.JobData{Name: "my-job", Image: "alpine"}
+	cMock := CronerMock{
+		RemoveJobMock: func(jobName string) error {
+			return nil
+		},
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{"my-job": before}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobDeleteHandler(s.responseWriter(nil), req)
+
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("delete", entries[0].Action)
+	s.Equal("10.0.0.1", entries[0].SourceIP)
+	s.Equal("192.168.1.1, 10.0.0.1", entries[0].ForwardedFor)
+	s.Nil(entries[0].After)
+	s.Equal(Change{Before: "alpine"}, entries[0].Diff["image"])
+}
+
+// AuditGetHandler
+
+func (s *AuditTestSuite) Test_AuditGetHandler_ReturnsEntries() {
+	auditor, _ := NewAuditor("")
+	auditor.Record(AuditEntry{Action: "create", Job: "my-job"})
+	auditor.Record(AuditEntry{Action: "delete", Job: "other-job"})
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/audit?action=delete", nil)
+	actual := AuditResponse{}
+
+	srv := Serve{Auditor: auditor}
+	srv.AuditGetHandler(s.responseWriter(&actual), req)
+
+	s.Equal("OK", actual.Status)
+	s.Len(actual.Entries, 1)
+	s.Equal("other-job", actual.Entries[0].Job)
+}
+
+func (s *AuditTestSuite) Test_AuditGetHandler_ReturnsBadRequest_WhenSinceIsInvalid() {
+	auditor, _ := NewAuditor("")
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/audit?since=yesterday", nil)
+	actualStatus := 0
+	rwMock := s.responseWriter(nil)
+	rwMock.WriteHeaderMock = func(header int) {
+		actualStatus = header
+	}
+
+	srv := Serve{Auditor: auditor}
+	srv.AuditGetHandler(rwMock, req)
+
+	s.Equal(400, actualStatus)
+}
+
+// Util
+
+func (s *AuditTestSuite) responseWriter(actual interface{}) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
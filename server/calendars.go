@@ -0,0 +1,148 @@
+package server
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type CalendarsResponse struct {
+	Status    string
+	Message   string
+	Calendars []cron.Calendar
+	Warnings  []string `json:",omitempty"`
+}
+
+// CalendarsGetHandler returns all calendars.
+func (s *Serve) CalendarsGetHandler(w http.ResponseWriter, req *http.Request) {
+	response := CalendarsResponse{Status: "OK", Calendars: []cron.Calendar{}}
+	if s.Calendars != nil {
+		response.Calendars = s.Calendars.List()
+	}
+	s.writeCalendarsResponse(w, response, http.StatusOK)
+}
+
+// CalendarGetHandler returns a single calendar.
+func (s *Serve) CalendarGetHandler(w http.ResponseWriter, req *http.Request) {
+	name := muxVars(req)["calendarName"]
+	response := CalendarsResponse{Status: "OK", Calendars: []cron.Calendar{}}
+	status := http.StatusOK
+	if calendar, ok := s.getCalendar(name); ok {
+		response.Calendars = append(response.Calendars, calendar)
+	} else {
+		response.Status = "NOK"
+		response.Message = fmt.Sprintf("calendar %s does not exist", name)
+		status = http.StatusNotFound
+	}
+	s.writeCalendarsResponse(w, response, status)
+}
+
+// CalendarPutHandler creates or replaces a calendar.
+// With the format parameter set to ical, the ranges of the calendar are replaced with the events of the iCalendar file in the body.
+func (s *Serve) CalendarPutHandler(w http.ResponseWriter, req *http.Request) {
+	name := muxVars(req)["calendarName"]
+	response := CalendarsResponse{Status: "OK", Calendars: []cron.Calendar{}}
+	status := http.StatusOK
+	content := []byte{}
+	if req.Body != nil {
+		defer func() { req.Body.Close() }()
+		content, _ = ioutil.ReadAll(req.Body)
+	}
+	calendar := cron.Calendar{}
+	action := "create-calendar"
+	if _, ok := s.getCalendar(name); ok {
+		action = "update-calendar"
+	}
+	var err error
+	if s.Calendars == nil {
+		err = fmt.Errorf("calendars are not supported")
+	} else if req.URL.Query().Get("format") == "ical" {
+		calendar, _ = s.Calendars.Get(name)
+		if tz := req.URL.Query().Get("tz"); len(tz) > 0 {
+			calendar.TimeZone = tz
+		}
+		calendar.Ranges, response.Warnings, err = cron.ParseICal(content)
+	} else {
+		err = json.Unmarshal(content, &calendar)
+	}
+	if err == nil {
+		calendar.Name = name
+		err = s.Calendars.Put(calendar)
+	}
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusBadRequest
+	} else {
+		response.Message = fmt.Sprintf("Calendar %s has been saved", name)
+		response.Calendars = append(response.Calendars, calendar)
+	}
+	s.auditCalendar(req, action, name, response.Status, response.Message)
+	s.writeCalendarsResponse(w, response, status)
+}
+
+// CalendarDeleteHandler removes a calendar. Calendars excluded by jobs cannot be removed.
+func (s *Serve) CalendarDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	name := muxVars(req)["calendarName"]
+	response := CalendarsResponse{Status: "OK", Calendars: []cron.Calendar{}}
+	status := http.StatusOK
+	if _, ok := s.getCalendar(name); !ok {
+		response.Status = "NOK"
+		response.Message = fmt.Sprintf("calendar %s does not exist", name)
+		status = http.StatusNotFound
+	} else if jobs, err := s.jobsExcluding(name); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusInternalServerError
+	} else if len(jobs) > 0 {
+		response.Status = "NOK"
+		response.Message = fmt.Sprintf("calendar %s is used by jobs %s", name, strings.Join(jobs, ", "))
+		status = http.StatusConflict
+	} else if err := s.Calendars.Remove(name); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusInternalServerError
+	} else {
+		response.Message = fmt.Sprintf("%s was deleted", name)
+	}
+	s.auditCalendar(req, "delete-calendar", name, response.Status, response.Message)
+	s.writeCalendarsResponse(w, response, status)
+}
+
+func (s *Serve) getCalendar(name string) (cron.Calendar, bool) {
+	if s.Calendars == nil {
+		return cron.Calendar{}, false
+	}
+	return s.Calendars.Get(name)
+}
+
+// jobsExcluding returns the names of the jobs that exclude the calendar.
+func (s *Serve) jobsExcluding(name string) ([]string, error) {
+	names := []string{}
+	jobs, err := s.Cron.GetJobs()
+	if err != nil {
+		return names, err
+	}
+	for _, job := range jobs {
+		for _, calendar := range job.ExcludeCalendars {
+			if calendar == name {
+				names = append(names, job.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Serve) writeCalendarsResponse(w http.ResponseWriter, response CalendarsResponse, status int) {
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
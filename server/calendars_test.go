@@ -0,0 +1,218 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarsTestSuite struct {
+	suite.Suite
+	muxVarsOrig func(r *http.Request) map[string]string
+}
+
+func TestCalendarsUnitTestSuite(t *testing.T) {
+	s := new(CalendarsTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *CalendarsTestSuite) SetupTest() {
+	s.muxVarsOrig = muxVars
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"calendarName": "holidays"}
+	}
+}
+
+func (s *CalendarsTestSuite) TearDownTest() {
+	muxVars = s.muxVarsOrig
+}
+
+// CalendarPutHandler
+
+func (s *CalendarsTestSuite) Test_CalendarPutHandler_SavesCalendar() {
+	body := `{"weekdays": ["Sunday"], "ranges": [{"name": "Christmas", "from": "2017-12-25", "to": "2017-12-26"}]}`
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/calendars/holidays", strings.NewReader(body))
+	store, _ := cron.NewCalendarStore("")
+	actual := CalendarsResponse{}
+
+	srv := Serve{Calendars: store}
+	srv.CalendarPutHandler(s.responseWriter(&actual, nil), req)
+	stored, ok := store.Get("holidays")
+
+	s.Equal("OK", actual.Status)
+	s.True(ok)
+	s.Equal([]string{"Sunday"}, stored.Weekdays)
+	s.Equal("Christmas", stored.Ranges[0].Name)
+}
+
+func (s *CalendarsTestSuite) Test_CalendarPutHandler_RecordsAuditEntry() {
+	store, _ := cron.NewCalendarStore("")
+	auditor, _ := NewAuditor("")
+	srv := Serve{Calendars: store, Auditor: auditor}
+
+	for _, body := range []string{`{"weekdays": ["Sunday"]}`, `{"weekdays": ["Saturday"]}`, `{"weekdays": ["Someday"]}`} {
+		req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/calendars/holidays", strings.NewReader(body))
+		srv.CalendarPutHandler(s.responseWriter(&CalendarsResponse{}, nil), req)
+	}
+
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Require().Len(entries, 3)
+	s.Equal("update-calendar", entries[0].Action)
+	s.Equal("NOK", entries[0].Status)
+	s.Equal("update-calendar", entries[1].Action)
+	s.Equal("OK", entries[1].Status)
+	s.Equal("create-calendar", entries[2].Action)
+	s.Equal("holidays", entries[2].Calendar)
+	s.Equal("anonymous", entries[2].Caller)
+}
+
+func (s *CalendarsTestSuite) Test_CalendarPutHandler_ImportsICal() {
+	body := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20171225\nSUMMARY:Christmas\nEND:VEVENT\nEND:VCALENDAR\n"
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/calendars/holidays?format=ical&tz=Europe/Berlin", strings.NewReader(body))
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "holidays", Weekdays: []string{"Sunday"}})
+	actual := CalendarsResponse{}
+
+	srv := Serve{Calendars: store}
+	srv.CalendarPutHandler(s.responseWriter(&actual, nil), req)
+	stored, _ := store.Get("holidays")
+
+	s.Equal("OK", actual.Status)
+	s.Equal(cron.Calendar{
+		Name:     "holidays",
+		TimeZone: "Europe/Berlin",
+		Weekdays: []string{"Sunday"},
+		Ranges:   []cron.DateRange{{Name: "Christmas", From: "2017-12-25"}},
+	}, stored)
+}
+
+func (s *CalendarsTestSuite) Test_CalendarPutHandler_ReturnsBadRequest_WhenCalendarIsInvalid() {
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/calendars/holidays", strings.NewReader(`{"weekdays": ["Caturday"]}`))
+	store, _ := cron.NewCalendarStore("")
+	actual := CalendarsResponse{}
+	actualStatus := 0
+
+	srv := Serve{Calendars: store}
+	srv.CalendarPutHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusBadRequest, actualStatus)
+	s.Empty(store.List())
+}
+
+// CalendarGetHandler
+
+func (s *CalendarsTestSuite) Test_CalendarGetHandler_ReturnsNotFound_WhenCalendarDoesNotExist() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/calendars/holidays", nil)
+	store, _ := cron.NewCalendarStore("")
+	actualStatus := 0
+
+	srv := Serve{Calendars: store}
+	srv.CalendarGetHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(http.StatusNotFound, actualStatus)
+}
+
+// CalendarsGetHandler
+
+func (s *CalendarsTestSuite) Test_CalendarsGetHandler_ReturnsCalendars() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/calendars", nil)
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "weekend", Weekdays: []string{"Saturday", "Sunday"}})
+	store.Put(cron.Calendar{Name: "holidays"})
+	actual := CalendarsResponse{}
+
+	srv := Serve{Calendars: store}
+	srv.CalendarsGetHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal([]cron.Calendar{{Name: "holidays"}, {Name: "weekend", Weekdays: []string{"Saturday", "Sunday"}}}, actual.Calendars)
+}
+
+// CalendarDeleteHandler
+
+func (s *CalendarsTestSuite) Test_CalendarDeleteHandler_RemovesCalendar() {
+	req, _ := http.NewRequest("DELETE", "/v1/docker-flow-cron/calendars/holidays", nil)
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "holidays"})
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{"my-job": {Name: "my-job"}}, nil
+		},
+	}
+	actual := CalendarsResponse{}
+
+	srv := Serve{Cron: cMock, Calendars: store}
+	srv.CalendarDeleteHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Empty(store.List())
+}
+
+func (s *CalendarsTestSuite) Test_CalendarDeleteHandler_RecordsAuditEntry() {
+	req, _ := http.NewRequest("DELETE", "/v1/docker-flow-cron/calendars/holidays", nil)
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "holidays"})
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Calendars: store, Auditor: auditor}
+	srv.CalendarDeleteHandler(s.responseWriter(&CalendarsResponse{}, nil), req)
+
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Require().Len(entries, 1)
+	s.Equal("delete-calendar", entries[0].Action)
+	s.Equal("holidays", entries[0].Calendar)
+	s.Equal("OK", entries[0].Status)
+}
+
+func (s *CalendarsTestSuite) Test_CalendarDeleteHandler_ReturnsConflict_WhenJobsExcludeCalendar() {
+	req, _ := http.NewRequest("DELETE", "/v1/docker-flow-cron/calendars/holidays", nil)
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "holidays"})
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{
+				"b-job": {Name: "b-job", ExcludeCalendars: []string{"weekend", "holidays"}},
+				"a-job": {Name: "a-job", ExcludeCalendars: []string{"holidays"}},
+			}, nil
+		},
+	}
+	actual := CalendarsResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock, Calendars: store}
+	srv.CalendarDeleteHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal(http.StatusConflict, actualStatus)
+	s.Equal("calendar holidays is used by jobs a-job, b-job", actual.Message)
+	s.Len(store.List(), 1)
+}
+
+// Util
+
+func (s *CalendarsTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"../cron"
+	"fmt"
+	"net/http"
+)
+
+type RunResponse struct {
+	Status  string
+	Message string
+	Run     cron.Run
+}
+
+// JobCancelHandler stops a running execution of a job and records it as cancelled by the caller.
+func (s *Serve) JobCancelHandler(w http.ResponseWriter, req *http.Request) {
+	vars := muxVars(req)
+	jobName := vars["jobName"]
+	runID := vars["id"]
+	response := RunResponse{Status: "OK"}
+	status := http.StatusOK
+	run, err := s.Cron.CancelRun(jobName, runID, getCaller(req))
+	response.Run = run
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = errorStatus(err)
+	} else {
+		response.Message = fmt.Sprintf("Run %s of job %s was cancelled", runID, jobName)
+	}
+	s.audit(req, "cancel", jobName, nil, nil, response.Status, response.Message)
+	writeJSON(w, status, response)
+}
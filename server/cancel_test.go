@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type CancelTestSuite struct {
+	suite.Suite
+	muxVarsOrig func(r *http.Request) map[string]string
+}
+
+func TestCancelUnitTestSuite(t *testing.T) {
+	s := new(CancelTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *CancelTestSuite) SetupTest() {
+	s.muxVarsOrig = muxVars
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job", "id": "123"}
+	}
+}
+
+func (s *CancelTestSuite) TearDownTest() {
+	muxVars = s.muxVarsOrig
+}
+
+// JobCancelHandler
+
+func (s *CancelTestSuite) Test_JobCancelHandler_CancelsRun() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/executions/123/cancel", nil)
+	actualJob, actualID, actualBy := "", "", ""
+	cMock := CronerMock{
+		CancelRunMock: func(jobName, runID, by string) (cron.Run, error) {
+			actualJob, actualID, actualBy = jobName, runID, by
+			return cron.Run{ID: runID, Job: jobName, Status: cron.RunCancelled, CancelledBy: by}, nil
+		},
+	}
+	actual := RunResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobCancelHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("Run 123 of job my-job was cancelled", actual.Message)
+	s.Equal("my-job", actualJob)
+	s.Equal("123", actualID)
+	s.Equal("anonymous", actualBy)
+	s.Equal(cron.RunCancelled, actual.Run.Status)
+	s.Equal("anonymous", actual.Run.CancelledBy)
+}
+
+func (s *CancelTestSuite) Test_JobCancelHandler_ReturnsStatusMatchingError() {
+	errs := map[int]error{
+		http.StatusNotFound:            &cron.NotFoundError{Message: "not found"},
+		http.StatusConflict:            &cron.ConflictError{Message: "not running"},
+		http.StatusInternalServerError: fmt.Errorf("This is an error"),
+	}
+	for expected, err := range errs {
+		req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/executions/123/cancel", nil)
+		returned := err
+		cMock := CronerMock{
+			CancelRunMock: func(jobName, runID, by string) (cron.Run, error) {
+				return cron.Run{}, returned
+			},
+		}
+		actual := RunResponse{}
+		actualStatus := 0
+
+		srv := Serve{Cron: cMock}
+		srv.JobCancelHandler(s.responseWriter(&actual, &actualStatus), req)
+
+		s.Equal("NOK", actual.Status)
+		s.Equal(err.Error(), actual.Message)
+		s.Equal(expected, actualStatus)
+	}
+}
+
+func (s *CancelTestSuite) Test_JobCancelHandler_RecordsAuditEntry() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/executions/123/cancel", nil)
+	cMock := CronerMock{
+		CancelRunMock: func(jobName, runID, by string) (cron.Run, error) {
+			return cron.Run{}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobCancelHandler(s.responseWriter(nil, nil), req)
+	entries, _ := auditor.Query(AuditFilter{})
+
+	s.Len(entries, 1)
+	s.Equal("cancel", entries[0].Action)
+	s.Equal("my-job", entries[0].Job)
+}
+
+// Util
+
+func (s *CancelTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
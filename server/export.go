@@ -0,0 +1,52 @@
+package server
+
+import (
+	"../cron"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// JobsExportHandler converts all jobs into another format.
+// Problems found during the conversion are written as comments at the top of the output.
+func (s *Serve) JobsExportHandler(w http.ResponseWriter, req *http.Request) {
+	response := Response{Status: "OK"}
+	status := http.StatusOK
+	format := req.URL.Query().Get("format")
+	jobs, err := s.Cron.GetJobs()
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusInternalServerError
+	} else if format != "k8s" {
+		response.Status = "NOK"
+		response.Message = fmt.Sprintf("format %s is not supported", format)
+		status = http.StatusBadRequest
+	}
+	if status != http.StatusOK {
+		httpWriterSetContentType(w, "application/json")
+		w.WriteHeader(status)
+		js, _ := json.Marshal(response)
+		w.Write(js)
+		return
+	}
+	names := []string{}
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := []cron.JobData{}
+	for _, name := range names {
+		list = append(list, jobs[name])
+	}
+	manifests, warnings := cron.ExportCronJobs(list)
+	out := bytes.Buffer{}
+	for _, warning := range warnings {
+		out.WriteString(fmt.Sprintf("# warning: %s\n", warning))
+	}
+	out.Write(manifests)
+	httpWriterSetContentType(w, "application/yaml")
+	w.Write(out.Bytes())
+}
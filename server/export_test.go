@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+}
+
+func TestExportUnitTestSuite(t *testing.T) {
+	s := new(ExportTestSuite)
+	suite.Run(t, s)
+}
+
+// JobsExportHandler
+
+func (s *ExportTestSuite) Test_JobsExportHandler_ReturnsKubernetesManifests() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/jobs/export?format=k8s", nil)
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{
+				"b-job": {Name: "b-job", Image: "alpine", Schedule: "@daily", Args: []string{"--network my-net"}},
+				"a-job": {Name: "a-job", Image: "alpine", Schedule: "@hourly"},
+			}, nil
+		},
+	}
+	actualContentType := ""
+	httpWriterSetContentTypeOrig := httpWriterSetContentType
+	defer func() { httpWriterSetContentType = httpWriterSetContentTypeOrig }()
+	httpWriterSetContentType = func(w http.ResponseWriter, value string) {
+		actualContentType = value
+	}
+	actual := ""
+
+	srv := Serve{Cron: cMock}
+	srv.JobsExportHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("application/yaml", actualContentType)
+	s.True(strings.HasPrefix(actual, "# warning: b-job: argument --network cannot be translated\napiVersion: batch/v1\n"))
+	s.True(strings.Index(actual, "name: a-job") < strings.Index(actual, "name: b-job"))
+	s.Contains(actual, "---\n")
+}
+
+func (s *ExportTestSuite) Test_JobsExportHandler_ReturnsBadRequest_WhenFormatIsNotSupported() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/jobs/export?format=unknown", nil)
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{}, nil
+		},
+	}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsExportHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(400, actualStatus)
+}
+
+func (s *ExportTestSuite) Test_JobsExportHandler_ReturnsInternalServerError_WhenGetJobsFails() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/jobs/export?format=k8s", nil)
+	cMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{}, fmt.Errorf("This is an error")
+		},
+	}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsExportHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(500, actualStatus)
+}
+
+// Util
+
+func (s *ExportTestSuite) responseWriter(actual *string, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				*actual = string(content)
+			}
+			return 0, nil
+		},
+	}
+}
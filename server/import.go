@@ -0,0 +1,91 @@
+package server
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+type ImportResponse struct {
+	Status   string
+	Message  string
+	Jobs     []cron.JobData
+	Warnings []string
+}
+
+// JobsImportHandler converts jobs from other formats and schedules them.
+// When the dryRun parameter is set to true, it only returns the jobs that would be created.
+func (s *Serve) JobsImportHandler(w http.ResponseWriter, req *http.Request) {
+	response := ImportResponse{Status: "OK", Jobs: []cron.JobData{}, Warnings: []string{}}
+	status := http.StatusOK
+	query := req.URL.Query()
+	content := []byte{}
+	if req.Body != nil {
+		defer func() { req.Body.Close() }()
+		content, _ = ioutil.ReadAll(req.Body)
+	}
+	var imported cron.Import
+	var err error
+	switch query.Get("format") {
+	case "crontab":
+		imported, err = cron.ParseCrontab(string(content), cron.CrontabOptions{
+			Image:  query.Get("image"),
+			Prefix: query.Get("prefix"),
+			System: query.Get("system") == "true",
+		})
+	case "k8s":
+		imported, err = cron.ParseCronJobs(content)
+	default:
+		err = fmt.Errorf("format %s is not supported", query.Get("format"))
+	}
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusBadRequest
+	} else {
+		response.Jobs = imported.Jobs
+		response.Warnings = imported.Warnings
+		if query.Get("dryRun") == "true" {
+			response.Message = fmt.Sprintf("%d jobs would be created", len(imported.Jobs))
+		} else {
+			status = s.importJobs(req, &response)
+		}
+	}
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+func (s *Serve) importJobs(req *http.Request, response *ImportResponse) int {
+	status := http.StatusOK
+	failed := []string{}
+	for _, job := range response.Jobs {
+		job := job
+		result := "OK"
+		message := fmt.Sprintf("Job %s has been scheduled", job.Name)
+		if err := s.Cron.AddJob(job); err != nil {
+			if _, ok := err.(*cron.PolicyError); ok && status == http.StatusOK {
+				status = http.StatusForbidden
+			} else {
+				status = http.StatusInternalServerError
+			}
+			result = "NOK"
+			message = err.Error()
+			failed = append(failed, fmt.Sprintf("%s: %s", job.Name, message))
+		}
+		s.audit(req, "create", job.Name, nil, &job, result, message)
+	}
+	if len(failed) > 0 {
+		response.Status = "NOK"
+		response.Message = fmt.Sprintf("Could not create jobs: %s", strings.Join(failed, "; "))
+	} else {
+		response.Message = fmt.Sprintf("%d jobs have been scheduled", len(response.Jobs))
+	}
+	return status
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportTestSuite struct {
+	suite.Suite
+}
+
+func TestImportUnitTestSuite(t *testing.T) {
+	s := new(ImportTestSuite)
+	suite.Run(t, s)
+}
+
+// JobsImportHandler
+
+func (s *ImportTestSuite) Test_JobsImportHandler_SchedulesCrontabJobs() {
+	req, _ := http.NewRequest(
+		"POST",
+		"/v1/docker-flow-cron/jobs/import?format=crontab&image=alpine&prefix=ops",
+		strings.NewReader("0 2 * * * backup\n"),
+	)
+	actualJobs := []cron.JobData{}
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			actualJobs = append(actualJobs, data)
+			return nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+	actual := ImportResponse{}
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobsImportHandler(s.responseWriter(&actual, nil), req)
+
+	expected := []cron.JobData{{
+		Name:     "ops-backup-1",
+		Image:    "alpine",
+		Command:  "sh -c 'backup'",
+		Schedule: "0 0 2 * * *",
+		Args:     []string{},
+	}}
+	s.Equal(expected, actualJobs)
+	s.Equal("OK", actual.Status)
+	s.Equal(expected, actual.Jobs)
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("create", entries[0].Action)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_DoesNotScheduleJobs_WhenDryRun() {
+	req, _ := http.NewRequest(
+		"POST",
+		"/v1/docker-flow-cron/jobs/import?format=crontab&image=alpine&dryRun=true",
+		strings.NewReader("@reboot agent\n0 2 * * * backup\n"),
+	)
+	called := false
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			called = true
+			return nil
+		},
+	}
+	actual := ImportResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobsImportHandler(s.responseWriter(&actual, nil), req)
+
+	s.False(called)
+	s.Equal("OK", actual.Status)
+	s.Len(actual.Jobs, 1)
+	s.Len(actual.Warnings, 1)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_SchedulesKubernetesCronJobs() {
+	manifest := `kind: CronJob
+metadata:
+  name: my-job
+spec:
+  schedule: "0 2 * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: main
+            image: alpine
+`
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/import?format=k8s", strings.NewReader(manifest))
+	actualJobs := []cron.JobData{}
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			actualJobs = append(actualJobs, data)
+			return nil
+		},
+	}
+	actual := ImportResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobsImportHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal([]cron.JobData{{
+		Name:     "my-job",
+		Image:    "alpine",
+		Schedule: "0 0 2 * * *",
+		Args:     []string{"--restart-condition none"},
+	}}, actualJobs)
+	s.Equal("OK", actual.Status)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_ReturnsBadRequest_WhenFormatIsNotSupported() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/import?format=unknown", strings.NewReader(""))
+	actual := ImportResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: CronerMock{}}
+	srv.JobsImportHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal(400, actualStatus)
+	s.Equal("NOK", actual.Status)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_ReturnsBadRequest_WhenCrontabIsInvalid() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/import?format=crontab", strings.NewReader("0 2 * * * backup"))
+	actualStatus := 0
+
+	srv := Serve{Cron: CronerMock{}}
+	srv.JobsImportHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(400, actualStatus)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_ReturnsForbidden_WhenPolicyIsViolated() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/import?format=crontab&image=alpine", strings.NewReader("0 2 * * * backup"))
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			return &cron.PolicyError{Rule: "allowedImages", Message: "image alpine is not allowed"}
+		},
+	}
+	actual := ImportResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsImportHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal(403, actualStatus)
+	s.Equal("NOK", actual.Status)
+}
+
+func (s *ImportTestSuite) Test_JobsImportHandler_ReturnsInternalServerError_WhenAddJobFails() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/import?format=crontab&image=alpine", strings.NewReader("0 2 * * * backup"))
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			return fmt.Errorf("This is an error")
+		},
+	}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsImportHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(500, actualStatus)
+}
+
+// Util
+
+func (s *ImportTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type PlanResponse struct {
+	Status  string
+	Message string
+	Plan    cron.Plan
+}
+
+// JobsPlanHandler returns the changes required to make the scheduled jobs match the definitions.
+// Definitions are read from the request body or, if the body is empty, from the jobs file.
+func (s *Serve) JobsPlanHandler(w http.ResponseWriter, req *http.Request) {
+	response, status := s.plan(req)
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+// JobsApplyHandler creates, updates and deletes jobs so that they match the definitions.
+func (s *Serve) JobsApplyHandler(w http.ResponseWriter, req *http.Request) {
+	response, status := s.plan(req)
+	if status == http.StatusOK {
+		before := map[string]cron.JobData{}
+		if s.Auditor != nil {
+			before, _ = s.Cron.GetJobs()
+		}
+		if err := s.Cron.Apply(response.Plan); err != nil {
+			response.Status = "NOK"
+			response.Message = err.Error()
+			status = http.StatusInternalServerError
+		} else {
+			response.Message = fmt.Sprintf("%d changes were applied", len(response.Plan.Changes))
+		}
+		for _, change := range response.Plan.Changes {
+			var beforeJob *cron.JobData
+			if job, ok := before[change.Name]; ok {
+				beforeJob = &job
+			}
+			s.audit(req, change.Action, change.Name, beforeJob, change.Job, response.Status, response.Message)
+		}
+	}
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+func (s *Serve) plan(req *http.Request) (PlanResponse, int) {
+	response := PlanResponse{Status: "OK", Plan: cron.Plan{Changes: []cron.PlanChange{}}}
+	content := []byte{}
+	if req.Body != nil {
+		defer func() { req.Body.Close() }()
+		content, _ = ioutil.ReadAll(req.Body)
+	}
+	var jobs []cron.JobData
+	var err error
+	if len(content) > 0 {
+		jobs, err = cron.ParseJobs(content)
+	} else if len(s.JobsFile) > 0 {
+		jobs, err = cron.LoadJobsFile(s.JobsFile)
+	} else {
+		err = fmt.Errorf("Request body is mandatory when the jobs file is not configured")
+	}
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		return response, http.StatusBadRequest
+	}
+	if response.Plan, err = s.Cron.Plan(jobs); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		return response, http.StatusInternalServerError
+	}
+	return response, http.StatusOK
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobsFileTestSuite struct {
+	suite.Suite
+}
+
+func TestJobsFileUnitTestSuite(t *testing.T) {
+	s := new(JobsFileTestSuite)
+	suite.Run(t, s)
+}
+
+// JobsPlanHandler
+
+func (s *JobsFileTestSuite) Test_JobsPlanHandler_ReturnsPlanForRequestBody() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/plan", strings.NewReader("- name: my-job\n  image: alpine\n"))
+	expected := cron.Plan{Changes: []cron.PlanChange{{Name: "my-job", Action: "create"}}}
+	actualJobs := []cron.JobData{}
+	cMock := CronerMock{
+		PlanMock: func(jobs []cron.JobData) (cron.Plan, error) {
+			actualJobs = jobs
+			return expected, nil
+		},
+	}
+	actual := PlanResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobsPlanHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal([]cron.JobData{{Name: "my-job", Image: "alpine"}}, actualJobs)
+	s.Equal("OK", actual.Status)
+	s.Equal(expected, actual.Plan)
+}
+
+func (s *JobsFileTestSuite) Test_JobsPlanHandler_ReadsJobsFile_WhenBodyIsEmpty() {
+	file, _ := ioutil.TempFile("", "jobs")
+	defer os.Remove(file.Name())
+	file.WriteString(`[{"name": "my-job", "image": "alpine"}]`)
+	file.Close()
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/plan", nil)
+	actualJobs := []cron.JobData{}
+	cMock := CronerMock{
+		PlanMock: func(jobs []cron.JobData) (cron.Plan, error) {
+			actualJobs = jobs
+			return cron.Plan{}, nil
+		},
+	}
+
+	srv := Serve{Cron: cMock, JobsFile: file.Name()}
+	srv.JobsPlanHandler(s.responseWriter(nil, nil), req)
+
+	s.Equal([]cron.JobData{{Name: "my-job", Image: "alpine"}}, actualJobs)
+}
+
+func (s *JobsFileTestSuite) Test_JobsPlanHandler_ReturnsBadRequest_WhenThereAreNoDefinitions() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/plan", nil)
+	actualStatus := 0
+
+	srv := Serve{Cron: CronerMock{}}
+	srv.JobsPlanHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(400, actualStatus)
+}
+
+func (s *JobsFileTestSuite) Test_JobsPlanHandler_ReturnsInternalServerError_WhenPlanFails() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/plan", strings.NewReader("[]"))
+	cMock := CronerMock{
+		PlanMock: func(jobs []cron.JobData) (cron.Plan, error) {
+			return cron.Plan{}, fmt.Errorf("This is an error")
+		},
+	}
+	actual := PlanResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsPlanHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal(500, actualStatus)
+	s.Equal("NOK", actual.Status)
+}
+
+// JobsApplyHandler
+
+func (s *JobsFileTestSuite) Test_JobsApplyHandler_AppliesPlanAndAuditsChanges() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/apply", strings.NewReader("[]"))
+	job := cron.JobData{Name: "my-job", Image: "alpine"}
+	plan := cron.Plan{Changes: []cron.PlanChange{{Name: "my-job", Action: "update", Job: &job}}}
+	actualPlan := cron.Plan{}
+	cMock := CronerMock{
+		PlanMock: func(jobs []cron.JobData) (cron.Plan, error) {
+			return plan, nil
+		},
+		ApplyMock: func(plan cron.Plan) error {
+			actualPlan = plan
+			return nil
+		},
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{"my-job": {Name: "my-job", Image: "ubuntu"}}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+	actual := PlanResponse{}
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobsApplyHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal(plan, actualPlan)
+	s.Equal("OK", actual.Status)
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("update", entries[0].Action)
+	s.Equal(Change{Before: "ubuntu", After: "alpine"}, entries[0].Diff["image"])
+}
+
+func (s *JobsFileTestSuite) Test_JobsApplyHandler_ReturnsInternalServerError_WhenApplyFails() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/jobs/apply", strings.NewReader("[]"))
+	cMock := CronerMock{
+		PlanMock: func(jobs []cron.JobData) (cron.Plan, error) {
+			return cron.Plan{}, nil
+		},
+		ApplyMock: func(plan cron.Plan) error {
+			return fmt.Errorf("This is an error")
+		},
+	}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobsApplyHandler(s.responseWriter(nil, &actualStatus), req)
+
+	s.Equal(500, actualStatus)
+}
+
+// Util
+
+func (s *JobsFileTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"../cron"
+	"../docker"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type RunsResponse struct {
+	Status  string
+	Message string
+	Runs    []cron.Run
+}
+
+// JobRunHandler starts a run of a job right away, outside of its schedule.
+func (s *Serve) JobRunHandler(w http.ResponseWriter, req *http.Request) {
+	jobName := muxVars(req)["jobName"]
+	response := RunResponse{Status: "OK"}
+	status := http.StatusOK
+	run, err := s.Cron.TriggerJob(jobName, getCaller(req))
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = errorStatus(err)
+	} else {
+		response.Run = run
+		response.Message = fmt.Sprintf("Run %s of job %s was started", run.ID, jobName)
+	}
+	s.audit(req, "run", jobName, nil, nil, response.Status, response.Message)
+	writeJSON(w, status, response)
+}
+
+// JobSuspendHandler stops scheduling a job until it is resumed.
+func (s *Serve) JobSuspendHandler(w http.ResponseWriter, req *http.Request) {
+	jobName := muxVars(req)["jobName"]
+	response := Response{Status: "OK", Message: fmt.Sprintf("Job %s was suspended", jobName)}
+	status := http.StatusOK
+	if err := s.Cron.SuspendJob(jobName, getCaller(req)); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = errorStatus(err)
+	}
+	s.audit(req, "suspend", jobName, nil, nil, response.Status, response.Message)
+	writeJSON(w, status, response)
+}
+
+// JobResumeHandler schedules a suspended job again.
+func (s *Serve) JobResumeHandler(w http.ResponseWriter, req *http.Request) {
+	jobName := muxVars(req)["jobName"]
+	response := Response{Status: "OK", Message: fmt.Sprintf("Job %s was resumed", jobName)}
+	status := http.StatusOK
+	if err := s.Cron.ResumeJob(jobName); err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = errorStatus(err)
+	}
+	s.audit(req, "resume", jobName, nil, nil, response.Status, response.Message)
+	writeJSON(w, status, response)
+}
+
+// JobRunsHandler returns the latest runs of a job, newest first. The number of runs is limited with the limit parameter.
+func (s *Serve) JobRunsHandler(w http.ResponseWriter, req *http.Request) {
+	jobName := muxVars(req)["jobName"]
+	response := RunsResponse{Status: "OK", Runs: []cron.Run{}}
+	limit := 0
+	if value := req.URL.Query().Get("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			response.Status = "NOK"
+			response.Message = fmt.Sprintf("limit must be a positive number: %s", value)
+			writeJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		limit = parsed
+	}
+	status := http.StatusOK
+	runs, err := s.Cron.GetRuns(jobName, limit)
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = errorStatus(err)
+	} else if runs != nil {
+		response.Runs = runs
+	}
+	writeJSON(w, status, response)
+}
+
+// JobLogsHandler writes the logs of the service of a job as plain text.
+// With follow=true the response is streamed until the client disconnects.
+func (s *Serve) JobLogsHandler(w http.ResponseWriter, req *http.Request) {
+	jobName := muxVars(req)["jobName"]
+	follow := req.URL.Query().Get("follow") == "true"
+	tail := req.URL.Query().Get("tail")
+	if _, err := strconv.Atoi(tail); len(tail) > 0 && tail != "all" && err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Status: "NOK", Message: fmt.Sprintf("tail must be a number: %s", tail)})
+		return
+	}
+	if s.Logs == nil {
+		writeJSON(w, http.StatusNotFound, Response{Status: "NOK", Message: "logs are not available"})
+		return
+	}
+	logs, err := s.Logs.GetLogs(jobName, follow, tail)
+	if err == docker.ErrNoService {
+		message := fmt.Sprintf("job %s does not have a service with logs", jobName)
+		writeJSON(w, http.StatusNotFound, Response{Status: "NOK", Message: message})
+		return
+	} else if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Response{Status: "NOK", Message: err.Error()})
+		return
+	}
+	defer logs.Close()
+	httpWriterSetContentType(w, "text/plain; charset=utf-8")
+	if follow {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-req.Context().Done():
+				logs.Close()
+			case <-finished:
+			}
+		}()
+	}
+	io.Copy(flushWriter{w}, logs)
+}
+
+// errorStatus returns the HTTP status matching the error returned by the scheduler.
+func errorStatus(err error) int {
+	switch err.(type) {
+	case *cron.NotFoundError:
+		return http.StatusNotFound
+	case *cron.ConflictError:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+// flushWriter sends each write to the client right away.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"../cron"
+	"../docker"
+	"github.com/stretchr/testify/suite"
+)
+
+type RunsTestSuite struct {
+	suite.Suite
+	muxVarsOrig func(r *http.Request) map[string]string
+}
+
+func TestRunsUnitTestSuite(t *testing.T) {
+	s := new(RunsTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *RunsTestSuite) SetupTest() {
+	s.muxVarsOrig = muxVars
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+}
+
+func (s *RunsTestSuite) TearDownTest() {
+	muxVars = s.muxVarsOrig
+}
+
+// JobRunHandler
+
+func (s *RunsTestSuite) Test_JobRunHandler_TriggersJob() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/run", nil)
+	actualJob, actualBy := "", ""
+	cMock := CronerMock{
+		TriggerJobMock: func(jobName, by string) (cron.Run, error) {
+			actualJob, actualBy = jobName, by
+			return cron.Run{ID: "123", Job: jobName, Trigger: cron.TriggerManual, TriggeredBy: by}, nil
+		},
+	}
+	auditor, _ := NewAuditor("")
+	actual := RunResponse{}
+
+	srv := Serve{Cron: cMock, Auditor: auditor}
+	srv.JobRunHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("Run 123 of job my-job was started", actual.Message)
+	s.Equal("my-job", actualJob)
+	s.Equal("anonymous", actualBy)
+	s.Equal(cron.TriggerManual, actual.Run.Trigger)
+	entries, _ := auditor.Query(AuditFilter{})
+	s.Len(entries, 1)
+	s.Equal("run", entries[0].Action)
+}
+
+func (s *RunsTestSuite) Test_JobRunHandler_ReturnsNotFound_WhenJobDoesNotExist() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/run", nil)
+	cMock := CronerMock{
+		TriggerJobMock: func(jobName, by string) (cron.Run, error) {
+			return cron.Run{}, &cron.NotFoundError{Message: "job my-job does not exist"}
+		},
+	}
+	actual := RunResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobRunHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusNotFound, actualStatus)
+}
+
+// JobSuspendHandler
+
+func (s *RunsTestSuite) Test_JobSuspendHandler_SuspendsJob() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/suspend", nil)
+	actualJob, actualBy := "", ""
+	cMock := CronerMock{
+		SuspendJobMock: func(jobName, by string) error {
+			actualJob, actualBy = jobName, by
+			return nil
+		},
+	}
+	actual := Response{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobSuspendHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("Job my-job was suspended", actual.Message)
+	s.Equal("my-job", actualJob)
+	s.Equal("anonymous", actualBy)
+}
+
+func (s *RunsTestSuite) Test_JobSuspendHandler_ReturnsConflict_WhenJobIsSuspended() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/suspend", nil)
+	cMock := CronerMock{
+		SuspendJobMock: func(jobName, by string) error {
+			return &cron.ConflictError{Message: "job my-job is already suspended"}
+		},
+	}
+	actual := Response{}
+	actualStatus := 0
+
+	srv := Serve{Cron: cMock}
+	srv.JobSuspendHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusConflict, actualStatus)
+}
+
+// JobResumeHandler
+
+func (s *RunsTestSuite) Test_JobResumeHandler_ResumesJob() {
+	req, _ := http.NewRequest("POST", "/v1/docker-flow-cron/job/my-job/resume", nil)
+	actualJob := ""
+	cMock := CronerMock{
+		ResumeJobMock: func(jobName string) error {
+			actualJob = jobName
+			return nil
+		},
+	}
+	actual := Response{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobResumeHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("Job my-job was resumed", actual.Message)
+	s.Equal("my-job", actualJob)
+}
+
+// JobRunsHandler
+
+func (s *RunsTestSuite) Test_JobRunsHandler_ReturnsRuns() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job/executions?limit=2", nil)
+	actualLimit := 0
+	cMock := CronerMock{
+		GetRunsMock: func(jobName string, limit int) ([]cron.Run, error) {
+			actualLimit = limit
+			return []cron.Run{{ID: "2", Job: jobName}, {ID: "1", Job: jobName}}, nil
+		},
+	}
+	actual := RunsResponse{}
+
+	srv := Serve{Cron: cMock}
+	srv.JobRunsHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal(2, actualLimit)
+	s.Len(actual.Runs, 2)
+	s.Equal("2", actual.Runs[0].ID)
+}
+
+func (s *RunsTestSuite) Test_JobRunsHandler_ReturnsBadRequest_WhenLimitIsInvalid() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job/executions?limit=some", nil)
+	actual := RunsResponse{}
+	actualStatus := 0
+
+	srv := Serve{Cron: CronerMock{}}
+	srv.JobRunsHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusBadRequest, actualStatus)
+}
+
+// JobLogsHandler
+
+func (s *RunsTestSuite) Test_JobLogsHandler_WritesLogs() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job/logs?tail=10", nil)
+	actualJob, actualFollow, actualTail := "", true, ""
+	lMock := LoggerMock{
+		GetLogsMock: func(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+			actualJob, actualFollow, actualTail = jobName, follow, tail
+			return ioutil.NopCloser(strings.NewReader("line 1\nline 2\n")), nil
+		},
+	}
+	actual := ""
+	w := s.responseWriter(nil, nil)
+	w.WriteMock = func(content []byte) (int, error) {
+		actual += string(content)
+		return len(content), nil
+	}
+
+	srv := Serve{Logs: lMock}
+	srv.JobLogsHandler(w, req)
+
+	s.Equal("line 1\nline 2\n", actual)
+	s.Equal("my-job", actualJob)
+	s.False(actualFollow)
+	s.Equal("10", actualTail)
+}
+
+func (s *RunsTestSuite) Test_JobLogsHandler_ReturnsNotFound_WhenJobDoesNotHaveService() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job/logs", nil)
+	lMock := LoggerMock{
+		GetLogsMock: func(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+			return nil, docker.ErrNoService
+		},
+	}
+	actual := Response{}
+	actualStatus := 0
+
+	srv := Serve{Logs: lMock}
+	srv.JobLogsHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusNotFound, actualStatus)
+}
+
+func (s *RunsTestSuite) Test_JobLogsHandler_ReturnsInternalServerError_WhenLogsCannotBeRead() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job/logs", nil)
+	lMock := LoggerMock{
+		GetLogsMock: func(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+			return nil, fmt.Errorf("This is an error")
+		},
+	}
+	actual := Response{}
+	actualStatus := 0
+
+	srv := Serve{Logs: lMock}
+	srv.JobLogsHandler(s.responseWriter(&actual, &actualStatus), req)
+
+	s.Equal("This is an error", actual.Message)
+	s.Equal(http.StatusInternalServerError, actualStatus)
+}
+
+// Util
+
+func (s *RunsTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
+
+type LoggerMock struct {
+	GetLogsMock func(jobName string, follow bool, tail string) (io.ReadCloser, error)
+}
+
+func (m LoggerMock) GetLogs(jobName string, follow bool, tail string) (io.ReadCloser, error) {
+	return m.GetLogsMock(jobName, follow, tail)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"../cron"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxPreviewCount = 100
+
+type PreviewResponse struct {
+	Status      string
+	Message     string
+	Schedule    string
+	Resolved    string `json:",omitempty"`
+	Description string
+	Times       []time.Time
+	Skipped     []cron.SkippedTick `json:",omitempty"`
+}
+
+var timeNow = time.Now
+
+// SchedulePreviewHandler returns the next times a schedule fires together with its description.
+// The tz parameter sets the time zone of schedules without the `TZ=` or `CRON_TZ=` prefix.
+// The name and jitter parameters resolve `H` tokens and spread the times the way they are for the job with that name.
+// The calendars parameter lists the times that are skipped since they are excluded by the calendars.
+func (s *Serve) SchedulePreviewHandler(w http.ResponseWriter, req *http.Request) {
+	response := PreviewResponse{Status: "OK", Times: []time.Time{}}
+	status := http.StatusOK
+	query := req.URL.Query()
+	spec := strings.TrimSpace(query.Get("spec"))
+	count, err := previewCount(query.Get("count"))
+	location := time.Local
+	if err == nil && len(query.Get("tz")) > 0 {
+		if location, err = time.LoadLocation(query.Get("tz")); err != nil {
+			err = fmt.Errorf("unknown time zone %s", query.Get("tz"))
+		} else if !strings.HasPrefix(spec, "TZ=") && !strings.HasPrefix(spec, "CRON_TZ=") {
+			spec = fmt.Sprintf("TZ=%s %s", query.Get("tz"), spec)
+		}
+	}
+	if err == nil {
+		response.Schedule = cron.NormalizeSchedule(spec)
+		job := cron.JobData{Name: query.Get("name"), Schedule: spec, Jitter: query.Get("jitter")}
+		if len(query.Get("calendars")) > 0 {
+			job.ExcludeCalendars = strings.Split(query.Get("calendars"), ",")
+		}
+		if response.Resolved, err = cron.ResolveSchedule(spec, job.Name); err == nil {
+			response.Times, err = cron.PreviewJob(job, timeNow().In(location), count)
+		}
+		if err == nil {
+			err = s.checkCalendars(job.ExcludeCalendars)
+		}
+		if err == nil && len(job.ExcludeCalendars) > 0 {
+			response.Skipped = cron.SkippedTicks(s.Calendars, job.ExcludeCalendars, response.Times)
+		}
+	}
+	if err != nil {
+		response.Status = "NOK"
+		response.Message = err.Error()
+		status = http.StatusBadRequest
+	} else {
+		response.Description = cron.DescribeSchedule(response.Resolved)
+		if response.Resolved == response.Schedule {
+			response.Resolved = ""
+		}
+	}
+	httpWriterSetContentType(w, "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+	js, _ := json.Marshal(response)
+	w.Write(js)
+}
+
+func (s *Serve) checkCalendars(names []string) error {
+	for _, name := range names {
+		if _, ok := s.getCalendar(name); !ok {
+			return fmt.Errorf("calendar %s does not exist", name)
+		}
+	}
+	return nil
+}
+
+func previewCount(value string) (int, error) {
+	if len(value) == 0 {
+		return 5, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 || count > maxPreviewCount {
+		return 0, fmt.Errorf("count must be a number between 1 and %d", maxPreviewCount)
+	}
+	return count, nil
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"../cron"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+}
+
+func TestScheduleUnitTestSuite(t *testing.T) {
+	s := new(ScheduleTestSuite)
+	suite.Run(t, s)
+}
+
+func (s *ScheduleTestSuite) SetupTest() {
+	timeNow = func() time.Time {
+		return time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	}
+}
+
+func (s *ScheduleTestSuite) TearDownTest() {
+	timeNow = time.Now
+}
+
+// SchedulePreviewHandler
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsNextTimesAndDescription() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec="+url.QueryEscape("0 2 * * 1-5")+"&count=2&tz=UTC", nil)
+	actual := PreviewResponse{}
+
+	srv := Serve{}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("TZ=UTC 0 0 2 * * 1-5", actual.Schedule)
+	s.Equal("runs every weekday at 02:00 UTC", actual.Description)
+	s.Len(actual.Times, 2)
+	s.True(time.Date(2017, 5, 8, 2, 0, 0, 0, time.UTC).Equal(actual.Times[0]))
+	s.True(time.Date(2017, 5, 9, 2, 0, 0, 0, time.UTC).Equal(actual.Times[1]))
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsFiveTimes_WhenCountIsNotSet() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec=@hourly", nil)
+	actual := PreviewResponse{}
+
+	srv := Serve{}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, nil), req)
+
+	s.Len(actual.Times, 5)
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_UsesTimeZoneOfSpec() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec="+url.QueryEscape("CRON_TZ=Europe/Berlin 0 2 * * *")+"&count=1&tz=UTC", nil)
+	actual := PreviewResponse{}
+
+	srv := Serve{}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("TZ=Europe/Berlin 0 0 2 * * *", actual.Schedule)
+	s.True(time.Date(2017, 5, 6, 0, 0, 0, 0, time.UTC).Equal(actual.Times[0]))
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ResolvesHashTokens() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec="+url.QueryEscape("H H(1-3) * * *")+"&name=backup&count=2&tz=UTC", nil)
+	actual := PreviewResponse{}
+
+	srv := Serve{}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal("TZ=UTC 0 H H(1-3) * * *", actual.Schedule)
+	s.Regexp(`^TZ=UTC 0 \d+ [1-3] \* \* \*$`, actual.Resolved)
+	s.Len(actual.Times, 2)
+	s.Equal(24*time.Hour, actual.Times[1].Sub(actual.Times[0]))
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsBadRequest_WhenHashTokensHaveNoName() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec="+url.QueryEscape("H 2 * * *"), nil)
+	actual := PreviewResponse{}
+	status := 0
+
+	srv := Serve{}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, &status), req)
+
+	s.Equal("NOK", actual.Status)
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsSkippedTimes() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec="+url.QueryEscape("0 2 * * *")+"&count=3&tz=UTC&calendars=weekend", nil)
+	store, _ := cron.NewCalendarStore("")
+	store.Put(cron.Calendar{Name: "weekend", Weekdays: []string{"Saturday", "Sunday"}})
+	actual := PreviewResponse{}
+
+	srv := Serve{Calendars: store}
+	srv.SchedulePreviewHandler(s.responseWriter(&actual, nil), req)
+
+	s.Equal("OK", actual.Status)
+	s.Len(actual.Times, 3)
+	s.Len(actual.Skipped, 2)
+	s.Equal("excluded by calendar weekend: Saturday", actual.Skipped[0].Reason)
+	s.True(time.Date(2017, 5, 6, 2, 0, 0, 0, time.UTC).Equal(actual.Skipped[0].Time))
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsBadRequest_WhenCalendarDoesNotExist() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?spec=@daily&calendars=weekend", nil)
+	store, _ := cron.NewCalendarStore("")
+	status := 0
+
+	srv := Serve{Calendars: store}
+	srv.SchedulePreviewHandler(s.responseWriter(nil, &status), req)
+
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *ScheduleTestSuite) Test_SchedulePreviewHandler_ReturnsBadRequest_WhenParamsAreInvalid() {
+	for _, query := range []string{
+		"spec=" + url.QueryEscape("0 61 * * *"),
+		"spec=",
+		"spec=@daily&count=0",
+		"spec=@daily&count=1000",
+		"spec=@daily&tz=Mars/Olympus",
+	} {
+		req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/schedule/preview?"+query, nil)
+		actual := PreviewResponse{}
+		actualStatus := 0
+
+		srv := Serve{}
+		srv.SchedulePreviewHandler(s.responseWriter(&actual, &actualStatus), req)
+
+		s.Equal(400, actualStatus, query)
+		s.Equal("NOK", actual.Status, query)
+	}
+}
+
+// Util
+
+func (s *ScheduleTestSuite) responseWriter(actual interface{}, actualStatus *int) ResponseWriterMock {
+	return ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			if actualStatus != nil {
+				*actualStatus = header
+			}
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			if actual != nil {
+				json.Unmarshal(content, actual)
+			}
+			return 0, nil
+		},
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,10 +17,18 @@ import (
 var muxVars = mux.Vars
 
 type Serve struct {
-	IP      string
-	Port    string
-	Cron    cron.Croner
-	Service docker.Servicer
+	IP           string
+	Port         string
+	Cron         cron.Croner
+	Service      docker.Servicer
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	Roles        map[string]string
+	Auditor      Auditor
+	JobsFile     string
+	Calendars    cron.CalendarStore
+	Logs         docker.Logger
 }
 
 type Response struct {
@@ -30,6 +39,8 @@ type Response struct {
 
 type Execution struct {
 	ServiceId string
+	NodeId    string
+	Index     int
 	CreatedAt time.Time
 	Status    swarm.TaskStatus
 }
@@ -52,18 +63,32 @@ var New = func(ip, port, dockerHost string) (*Serve, error) {
 		return &Serve{}, err
 	}
 	cron, _ := cron.New(dockerHost)
+	auditor, _ := NewAuditor("")
 	return &Serve{
 		IP:      ip,
 		Port:    port,
 		Cron:    cron,
 		Service: service,
+		Auditor: auditor,
+		Logs:    service,
 	}, nil
 }
 
 func (s *Serve) Execute() error {
 	fmt.Printf("Starting Web server running on %s:%s\n", s.IP, s.Port)
 	address := fmt.Sprintf("%s:%s", s.IP, s.Port)
-	// TODO: Test routes
+	r := s.Router()
+	if len(s.CertFile) > 0 {
+		return s.executeTLS(address, r)
+	}
+	if err := httpListenAndServe(address, r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Router returns the handler of all API endpoints.
+func (s *Serve) Router() *mux.Router {
 	r := mux.NewRouter().StrictSlash(true)
 	//swarm-listener
 	r.HandleFunc("/v1/docker-flow-cron/job/create", s.JobPutHandler).Methods("GET")
@@ -74,7 +99,43 @@ func (s *Serve) Execute() error {
 	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}", s.JobDetailsHandler).Methods("GET")
 	// TODO: Document
 	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}", s.JobDeleteHandler).Methods("DELETE")
-	if err := httpListenAndServe(address, r); err != nil {
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/executions", s.JobRunsHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/executions/{id}/cancel", s.JobCancelHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/run", s.JobRunHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/suspend", s.JobSuspendHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/resume", s.JobResumeHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/job/{jobName}/logs", s.JobLogsHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/audit", s.AuditGetHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/jobs/plan", s.JobsPlanHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/jobs/apply", s.JobsApplyHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/jobs/import", s.JobsImportHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/jobs/export", s.JobsExportHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/schedule/preview", s.SchedulePreviewHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/admin/pause", s.AdminPauseHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/admin/resume", s.AdminResumeHandler).Methods("POST")
+	r.HandleFunc("/v1/docker-flow-cron/admin/maintenance", s.AdminMaintenanceHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/calendars", s.CalendarsGetHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/calendars/{calendarName}", s.CalendarGetHandler).Methods("GET")
+	r.HandleFunc("/v1/docker-flow-cron/calendars/{calendarName}", s.CalendarPutHandler).Methods("PUT")
+	r.HandleFunc("/v1/docker-flow-cron/calendars/{calendarName}", s.CalendarDeleteHandler).Methods("DELETE")
+	return r
+}
+
+func (s *Serve) executeTLS(address string, handler http.Handler) error {
+	certs, err := newCertStore(s.CertFile, s.KeyFile, s.ClientCAFile)
+	if err != nil {
+		return err
+	}
+	certs.reloadOnSignal()
+	if len(s.Roles) > 0 {
+		handler = s.authorize(handler)
+	}
+	srv := &http.Server{
+		Addr:      address,
+		Handler:   handler,
+		TLSConfig: certs.tlsConfig(),
+	}
+	if err := httpListenAndServeTLS(srv); err != nil {
 		return err
 	}
 	return nil
@@ -84,18 +145,20 @@ func (s *Serve) JobDeleteHandler(w http.ResponseWriter, req *http.Request) {
 	jobName := req.URL.Query().Get("serviceName")
 	if muxVars(req)["jobName"] != "" {
 		jobName = muxVars(req)["jobName"]
-	} 
+	}
 
 	response := ResponseDetails{
 		Status:  "OK",
 		Message: fmt.Sprintf("%s was deleted", jobName),
 	}
+	before := s.getJobBeforeChange(jobName)
 	err := s.Cron.RemoveJob(jobName)
 	if err != nil {
 		response.Status = "NOK"
 		response.Message = err.Error()
 		w.WriteHeader(http.StatusInternalServerError)
 	}
+	s.audit(req, "delete", jobName, before, nil, response.Status, response.Message)
 	js, _ := json.Marshal(response)
 	w.Write(js)
 }
@@ -117,9 +180,13 @@ func (s *Serve) JobDetailsHandler(w http.ResponseWriter, req *http.Request) {
 	} else {
 		index := len(services) - 1
 		if index < 0 {
-			response.Status = "NOK"
-			response.Message = "Could not find the job"
-			w.WriteHeader(http.StatusNotFound)
+			if job, ok := s.localJob(jobName); ok {
+				response.Job = job
+			} else {
+				response.Status = "NOK"
+				response.Message = "Could not find the job"
+				w.WriteHeader(http.StatusNotFound)
+			}
 		} else {
 			service := services[index]
 			tasks, err := s.Service.GetTasks(jobName)
@@ -132,12 +199,18 @@ func (s *Serve) JobDetailsHandler(w http.ResponseWriter, req *http.Request) {
 				for _, t := range tasks {
 					execution := Execution{
 						ServiceId: t.ServiceID,
+						NodeId:    t.NodeID,
+						Index:     t.Slot,
 						CreatedAt: t.CreatedAt,
 						Status:    t.Status,
 					}
 					executions = append(executions, execution)
 				}
 				response.Job = s.getJob(service)
+				if s.Cron != nil {
+					state := s.Cron.GetState(jobName)
+					response.Job.State = &state
+				}
 				response.Executions = executions
 			}
 		}
@@ -171,6 +244,9 @@ func (s *Serve) JobPutHandler(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		response.Status = "NOK"
 		response.Message = "Request body is mandatory"
+	} else if req.Method == "GET" && len(req.URL.Query().Get("cron.run")) > 0 {
+		// Services created for single runs of jobs in the per-run execution mode are not jobs
+		response.Message = fmt.Sprintf("%s is a run of job %s", req.URL.Query().Get("serviceName"), req.URL.Query().Get("cron.name"))
 	} else {
 		defer func() { req.Body.Close() }()
 		body, _ := ioutil.ReadAll(req.Body)
@@ -182,28 +258,76 @@ func (s *Serve) JobPutHandler(w http.ResponseWriter, req *http.Request) {
 			data.Image = req.URL.Query().Get("cron.image")
 			data.Command = req.URL.Query().Get("cron.command")
 			data.Schedule = req.URL.Query().Get("cron.schedule")
+			data.RunAt = req.URL.Query().Get("cron.run-at")
+			data.TTL = req.URL.Query().Get("cron.ttl")
+			data.StartingDeadline = req.URL.Query().Get("cron.starting-deadline")
+			data.CatchUp = req.URL.Query().Get("cron.catch-up")
+			data.CatchUpLimit, _ = strconv.Atoi(req.URL.Query().Get("cron.catch-up-limit"))
+			data.Jitter = req.URL.Query().Get("cron.jitter")
+			data.NotBefore = req.URL.Query().Get("cron.not-before")
+			data.NotAfter = req.URL.Query().Get("cron.not-after")
+			data.MaxRuns, _ = strconv.Atoi(req.URL.Query().Get("cron.max-runs"))
+			data.OnExpiry = req.URL.Query().Get("cron.on-expiry")
+			if calendars := req.URL.Query().Get("cron.exclude-calendars"); len(calendars) > 0 {
+				data.ExcludeCalendars = strings.Split(calendars, ",")
+			}
+			data.ExecutionMode = req.URL.Query().Get("cron.execution-mode")
+			data.Retention, _ = strconv.Atoi(req.URL.Query().Get("cron.retention"))
+			data.MaxConcurrent, _ = strconv.Atoi(req.URL.Query().Get("cron.max-concurrent"))
+			data.TotalCompletions, _ = strconv.Atoi(req.URL.Query().Get("cron.total-completions"))
+			data.Mode = req.URL.Query().Get("cron.mode")
+			data.Parallelism, _ = strconv.Atoi(req.URL.Query().Get("cron.parallelism"))
+			data.Completions, _ = strconv.Atoi(req.URL.Query().Get("cron.completions"))
+			if nodeLabels := req.URL.Query().Get("cron.node-labels"); len(nodeLabels) > 0 {
+				data.NodeLabels = strings.Split(nodeLabels, ",")
+			}
 			data.Created = true
 		} else {
 			jobName := muxVars(req)["jobName"]
 			json.Unmarshal(body, &data)
 			data.Name = jobName
 			data.Created = false
+			// Only the jobs file marks jobs as managed
+			data.ManagedBy = ""
 		}
 
 		response.Job = data
+		before := s.getJobBeforeChange(data.Name)
 		if err := s.Cron.AddJob(data); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			if _, ok := err.(*cron.PolicyError); ok {
+				w.WriteHeader(http.StatusForbidden)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
 			response.Status = "NOK"
 			response.Message = err.Error()
 		} else {
 			response.Message = fmt.Sprintf("Job %s has been scheduled", data.Name)
 		}
+		action := "create"
+		if before != nil {
+			action = "update"
+		}
+		s.audit(req, action, data.Name, before, &data, response.Status, response.Message)
 	}
 	httpWriterSetContentType(w, "application/json")
 	js, _ := json.Marshal(response)
 	w.Write(js)
 }
 
+// localJob returns a job that is not stored as a service, e.g. a job of the http type.
+func (s *Serve) localJob(jobName string) (cron.JobData, bool) {
+	if s.Cron == nil {
+		return cron.JobData{}, false
+	}
+	jobs, err := s.Cron.GetJobs()
+	if err != nil {
+		return cron.JobData{}, false
+	}
+	job, ok := jobs[jobName]
+	return job, ok
+}
+
 // TODO: Remove when JobDetailsHandler is refactored to use cron
 func (s *Serve) getJob(service swarm.Service) cron.JobData {
 	command := ""
@@ -216,10 +340,11 @@ func (s *Serve) getJob(service swarm.Service) cron.JobData {
 	}
 	name := service.Spec.Annotations.Labels["com.df.cron.name"]
 	return cron.JobData{
-		Name:     name,
+		Name:        name,
 		ServiceName: service.Spec.Name,
-		Image:    service.Spec.TaskTemplate.ContainerSpec.Image,
-		Command:  service.Spec.Annotations.Labels["com.df.cron.command"],
-		Schedule: service.Spec.Annotations.Labels["com.df.cron.schedule"],
+		Image:       service.Spec.TaskTemplate.ContainerSpec.Image,
+		Command:     service.Spec.Annotations.Labels["com.df.cron.command"],
+		Schedule:    service.Spec.Annotations.Labels["com.df.cron.schedule"],
+		ManagedBy:   service.Spec.Annotations.Labels["com.df.cron.managed-by"],
 	}
 }
@@ -119,6 +119,28 @@ func (s *ServerTestSuite) Test_JobPutHandler_InvokesCronAddJob() {
 	s.Equal(expectedData, actualData)
 }
 
+func (s *ServerTestSuite) Test_JobPutHandler_IgnoresManagedBy() {
+	muxVarsOrig := muxVars
+	defer func() { muxVars = muxVarsOrig }()
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+	js, _ := json.Marshal(cron.JobData{Image: "my-image", Schedule: "@yearly", ManagedBy: `x" ; rm -rf / #`})
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/job", strings.NewReader(string(js)))
+	actualData := cron.JobData{}
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			actualData = data
+			return nil
+		},
+	}
+
+	srv := Serve{Cron: cMock}
+	srv.JobPutHandler(s.ResponseWriter, req)
+
+	s.Empty(actualData.ManagedBy)
+}
+
 // JobPutHandler (GET)
 
 func (s *ServerTestSuite) Test_JobPutHandler_GetRequest_ReturnsJobDetails() {
@@ -163,6 +185,38 @@ func (s *ServerTestSuite) Test_JobPutHandler_GetRequest_ReturnsJobDetails() {
 	s.Equal(expected, actual)
 }
 
+func (s *ServerTestSuite) Test_JobPutHandler_GetRequest_IgnoresRunServices() {
+	req, _ := http.NewRequest(
+		"GET",
+		"/v1/docker-flow-cron/job/create?serviceName=my-job-123&cron=true&cron.image=alpine&cron.name=my-job&cron.schedule=%40every+10s&cron.run=123",
+		bytes.NewBufferString(`{}`),
+	)
+	actual := ResponseDetails{}
+	rwMock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			json.Unmarshal(content, &actual)
+			return 0, nil
+		},
+	}
+	added := false
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			added = true
+			return nil
+		},
+	}
+	srv := Serve{Service: s.Service, Cron: cMock}
+	srv.JobPutHandler(rwMock, req)
+
+	s.False(added)
+	s.Equal("OK", actual.Status)
+	s.Equal("my-job-123 is a run of job my-job", actual.Message)
+}
+
 func (s *ServerTestSuite) Test_JobPutHandler_ReturnsBadRequestWhenBodyIsNil() {
 	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/job", nil)
 	cMock := CronerMock{
@@ -225,6 +279,36 @@ func (s *ServerTestSuite) Test_JobPutHandler_InvokesInternalServerError_WhenAddJ
 	s.Equal(500, actualStatus)
 }
 
+func (s *ServerTestSuite) Test_JobPutHandler_ReturnsForbidden_WhenPolicyIsViolated() {
+	js, _ := json.Marshal(cron.JobData{Name: "my-job", Image: "my-image", Schedule: "@yearly"})
+	req, _ := http.NewRequest("PUT", "/v1/docker-flow-cron/job", strings.NewReader(string(js)))
+	cMock := CronerMock{
+		AddJobMock: func(data cron.JobData) error {
+			return &cron.PolicyError{Rule: "deniedImages", Message: "image my-image is denied"}
+		},
+	}
+	actual := ResponseDetails{}
+	actualStatus := 0
+	mock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			actualStatus = header
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			json.Unmarshal(content, &actual)
+			return 0, nil
+		},
+	}
+
+	srv := Serve{Cron: cMock}
+	srv.JobPutHandler(mock, req)
+
+	s.Equal(403, actualStatus)
+	s.Contains(actual.Message, "deniedImages")
+}
+
 // JobGetHandler
 
 func (s *ServerTestSuite) Test_JobGetHandler_ReturnsListOfServices() {
@@ -456,6 +540,101 @@ func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsJobDetails() {
 	s.NotNil(actual.Executions[0].ServiceId)
 }
 
+func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsJobState() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job", nil)
+	muxVarsOrig := muxVars
+	defer func() { muxVars = muxVarsOrig }()
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+	service := swarm.Service{}
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: "alpine"}
+	service.Spec.Annotations.Labels = map[string]string{"com.df.cron.name": "my-job"}
+	sMock := ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{service}, nil
+		},
+		GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+			return []swarm.Task{}, nil
+		},
+	}
+	next := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	expected := cron.JobState{
+		NextRun: &next,
+		LastRun: &cron.Run{ID: "1", Job: "my-job", Status: cron.RunSucceeded, Duration: "10s"},
+	}
+	actualJobName := ""
+	cMock := CronerMock{
+		GetStateMock: func(jobName string) cron.JobState {
+			actualJobName = jobName
+			return expected
+		},
+	}
+	actual := ResponseDetails{}
+	rwMock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			json.Unmarshal(content, &actual)
+			return 0, nil
+		},
+	}
+
+	srv := Serve{Service: sMock, Cron: cMock}
+	srv.JobDetailsHandler(rwMock, req)
+
+	s.Equal("my-job", actualJobName)
+	s.True(next.Equal(*actual.Job.State.NextRun))
+	s.Equal(*expected.LastRun, *actual.Job.State.LastRun)
+}
+
+func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsStatusOfEachTask() {
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job", nil)
+	muxVarsOrig := muxVars
+	defer func() { muxVars = muxVarsOrig }()
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+	service := swarm.Service{}
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: "alpine"}
+	service.Spec.Annotations.Labels = map[string]string{"com.df.cron.name": "my-job"}
+	tasks := []swarm.Task{
+		{ServiceID: "my-service", NodeID: "node-1", Slot: 1, Status: swarm.TaskStatus{State: swarm.TaskStateComplete}},
+		{ServiceID: "my-service", NodeID: "node-2", Slot: 2, Status: swarm.TaskStatus{State: swarm.TaskStateFailed, Err: "exit code 1"}},
+	}
+	sMock := ServicerMock{
+		GetServicesMock: func(jobName string) ([]swarm.Service, error) {
+			return []swarm.Service{service}, nil
+		},
+		GetTasksMock: func(jobName string) ([]swarm.Task, error) {
+			return tasks, nil
+		},
+	}
+	actual := ResponseDetails{}
+	rwMock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			json.Unmarshal(content, &actual)
+			return 0, nil
+		},
+	}
+
+	srv := Serve{Service: sMock}
+	srv.JobDetailsHandler(rwMock, req)
+
+	s.Len(actual.Executions, 2)
+	s.Equal(1, actual.Executions[0].Index)
+	s.Equal("node-1", actual.Executions[0].NodeId)
+	s.Equal(swarm.TaskStateComplete, actual.Executions[0].Status.State)
+	s.Equal(2, actual.Executions[1].Index)
+	s.Equal("exit code 1", actual.Executions[1].Status.Err)
+}
+
 func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsError_WhenGetServicesFail() {
 	message := "This is an get services error"
 	mock := ServicerMock{
@@ -525,6 +704,48 @@ func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsError_WhenServiceDoesNot
 	s.Equal(404, actualStatus)
 }
 
+func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsJob_WhenJobIsNotService() {
+	muxVarsOrig := muxVars
+	defer func() { muxVars = muxVarsOrig }()
+	muxVars = func(r *http.Request) map[string]string {
+		return map[string]string{"jobName": "my-job"}
+	}
+	actual := ResponseDetails{}
+	actualStatus := 0
+	rwMock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			actualStatus = header
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			json.Unmarshal(content, &actual)
+			return 0, nil
+		},
+	}
+	req, _ := http.NewRequest("GET", "/v1/docker-flow-cron/job/my-job", nil)
+	job := cron.JobData{
+		Name:     "my-job",
+		Schedule: "@daily",
+		Type:     cron.TypeHTTP,
+		HTTP:     &cron.HTTPRequest{URL: "http://example.com"},
+	}
+	cronMock := CronerMock{
+		GetJobsMock: func() (map[string]cron.JobData, error) {
+			return map[string]cron.JobData{"my-job": job}, nil
+		},
+	}
+
+	srv := Serve{Service: s.Service, Cron: cronMock}
+	srv.JobDetailsHandler(rwMock, req)
+
+	s.Equal("OK", actual.Status)
+	s.Equal(job, actual.Job)
+	s.Equal([]Execution{}, actual.Executions)
+	s.Equal(0, actualStatus)
+}
+
 func (s *ServerTestSuite) Test_JobDetailsHandler_ReturnsError_WhenGetTasksFail() {
 	message := "This is an get tasks error"
 	mock := ServicerMock{
@@ -590,6 +811,17 @@ type CronerMock struct {
 	GetJobsMock        func() (map[string]cron.JobData, error)
 	RemoveJobMock      func(jobName string) error
 	RescheduleJobsMock func() error
+	PlanMock           func(jobs []cron.JobData) (cron.Plan, error)
+	ApplyMock          func(plan cron.Plan) error
+	GetStateMock       func(jobName string) cron.JobState
+	PauseMock          func(until *time.Time, by string) error
+	ResumeMock         func() error
+	GetMaintenanceMock func() cron.Maintenance
+	CancelRunMock      func(jobName, runID, by string) (cron.Run, error)
+	TriggerJobMock     func(jobName, by string) (cron.Run, error)
+	SuspendJobMock     func(jobName, by string) error
+	ResumeJobMock      func(jobName string) error
+	GetRunsMock        func(jobName string, limit int) ([]cron.Run, error)
 }
 
 func (m CronerMock) AddJob(data cron.JobData) error {
@@ -612,10 +844,64 @@ func (m CronerMock) RescheduleJobs() error {
 	return m.RescheduleJobsMock()
 }
 
+func (m CronerMock) Plan(jobs []cron.JobData) (cron.Plan, error) {
+	return m.PlanMock(jobs)
+}
+
+func (m CronerMock) Apply(plan cron.Plan) error {
+	return m.ApplyMock(plan)
+}
+
+func (m CronerMock) GetState(jobName string) cron.JobState {
+	if m.GetStateMock == nil {
+		return cron.JobState{}
+	}
+	return m.GetStateMock(jobName)
+}
+
+func (m CronerMock) Pause(until *time.Time, by string) error {
+	return m.PauseMock(until, by)
+}
+
+func (m CronerMock) Resume() error {
+	return m.ResumeMock()
+}
+
+func (m CronerMock) GetMaintenance() cron.Maintenance {
+	return m.GetMaintenanceMock()
+}
+
+func (m CronerMock) CancelRun(jobName, runID, by string) (cron.Run, error) {
+	return m.CancelRunMock(jobName, runID, by)
+}
+
+func (m CronerMock) TriggerJob(jobName, by string) (cron.Run, error) {
+	return m.TriggerJobMock(jobName, by)
+}
+
+func (m CronerMock) SuspendJob(jobName, by string) error {
+	return m.SuspendJobMock(jobName, by)
+}
+
+func (m CronerMock) ResumeJob(jobName string) error {
+	return m.ResumeJobMock(jobName)
+}
+
+func (m CronerMock) GetRuns(jobName string, limit int) ([]cron.Run, error) {
+	return m.GetRunsMock(jobName, limit)
+}
+
 type ServicerMock struct {
-	GetServicesMock    func(jobName string) ([]swarm.Service, error)
-	GetTasksMock       func(jobName string) ([]swarm.Task, error)
-	RemoveServicesMock func(jobName string) error
+	GetServicesMock      func(jobName string) ([]swarm.Service, error)
+	GetTasksMock         func(jobName string) ([]swarm.Task, error)
+	RemoveServicesMock   func(jobName string) error
+	ScaleServiceMock     func(serviceName string, replicas uint64) error
+	RunServiceMock       func(serviceName, runName string, labels map[string]string) (string, error)
+	GetRunServicesMock   func(jobName string) ([]swarm.Service, error)
+	RemoveServiceMock    func(serviceName string) error
+	JobModeSupportedMock func() bool
+	RunJobMock           func(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error)
+	CreateServiceMock    func(spec swarm.ServiceSpec) error
 }
 
 func (m ServicerMock) GetServices(jobName string) ([]swarm.Service, error) {
@@ -629,3 +915,31 @@ func (m ServicerMock) GetTasks(jobName string) ([]swarm.Task, error) {
 func (m ServicerMock) RemoveServices(jobName string) error {
 	return m.RemoveServicesMock(jobName)
 }
+
+func (m ServicerMock) ScaleService(serviceName string, replicas uint64) error {
+	return m.ScaleServiceMock(serviceName, replicas)
+}
+
+func (m ServicerMock) RunService(serviceName, runName string, labels map[string]string) (string, error) {
+	return m.RunServiceMock(serviceName, runName, labels)
+}
+
+func (m ServicerMock) GetRunServices(jobName string) ([]swarm.Service, error) {
+	return m.GetRunServicesMock(jobName)
+}
+
+func (m ServicerMock) RemoveService(serviceName string) error {
+	return m.RemoveServiceMock(serviceName)
+}
+
+func (m ServicerMock) JobModeSupported() bool {
+	return m.JobModeSupportedMock()
+}
+
+func (m ServicerMock) RunJob(serviceName string, maxConcurrent, totalCompletions uint64) (uint64, error) {
+	return m.RunJobMock(serviceName, maxConcurrent, totalCompletions)
+}
+
+func (m ServicerMock) CreateService(spec swarm.ServiceSpec) error {
+	return m.CreateServiceMock(spec)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+)
+
+var httpListenAndServeTLS = func(srv *http.Server) error {
+	return srv.ListenAndServeTLS("", "")
+}
+
+// certStore holds the server certificate and the client CA pool so that both can be
+// replaced while the server is running.
+type certStore struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	mu           sync.RWMutex
+	cert         *tls.Certificate
+	clientCAs    *x509.CertPool
+}
+
+func newCertStore(certFile, keyFile, clientCAFile string) (*certStore, error) {
+	cs := &certStore{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := cs.reload(); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (cs *certStore) reload() error {
+	cert, err := tls.LoadX509KeyPair(cs.certFile, cs.keyFile)
+	if err != nil {
+		return err
+	}
+	var pool *x509.CertPool
+	if len(cs.clientCAFile) > 0 {
+		pem, err := ioutil.ReadFile(cs.clientCAFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", cs.clientCAFile)
+		}
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.cert = &cert
+	cs.clientCAs = pool
+	return nil
+}
+
+func (cs *certStore) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cs.mu.RLock()
+			defer cs.mu.RUnlock()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cs.cert},
+				ClientAuth:   tls.NoClientCert,
+			}
+			if cs.clientCAs != nil {
+				config.ClientCAs = cs.clientCAs
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return config, nil
+		},
+	}
+}
+
+// reloadOnSignal reloads certificates every time the process receives SIGHUP.
+// Only the certificates are replaced; the scheduler is not touched.
+func (cs *certStore) reloadOnSignal() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	go func() {
+		for range sig {
+			fmt.Println("Reloading TLS certificates")
+			if err := cs.reload(); err != nil {
+				fmt.Println("Could not reload TLS certificates:", err.Error())
+			}
+		}
+	}()
+}
+
+// ParseRoles parses role mappings in the format "subject=role;subject=role".
+// A subject is either a client certificate common name or its full distinguished name,
+// e.g. "ci-bot=write;CN=grafana,O=Ops=read".
+func ParseRoles(value string) (map[string]string, error) {
+	roles := map[string]string{}
+	for _, mapping := range strings.Split(value, ";") {
+		mapping = strings.TrimSpace(mapping)
+		if len(mapping) == 0 {
+			continue
+		}
+		i := strings.LastIndex(mapping, "=")
+		if i <= 0 {
+			return roles, fmt.Errorf("invalid role mapping %s", mapping)
+		}
+		role := strings.TrimSpace(mapping[i+1:])
+		if role != RoleRead && role != RoleWrite {
+			return roles, fmt.Errorf("unknown role %s in mapping %s", role, mapping)
+		}
+		roles[strings.TrimSpace(mapping[:i])] = role
+	}
+	return roles, nil
+}
+
+func (s *Serve) getRole(req *http.Request) string {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	subject := req.TLS.PeerCertificates[0].Subject
+	if role, ok := s.Roles[subject.String()]; ok {
+		return role
+	}
+	return s.Roles[subject.CommonName]
+}
+
+// isReadOnly returns false for the swarm-listener endpoints since they modify jobs through GET requests.
+func isReadOnly(req *http.Request) bool {
+	if req.Method != "GET" {
+		return false
+	}
+	path := strings.TrimRight(req.URL.Path, "/")
+	return !strings.HasSuffix(path, "/job/create") && !strings.HasSuffix(path, "/job/remove")
+}
+
+// authorize rejects requests from clients whose certificate subject is not mapped to a role.
+// Clients with the read role are limited to read-only requests.
+func (s *Serve) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		role := s.getRole(req)
+		if role == RoleWrite || (role == RoleRead && isReadOnly(req)) {
+			next.ServeHTTP(w, req)
+			return
+		}
+		message := "Client certificate is not mapped to any role"
+		if len(role) > 0 {
+			message = fmt.Sprintf("Role %s is not allowed to send %s requests", role, req.Method)
+		}
+		writeForbidden(w, message)
+	})
+}
+
+func writeForbidden(w http.ResponseWriter, message string) {
+	httpWriterSetContentType(w, "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	js, _ := json.Marshal(Response{Status: "NOK", Message: message})
+	w.Write(js)
+}
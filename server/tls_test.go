@@ -0,0 +1,214 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TLSTestSuite struct {
+	suite.Suite
+	Dir string
+}
+
+func (s *TLSTestSuite) SetupTest() {
+	s.Dir, _ = ioutil.TempDir("", "df-cron-tls")
+}
+
+func (s *TLSTestSuite) TearDownTest() {
+	os.RemoveAll(s.Dir)
+}
+
+func TestTLSUnitTestSuite(t *testing.T) {
+	s := new(TLSTestSuite)
+	suite.Run(t, s)
+}
+
+// Execute
+
+func (s *TLSTestSuite) Test_Execute_InvokesHTTPListenAndServeTLS_WhenCertFileIsSet() {
+	orig := httpListenAndServeTLS
+	defer func() { httpListenAndServeTLS = orig }()
+	certFile, keyFile := s.writeCert("server", 1)
+	var actual *http.Server
+	httpListenAndServeTLS = func(srv *http.Server) error {
+		actual = srv
+		return nil
+	}
+
+	srv := Serve{IP: "myIp", Port: "1234", CertFile: certFile, KeyFile: keyFile}
+	err := srv.Execute()
+
+	s.NoError(err)
+	s.Equal("myIp:1234", actual.Addr)
+	s.NotNil(actual.TLSConfig)
+}
+
+func (s *TLSTestSuite) Test_Execute_ReturnsError_WhenCertFileDoesNotExist() {
+	srv := Serve{IP: "myIp", Port: "1234", CertFile: "/this/does/not/exist", KeyFile: "/neither/does/this"}
+
+	err := srv.Execute()
+
+	s.Error(err)
+}
+
+// certStore
+
+func (s *TLSTestSuite) Test_TLSConfig_RequiresClientCert_WhenClientCAFileIsSet() {
+	certFile, keyFile := s.writeCert("server", 1)
+	cs, _ := newCertStore(certFile, keyFile, certFile)
+
+	config, _ := cs.tlsConfig().GetConfigForClient(nil)
+
+	s.Equal(tls.RequireAndVerifyClientCert, config.ClientAuth)
+	s.NotNil(config.ClientCAs)
+}
+
+func (s *TLSTestSuite) Test_TLSConfig_DoesNotRequireClientCert_WhenClientCAFileIsEmpty() {
+	certFile, keyFile := s.writeCert("server", 1)
+	cs, _ := newCertStore(certFile, keyFile, "")
+
+	config, _ := cs.tlsConfig().GetConfigForClient(nil)
+
+	s.Equal(tls.NoClientCert, config.ClientAuth)
+}
+
+func (s *TLSTestSuite) Test_Reload_ReplacesCertificate() {
+	certFile, keyFile := s.writeCert("server", 1)
+	cs, _ := newCertStore(certFile, keyFile, "")
+	config := cs.tlsConfig()
+	before, _ := config.GetConfigForClient(nil)
+
+	s.writeCert("server", 2)
+	err := cs.reload()
+	after, _ := config.GetConfigForClient(nil)
+
+	s.NoError(err)
+	s.NotEqual(before.Certificates[0].Certificate[0], after.Certificates[0].Certificate[0])
+}
+
+func (s *TLSTestSuite) Test_Reload_KeepsCertificate_WhenFilesAreInvalid() {
+	certFile, keyFile := s.writeCert("server", 1)
+	cs, _ := newCertStore(certFile, keyFile, "")
+	before, _ := cs.tlsConfig().GetConfigForClient(nil)
+
+	ioutil.WriteFile(certFile, []byte("not a cert"), 0600)
+	err := cs.reload()
+	after, _ := cs.tlsConfig().GetConfigForClient(nil)
+
+	s.Error(err)
+	s.Equal(before.Certificates[0].Certificate[0], after.Certificates[0].Certificate[0])
+}
+
+// ParseRoles
+
+func (s *TLSTestSuite) Test_ParseRoles_ReturnsRoles() {
+	expected := map[string]string{
+		"ci-bot":           RoleWrite,
+		"CN=grafana,O=Ops": RoleRead,
+	}
+
+	actual, err := ParseRoles("ci-bot=write; CN=grafana,O=Ops=read;")
+
+	s.NoError(err)
+	s.Equal(expected, actual)
+}
+
+func (s *TLSTestSuite) Test_ParseRoles_ReturnsError_WhenRoleIsUnknown() {
+	_, err := ParseRoles("ci-bot=root")
+
+	s.Error(err)
+}
+
+// authorize
+
+func (s *TLSTestSuite) Test_Authorize_AllowsWriteRole() {
+	srv := Serve{Roles: map[string]string{"ci-bot": RoleWrite}}
+
+	actual := s.authorize(srv, "PUT", "/v1/docker-flow-cron/job/my-job", "ci-bot")
+
+	s.Equal(http.StatusOK, actual)
+}
+
+func (s *TLSTestSuite) Test_Authorize_AllowsReadRoleToGetJobs() {
+	srv := Serve{Roles: map[string]string{"CN=grafana,O=Ops": RoleRead}}
+
+	actual := s.authorize(srv, "GET", "/v1/docker-flow-cron/job", "grafana", "Ops")
+
+	s.Equal(http.StatusOK, actual)
+}
+
+func (s *TLSTestSuite) Test_Authorize_ForbidsReadRoleToModifyJobs() {
+	srv := Serve{Roles: map[string]string{"grafana": RoleRead}}
+
+	s.Equal(http.StatusForbidden, s.authorize(srv, "DELETE", "/v1/docker-flow-cron/job/my-job", "grafana"))
+	s.Equal(http.StatusForbidden, s.authorize(srv, "GET", "/v1/docker-flow-cron/job/create", "grafana"))
+}
+
+func (s *TLSTestSuite) Test_Authorize_ForbidsUnmappedSubjects() {
+	srv := Serve{Roles: map[string]string{"ci-bot": RoleWrite}}
+
+	actual := s.authorize(srv, "GET", "/v1/docker-flow-cron/job", "someone-else")
+
+	s.Equal(http.StatusForbidden, actual)
+}
+
+// Util
+
+func (s *TLSTestSuite) authorize(srv Serve, method, url, commonName string, organization ...string) int {
+	actual := http.StatusOK
+	rwMock := ResponseWriterMock{
+		WriteHeaderMock: func(header int) {
+			actual = header
+		},
+		HeaderMock: func() http.Header {
+			return http.Header{}
+		},
+		WriteMock: func(content []byte) (int, error) {
+			return 0, nil
+		},
+	}
+	req, _ := http.NewRequest(method, url, nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: commonName, Organization: organization}},
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	srv.authorize(next).ServeHTTP(rwMock, req)
+	return actual
+}
+
+func (s *TLSTestSuite) writeCert(commonName string, serial int64) (string, string) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+	certFile := filepath.Join(s.Dir, fmt.Sprintf("%s.pem", commonName))
+	keyFile := filepath.Join(s.Dir, fmt.Sprintf("%s-key.pem", commonName))
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return certFile, keyFile
+}